@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/application"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+// WithActor attributes the operations of each request to the caller named in
+// the X-Actor header.
+func WithActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor := r.Header.Get("X-Actor"); actor != "" {
+			r = r.WithContext(application.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func GetAuditEventsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := domain.AuditFilter{}
+	if entityType := query.Get("entity_type"); entityType != "" {
+		value := domain.AuditEntity(entityType)
+		filter.EntityType = &value
+	}
+	if entityID := query.Get("entity_id"); entityID != "" {
+		filter.EntityID = &entityID
+	}
+	if actor := query.Get("actor"); actor != "" {
+		filter.Actor = &actor
+	}
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "from must be an RFC 3339 time or a YYYY-MM-DD date")
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "to must be an RFC 3339 time or a YYYY-MM-DD date")
+		return
+	}
+	if limit := query.Get("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil || filter.Limit <= 0 {
+			writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "limit must be a positive integer")
+			return
+		}
+	}
+
+	events, err := application.GetAuditEvents(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+	if events == nil {
+		events = []domain.AuditEvent{}
+	}
+
+	writeJSON(w, http.StatusOK, AuditEventsResponse{
+		Events: events,
+	})
+}
@@ -12,19 +12,111 @@ type CreateTeamRequest struct {
 	Members  []domain.TeamMember `json:"members"`
 }
 
+type AddTeamMemberRequest struct {
+	TeamName string            `json:"team_name"`
+	User     domain.TeamMember `json:"user"`
+}
+
+type RemoveTeamMemberRequest struct {
+	TeamName string `json:"team_name"`
+	UserID   string `json:"user_id"`
+}
+
+type MoveTeamMemberRequest struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+type RestoreTeamRequest struct {
+	TeamName          string `json:"team_name"`
+	ReassignReviewers bool   `json:"reassign_reviewers,omitempty"`
+}
+
+// SetUserActiveRequest deactivates or activates a user. Reassign defaults to
+// true: open reviews of a deactivated user are handed over to teammates.
 type SetUserActiveRequest struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
+	Reassign *bool  `json:"reassign,omitempty"`
+}
+
+type UserActiveEntry struct {
+	UserID   string `json:"user_id"`
+	IsActive bool   `json:"is_active"`
+}
+
+// BulkSetUserActiveRequest updates many users in one transaction. Reassign
+// applies to every deactivated user and defaults to true.
+type BulkSetUserActiveRequest struct {
+	Users    []UserActiveEntry `json:"users"`
+	Reassign *bool             `json:"reassign,omitempty"`
+}
+
+// AddUserAbsenceRequest schedules an absence from starts_at until ends_at,
+// both RFC 3339 timestamps.
+type AddUserAbsenceRequest struct {
+	UserID   string     `json:"user_id"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
 }
 
 type CreatePullRequestRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	Draft           bool     `json:"draft,omitempty"`
+	ReviewersCount  *int     `json:"reviewers_count,omitempty"`
+	ChangedPaths    []string `json:"changed_paths,omitempty"`
+}
+
+type PullRequestIDRequest struct {
+	PullRequestID string `json:"pull_request_id"`
 }
 
 type MergePullRequestRequest struct {
 	PullRequestID string `json:"pull_request_id"`
+	Override      bool   `json:"override,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+type SetMergePolicyRequest struct {
+	TeamName                string `json:"team_name"`
+	MinApprovals            int    `json:"min_approvals"`
+	BlockOnChangesRequested bool   `json:"block_on_changes_requested"`
+	ForbidSelfApproval      bool   `json:"forbid_self_approval"`
+}
+
+type SetTeamSettingsRequest struct {
+	TeamName              string  `json:"team_name"`
+	ReviewersRequired     int     `json:"reviewers_required"`
+	ReviewerPool          *string `json:"reviewer_pool,omitempty"`
+	PoolReviewersRequired int     `json:"pool_reviewers_required,omitempty"`
+	ReviewSLAHours        int     `json:"review_sla_hours,omitempty"`
+	SLAAction             string  `json:"sla_action,omitempty"`
+	TeamLead              *string `json:"team_lead,omitempty"`
+}
+
+type SetCodeownersRequest struct {
+	TeamName string `json:"team_name"`
+	Content  string `json:"content"`
+	Required bool   `json:"required,omitempty"`
+}
+
+type SetReviewerPoolRequest struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+type SubscribeWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+type LinkProviderAccountRequest struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserID   string `json:"user_id"`
 }
 
 type ReassignPullRequestRequest struct {
@@ -32,11 +124,26 @@ type ReassignPullRequestRequest struct {
 	OldUserID     string `json:"old_user_id"`
 }
 
+type SubmitReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	State         string `json:"state"`
+}
+
 type PullRequestResponse struct {
 	domain.PullRequestShort
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         *time.Time `json:"createdAt,omitempty"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	AssignedReviewers []string         `json:"assigned_reviewers"`
+	Reviews           []ReviewResponse `json:"reviews"`
+	ReviewersCount    *int             `json:"reviewers_count,omitempty"`
+	ChangedPaths      []string         `json:"changed_paths,omitempty"`
+	CreatedAt         *time.Time       `json:"createdAt,omitempty"`
+	MergedAt          *time.Time       `json:"mergedAt,omitempty"`
+}
+
+type ReviewResponse struct {
+	ReviewerID  string             `json:"reviewer_id"`
+	State       domain.ReviewState `json:"state"`
+	SubmittedAt *time.Time         `json:"submittedAt,omitempty"`
 }
 
 type PullRequestShortResponse struct {
@@ -47,14 +154,98 @@ type TeamWrapperResponse struct {
 	Team domain.Team `json:"team"`
 }
 
-type UserWrapperResponse struct {
-	User domain.User `json:"user"`
+type TeamMembershipResponse struct {
+	Team          domain.Team           `json:"team"`
+	Reassignments []domain.Reassignment `json:"reassignments"`
+}
+
+type DeletedTeamsResponse struct {
+	Teams []domain.DeletedTeam `json:"teams"`
+}
+
+type RestoreTeamResponse struct {
+	Team         domain.Team           `json:"team"`
+	PullRequests []PullRequestResponse `json:"pull_requests"`
+}
+
+type PurgeTeamsResponse struct {
+	PurgedTeams []string `json:"purged_teams"`
+}
+
+type AuditEventsResponse struct {
+	Events []domain.AuditEvent `json:"events"`
+}
+
+type MergePolicyWrapperResponse struct {
+	Policy domain.MergePolicy `json:"policy"`
+}
+
+type CodeownersWrapperResponse struct {
+	Codeowners domain.Codeowners `json:"codeowners"`
+}
+
+type ReviewerPoolWrapperResponse struct {
+	Pool domain.ReviewerPool `json:"pool"`
+}
+
+type WebhookWrapperResponse struct {
+	Subscription domain.WebhookSubscription `json:"subscription"`
+}
+
+type WebhookDeliveriesResponse struct {
+	SubscriptionID int64                    `json:"subscription_id"`
+	Deliveries     []domain.WebhookDelivery `json:"deliveries"`
+}
+
+type ProviderAccountWrapperResponse struct {
+	Account domain.ProviderAccount `json:"account"`
+}
+
+type ProviderAccountsResponse struct {
+	Provider domain.Provider          `json:"provider"`
+	Accounts []domain.ProviderAccount `json:"accounts"`
+}
+
+type TeamSettingsWrapperResponse struct {
+	Settings domain.TeamSettings `json:"settings"`
+}
+
+type UserStatusResponse struct {
+	User          domain.User           `json:"user"`
+	Reassignments []domain.Reassignment `json:"reassignments"`
+}
+
+// UserStatusResult is set either to the updated user with its reassignments
+// or to the error for that user.
+type UserStatusResult struct {
+	UserID        string                `json:"user_id"`
+	User          *domain.User          `json:"user,omitempty"`
+	Reassignments []domain.Reassignment `json:"reassignments,omitempty"`
+	Error         *ErrorDetail          `json:"error,omitempty"`
+}
+
+type BulkUserStatusResponse struct {
+	Results []UserStatusResult `json:"results"`
+}
+
+type AbsenceWrapperResponse struct {
+	Absence domain.Absence `json:"absence"`
+}
+
+type UserAbsencesResponse struct {
+	UserID   string           `json:"user_id"`
+	Absences []domain.Absence `json:"absences"`
 }
 
 type PullRequestWrapperResponse struct {
 	PR PullRequestResponse `json:"pr"`
 }
 
+type PullRequestHistoryResponse struct {
+	PullRequestID string                   `json:"pull_request_id"`
+	History       []domain.AssignmentEvent `json:"history"`
+}
+
 type ReassignResponse struct {
 	PR         PullRequestResponse `json:"pr"`
 	ReplacedBy string              `json:"replaced_by"`
@@ -67,16 +258,9 @@ type UserPullRequestsResponse struct {
 
 // Conversion functions
 func domainPRToResponse(pr domain.PullRequest) PullRequestResponse {
-	reviewersStr := strings.Trim(pr.AssignedReviewers, "[]")
-	var reviewers []string
-	if reviewersStr != "" {
-		parts := strings.Split(reviewersStr, ",")
-		for _, part := range parts {
-			reviewer := strings.TrimSpace(part)
-			if reviewer != "" {
-				reviewers = append(reviewers, reviewer)
-			}
-		}
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
 	}
 
 	status := domain.PullRequestStatus(strings.ToUpper(string(pr.Status)))
@@ -91,6 +275,16 @@ func domainPRToResponse(pr domain.PullRequest) PullRequestResponse {
 		mergedAt = pr.MergedAt
 	}
 
+	reviews := make([]ReviewResponse, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		review, _ := pr.ReviewOf(reviewer)
+		reviews = append(reviews, ReviewResponse{
+			ReviewerID:  reviewer,
+			State:       domain.ReviewState(strings.ToUpper(string(review.State))),
+			SubmittedAt: review.SubmittedAt,
+		})
+	}
+
 	return PullRequestResponse{
 		PullRequestShort: domain.PullRequestShort{
 			ID:       pr.ID,
@@ -99,6 +293,9 @@ func domainPRToResponse(pr domain.PullRequest) PullRequestResponse {
 			Status:   status,
 		},
 		AssignedReviewers: reviewers,
+		Reviews:           reviews,
+		ReviewersCount:    pr.ReviewersCount,
+		ChangedPaths:      pr.ChangedPaths,
 		CreatedAt:         createdAt,
 		MergedAt:          mergedAt,
 	}
@@ -124,13 +321,19 @@ func requestToDomainTeam(req CreateTeamRequest) domain.Team {
 }
 
 func requestToDomainPR(req CreatePullRequestRequest) domain.PullRequest {
+	status := domain.Open
+	if req.Draft {
+		status = domain.Draft
+	}
 	return domain.PullRequest{
 		PullRequestShort: domain.PullRequestShort{
 			ID:       req.PullRequestID,
 			Name:     req.PullRequestName,
 			AuthorID: req.AuthorID,
-			Status:   domain.Open,
+			Status:   status,
 		},
+		ReviewersCount: req.ReviewersCount,
+		ChangedPaths:   req.ChangedPaths,
 	}
 }
 
@@ -141,3 +344,59 @@ func requestToDomainPRForMerge(req MergePullRequestRequest) domain.PullRequest {
 		},
 	}
 }
+
+func requestToDomainMergePolicy(req SetMergePolicyRequest) domain.MergePolicy {
+	return domain.MergePolicy{
+		TeamName:                req.TeamName,
+		MinApprovals:            req.MinApprovals,
+		BlockOnChangesRequested: req.BlockOnChangesRequested,
+		ForbidSelfApproval:      req.ForbidSelfApproval,
+	}
+}
+
+func requestToDomainTeamSettings(req SetTeamSettingsRequest) domain.TeamSettings {
+	return domain.TeamSettings{
+		TeamName:              req.TeamName,
+		ReviewersRequired:     req.ReviewersRequired,
+		ReviewerPool:          req.ReviewerPool,
+		PoolReviewersRequired: req.PoolReviewersRequired,
+		ReviewSLAHours:        req.ReviewSLAHours,
+		SLAAction:             domain.SLAAction(strings.ToLower(req.SLAAction)),
+		TeamLead:              req.TeamLead,
+	}
+}
+
+func requestToDomainCodeowners(req SetCodeownersRequest) domain.Codeowners {
+	return domain.Codeowners{
+		TeamName: req.TeamName,
+		Content:  req.Content,
+		Required: req.Required,
+	}
+}
+
+func requestToDomainReviewerPool(req SetReviewerPoolRequest) domain.ReviewerPool {
+	return domain.ReviewerPool{
+		Name:    req.Name,
+		Members: req.Members,
+	}
+}
+
+func requestToDomainWebhookSubscription(req SubscribeWebhookRequest) domain.WebhookSubscription {
+	events := make([]domain.WebhookEventType, 0, len(req.Events))
+	for _, event := range req.Events {
+		events = append(events, domain.WebhookEventType(event))
+	}
+	return domain.WebhookSubscription{
+		URL:    req.URL,
+		Events: events,
+		Secret: req.Secret,
+	}
+}
+
+func requestToDomainProviderAccount(req LinkProviderAccountRequest) domain.ProviderAccount {
+	return domain.ProviderAccount{
+		Provider: domain.Provider(req.Provider),
+		Login:    req.Login,
+		UserID:   req.UserID,
+	}
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
 )
 
 type ErrorCode string
@@ -14,6 +16,24 @@ const (
 	ErrorCodeNotAssigned ErrorCode = "NOT_ASSIGNED"
 	ErrorCodeNoCandidate ErrorCode = "NO_CANDIDATE"
 	ErrorCodeNotFound    ErrorCode = "NOT_FOUND"
+
+	ErrorCodeInvalidReviewState ErrorCode = "INVALID_REVIEW_STATE"
+	ErrorCodePolicyNotSatisfied ErrorCode = "POLICY_NOT_SATISFIED"
+	ErrorCodeInvalidPolicy      ErrorCode = "INVALID_POLICY"
+	ErrorCodeForbidden          ErrorCode = "FORBIDDEN"
+	ErrorCodePRNotOpen          ErrorCode = "PR_NOT_OPEN"
+	ErrorCodeInvalidTransition  ErrorCode = "INVALID_TRANSITION"
+	ErrorCodeMemberExists       ErrorCode = "MEMBER_EXISTS"
+	ErrorCodeInvalidAbsence     ErrorCode = "INVALID_ABSENCE"
+	ErrorCodeInvalidReviewers   ErrorCode = "INVALID_REVIEWERS"
+	ErrorCodeInvalidPool        ErrorCode = "INVALID_POOL"
+	ErrorCodeInvalidCodeowners  ErrorCode = "INVALID_CODEOWNERS"
+	ErrorCodeInvalidSLA         ErrorCode = "INVALID_SLA"
+	ErrorCodeInvalidWebhook     ErrorCode = "INVALID_WEBHOOK"
+	ErrorCodeInvalidAccount     ErrorCode = "INVALID_ACCOUNT"
+	ErrorCodeAccountNotLinked   ErrorCode = "ACCOUNT_NOT_LINKED"
+	ErrorCodeInvalidSignature   ErrorCode = "INVALID_SIGNATURE"
+	ErrorCodePayloadTooLarge    ErrorCode = "PAYLOAD_TOO_LARGE"
 )
 
 type ErrorResponse struct {
@@ -21,8 +41,9 @@ type ErrorResponse struct {
 }
 
 type ErrorDetail struct {
-	Code    ErrorCode `json:"code"`
-	Message string    `json:"message"`
+	Code       ErrorCode `json:"code"`
+	Message    string    `json:"message"`
+	UnmetRules []string  `json:"unmet_rules,omitempty"`
 }
 
 func writeError(w http.ResponseWriter, statusCode int, code ErrorCode, message string) {
@@ -36,6 +57,18 @@ func writeError(w http.ResponseWriter, statusCode int, code ErrorCode, message s
 	})
 }
 
+func writePolicyError(w http.ResponseWriter, policyErr *domain.PolicyError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Error: ErrorDetail{
+			Code:       ErrorCodePolicyNotSatisfied,
+			Message:    policyErr.Error(),
+			UnmetRules: policyErr.UnmetRules,
+		},
+	})
+}
+
 func writeJSON(w http.ResponseWriter, statusCode int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/application"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/integration"
+)
+
+// maxProviderWebhookBody limits the provider webhook bodies read into memory.
+// Pull request events are far smaller.
+const maxProviderWebhookBody = 1 << 20
+
+func GitHubWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	body, ok := readProviderWebhookBody(w, r)
+	if !ok {
+		return
+	}
+	secret := application.ProviderWebhookSecret(domain.ProviderGitHub)
+	if !integration.VerifyGitHub(secret, body, r.Header.Get(integration.GitHubSignatureHeader)) {
+		writeError(w, http.StatusUnauthorized, ErrorCodeInvalidSignature, domain.ErrInvalidSignature.Error())
+		return
+	}
+
+	event, ok, err := integration.ParseGitHub(r.Header.Get(integration.GitHubEventHeader), body)
+	ingestProviderEvent(w, r, event, ok, err)
+}
+
+func GitLabWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	secret := application.ProviderWebhookSecret(domain.ProviderGitLab)
+	if !integration.VerifyGitLab(secret, r.Header.Get(integration.GitLabTokenHeader)) {
+		writeError(w, http.StatusUnauthorized, ErrorCodeInvalidSignature, domain.ErrInvalidSignature.Error())
+		return
+	}
+	body, ok := readProviderWebhookBody(w, r)
+	if !ok {
+		return
+	}
+
+	event, ok, err := integration.ParseGitLab(r.Header.Get(integration.GitLabEventHeader), body)
+	ingestProviderEvent(w, r, event, ok, err)
+}
+
+// readProviderWebhookBody reads the body of a provider webhook and answers 413
+// if it exceeds maxProviderWebhookBody.
+func readProviderWebhookBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxProviderWebhookBody))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, ErrorCodePayloadTooLarge, "request body is too large")
+			return nil, false
+		}
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return nil, false
+	}
+	return body, true
+}
+
+// ingestProviderEvent applies a parsed provider event. Events the service
+// does not track are acknowledged with 204.
+func ingestProviderEvent(w http.ResponseWriter, r *http.Request, event domain.ProviderPullRequestEvent, ok bool, err error) {
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	result, err := application.IngestPullRequestEvent(r.Context(), event)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotLinked) {
+			writeError(w, http.StatusUnprocessableEntity, ErrorCodeAccountNotLinked, "author login is not linked to a user")
+			return
+		}
+		if errors.Is(err, domain.ErrPRMerged) {
+			writeError(w, http.StatusConflict, ErrorCodePRMerged, "pull request is already merged")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			writeError(w, http.StatusConflict, ErrorCodeInvalidTransition, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrNoPossibleAssigners) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PullRequestWrapperResponse{
+		PR: domainPRToResponse(result),
+	})
+}
+
+func LinkProviderAccountHandler(w http.ResponseWriter, r *http.Request) {
+	var req LinkProviderAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+
+	result, err := application.LinkProviderAccount(r.Context(), requestToDomainProviderAccount(req))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAccount) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidAccount, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ProviderAccountWrapperResponse{
+		Account: result,
+	})
+}
+
+func GetProviderAccountsHandler(w http.ResponseWriter, r *http.Request) {
+	provider := domain.Provider(r.URL.Query().Get("provider"))
+
+	result, err := application.GetProviderAccounts(r.Context(), provider)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAccount) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidAccount, "provider must be github or gitlab")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+	if result == nil {
+		result = []domain.ProviderAccount{}
+	}
+
+	writeJSON(w, http.StatusOK, ProviderAccountsResponse{
+		Provider: provider,
+		Accounts: result,
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/application"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+func AddTeamMemberHandler(w http.ResponseWriter, r *http.Request) {
+	var req AddTeamMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+	if req.TeamName == "" || req.User.UserID == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "team_name and user.user_id are required")
+		return
+	}
+
+	team, err := application.AddTeamMember(r.Context(), req.TeamName, req.User)
+	if err != nil {
+		writeMembershipError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, membershipResponse(team, nil))
+}
+
+func RemoveTeamMemberHandler(w http.ResponseWriter, r *http.Request) {
+	var req RemoveTeamMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+	if req.TeamName == "" || req.UserID == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "team_name and user_id are required")
+		return
+	}
+
+	team, reassignments, err := application.RemoveTeamMember(r.Context(), req.TeamName, req.UserID)
+	if err != nil {
+		writeMembershipError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, membershipResponse(team, reassignments))
+}
+
+func MoveTeamMemberHandler(w http.ResponseWriter, r *http.Request) {
+	var req MoveTeamMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+	if req.TeamName == "" || req.UserID == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "user_id and team_name are required")
+		return
+	}
+
+	team, reassignments, err := application.MoveTeamMember(r.Context(), req.UserID, req.TeamName)
+	if err != nil {
+		writeMembershipError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, membershipResponse(team, reassignments))
+}
+
+func membershipResponse(team domain.Team, reassignments []domain.Reassignment) TeamMembershipResponse {
+	if reassignments == nil {
+		reassignments = []domain.Reassignment{}
+	}
+	return TeamMembershipResponse{Team: team, Reassignments: reassignments}
+}
+
+func writeMembershipError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrUserInAnotherTeam) {
+		writeError(w, http.StatusConflict, ErrorCodeMemberExists, "user is already in a team, use /team/moveMember")
+		return
+	}
+	if errors.Is(err, domain.ErrTeamNotFound) || errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrNotTeamMember) {
+		writeError(w, http.StatusNotFound, ErrorCodeNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+}
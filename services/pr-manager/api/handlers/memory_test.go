@@ -0,0 +1,1390 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/application"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/integration"
+	"github.com/zemld/pr-manager/pr-manager/internal/webhook"
+)
+
+func useMemoryStorage(t *testing.T) {
+	t.Helper()
+	application.UseMemoryStorage()
+	t.Cleanup(application.UsePostgresStorage)
+}
+
+func doRequest(t *testing.T, handler http.HandlerFunc, method string, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("failed to encode request: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+// doAdminRequest is doRequest with the admin token "secret"; the test sets
+// ADMIN_TOKEN to it.
+func doAdminRequest(t *testing.T, handler http.HandlerFunc, method string, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("failed to encode request: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Admin-Token", "secret")
+	w := httptest.NewRecorder()
+	handler(w, req)
+	return w
+}
+
+func decodeErrorCode(t *testing.T, w *httptest.ResponseRecorder) ErrorCode {
+	t.Helper()
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	return errResp.Error.Code
+}
+
+func addBackendTeam(t *testing.T) {
+	t.Helper()
+	w := doRequest(t, AddTeamHandler, "POST", "/team/add", CreateTeamRequest{
+		TeamName: "backend",
+		Members: []domain.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Carol", IsActive: true},
+		},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+}
+
+func TestPullRequestLifecycle_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	w := doRequest(t, AddTeamHandler, "POST", "/team/add", CreateTeamRequest{TeamName: "backend"})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeTeamExists {
+		t.Errorf("expected TEAM_EXISTS, got %d: %s", w.Code, w.Body.String())
+	}
+
+	createReq := CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"}
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", createReq)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if len(created.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", created.PR.AssignedReviewers)
+	}
+	for _, reviewer := range created.PR.AssignedReviewers {
+		if reviewer == "u1" {
+			t.Errorf("author must not be assigned as reviewer")
+		}
+	}
+
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", createReq)
+	if w.Code != http.StatusConflict || decodeErrorCode(t, w) != ErrorCodePRExists {
+		t.Errorf("expected PR_EXISTS, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, GetUserReviewsHandler, "GET", "/users/getReview?user_id=u2", nil)
+	var reviews UserPullRequestsResponse
+	json.Unmarshal(w.Body.Bytes(), &reviews)
+	if len(reviews.PullRequests) != 1 || reviews.PullRequests[0].ID != "pr1" {
+		t.Errorf("expected u2 to review pr1, got %+v", reviews.PullRequests)
+	}
+
+	// Nobody is left in the team to replace u2, so it is just removed.
+	w = doRequest(t, ReassignPullRequestHandler, "POST", "/pullRequest/reassign", ReassignPullRequestRequest{PullRequestID: "pr1", OldUserID: "u2"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var reassigned ReassignResponse
+	json.Unmarshal(w.Body.Bytes(), &reassigned)
+	if reassigned.ReplacedBy != "" || len(reassigned.PR.AssignedReviewers) != 1 || reassigned.PR.AssignedReviewers[0] != "u3" {
+		t.Errorf("expected only u3 to remain, got %+v", reassigned)
+	}
+
+	for range 2 {
+		w = doRequest(t, MergePullRequestHandler, "POST", "/pullRequest/merge", MergePullRequestRequest{PullRequestID: "pr1"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+	}
+	var merged PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &merged)
+	if merged.PR.Status != "MERGED" || merged.PR.MergedAt == nil {
+		t.Errorf("expected merged PR with mergedAt, got %+v", merged.PR)
+	}
+
+	w = doRequest(t, ReassignPullRequestHandler, "POST", "/pullRequest/reassign", ReassignPullRequestRequest{PullRequestID: "pr1", OldUserID: "u3"})
+	if w.Code != http.StatusConflict || decodeErrorCode(t, w) != ErrorCodePRMerged {
+		t.Errorf("expected PR_MERGED, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDeleteTeam_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	w := doRequest(t, DeleteTeamHandler, "DELETE", "/team/delete?name=backend", nil)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, GetTeamHandler, "GET", "/team/get?name=backend", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	w = doRequest(t, SetUserActiveHandler, "POST", "/users/setIsActive", SetUserActiveRequest{UserID: "u1", IsActive: false})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for user of deleted team, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRestoreAndPurgeTeam_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	t.Setenv("ADMIN_TOKEN", "secret")
+	addBackendTeam(t)
+	doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	doRequest(t, DeleteTeamHandler, "DELETE", "/team/delete?name=backend", nil)
+
+	w := doRequest(t, GetDeletedTeamsHandler, "GET", "/team/listDeleted", nil)
+	var deleted DeletedTeamsResponse
+	json.Unmarshal(w.Body.Bytes(), &deleted)
+	if len(deleted.Teams) != 1 || deleted.Teams[0].TeamName != "backend" || len(deleted.Teams[0].Members) != 3 || deleted.Teams[0].DeletedAt == nil {
+		t.Fatalf("expected deleted backend team, got %s", w.Body.String())
+	}
+
+	w = doRequest(t, RestoreTeamHandler, "POST", "/team/restore", RestoreTeamRequest{TeamName: "backend", ReassignReviewers: true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var restored RestoreTeamResponse
+	json.Unmarshal(w.Body.Bytes(), &restored)
+	if len(restored.Team.Members) != 3 {
+		t.Errorf("expected 3 restored members, got %+v", restored.Team)
+	}
+	if len(restored.PullRequests) != 1 || len(restored.PullRequests[0].AssignedReviewers) != 2 {
+		t.Errorf("expected pr1 to get 2 reviewers back, got %+v", restored.PullRequests)
+	}
+
+	w = doRequest(t, RestoreTeamHandler, "POST", "/team/restore", RestoreTeamRequest{TeamName: "backend"})
+	if w.Code != http.StatusConflict || decodeErrorCode(t, w) != ErrorCodeTeamExists {
+		t.Errorf("expected TEAM_EXISTS, got %d: %s", w.Code, w.Body.String())
+	}
+
+	doRequest(t, DeleteTeamHandler, "DELETE", "/team/delete?name=backend", nil)
+
+	w = doRequest(t, PurgeTeamsHandler, "DELETE", "/team/purge?older_than_days=0", nil)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d without admin token, got %d", http.StatusForbidden, w.Code)
+	}
+
+	req := httptest.NewRequest("DELETE", "/team/purge", nil)
+	req.Header.Set("X-Admin-Token", "secret")
+	rec := httptest.NewRecorder()
+	PurgeTeamsHandler(rec, req)
+	var purge PurgeTeamsResponse
+	json.Unmarshal(rec.Body.Bytes(), &purge)
+	if rec.Code != http.StatusOK || len(purge.PurgedTeams) != 0 {
+		t.Errorf("expected nothing to be purged within retention, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest("DELETE", "/team/purge?older_than_days=0", nil)
+	req.Header.Set("X-Admin-Token", "secret")
+	rec = httptest.NewRecorder()
+	PurgeTeamsHandler(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected immediate purge without confirmation to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest("DELETE", "/team/purge?older_than_days=0&confirm=true", nil)
+	req.Header.Set("X-Admin-Token", "secret")
+	rec = httptest.NewRecorder()
+	PurgeTeamsHandler(rec, req)
+	json.Unmarshal(rec.Body.Bytes(), &purge)
+	if rec.Code != http.StatusOK || len(purge.PurgedTeams) != 1 || purge.PurgedTeams[0] != "backend" {
+		t.Errorf("expected backend to be purged, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	w = doRequest(t, RestoreTeamHandler, "POST", "/team/restore", RestoreTeamRequest{TeamName: "backend"})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected purged team not to be restorable, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Purged users and their pull requests are gone, so everything can be created again.
+	addBackendTeam(t)
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected pr1 to be created again, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRecreateDeletedTeam_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+	doRequest(t, DeleteTeamHandler, "DELETE", "/team/delete?name=backend", nil)
+
+	addBackendTeam(t)
+
+	w := doRequest(t, GetDeletedTeamsHandler, "GET", "/team/listDeleted", nil)
+	var deleted DeletedTeamsResponse
+	json.Unmarshal(w.Body.Bytes(), &deleted)
+	if len(deleted.Teams) != 0 {
+		t.Errorf("expected re-created team members to leave the deleted team, got %+v", deleted.Teams)
+	}
+}
+
+func TestTeamMembership_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+	w := doRequest(t, AddTeamHandler, "POST", "/team/add", CreateTeamRequest{
+		TeamName: "frontend",
+		Members:  []domain.TeamMember{{UserID: "f1", Username: "Frank", IsActive: true}},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// Nobody but the other reviewer is left in backend to take the review over.
+	w = doRequest(t, MoveTeamMemberHandler, "POST", "/team/moveMember", MoveTeamMemberRequest{UserID: "u2", TeamName: "frontend"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var moved TeamMembershipResponse
+	json.Unmarshal(w.Body.Bytes(), &moved)
+	if moved.Team.TeamName != "frontend" || len(moved.Team.Members) != 2 {
+		t.Errorf("expected u2 in frontend, got %+v", moved.Team)
+	}
+	if len(moved.Reassignments) != 1 || moved.Reassignments[0].PullRequestID != "pr1" || moved.Reassignments[0].ReplacedBy != "" {
+		t.Errorf("expected review on pr1 to be dropped, got %+v", moved.Reassignments)
+	}
+
+	w = doRequest(t, AddTeamMemberHandler, "POST", "/team/addMember", AddTeamMemberRequest{
+		TeamName: "backend",
+		User:     domain.TeamMember{UserID: "u4", Username: "Dave", IsActive: true},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, RemoveTeamMemberHandler, "POST", "/team/removeMember", RemoveTeamMemberRequest{TeamName: "backend", UserID: "u3"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var removed TeamMembershipResponse
+	json.Unmarshal(w.Body.Bytes(), &removed)
+	if len(removed.Reassignments) != 1 || removed.Reassignments[0].ReplacedBy != "u4" {
+		t.Errorf("expected review on pr1 to go to u4, got %+v", removed.Reassignments)
+	}
+
+	w = doRequest(t, GetUserReviewsHandler, "GET", "/users/getReview?user_id=u4", nil)
+	var reviews UserPullRequestsResponse
+	json.Unmarshal(w.Body.Bytes(), &reviews)
+	if len(reviews.PullRequests) != 1 || reviews.PullRequests[0].ID != "pr1" {
+		t.Errorf("expected pr1 to be reviewed by u4, got %+v", reviews.PullRequests)
+	}
+
+	w = doRequest(t, AddTeamMemberHandler, "POST", "/team/addMember", AddTeamMemberRequest{
+		TeamName: "backend",
+		User:     domain.TeamMember{UserID: "u2", Username: "Bob", IsActive: true},
+	})
+	if w.Code != http.StatusConflict || decodeErrorCode(t, w) != ErrorCodeMemberExists {
+		t.Errorf("expected MEMBER_EXISTS, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// A user removed from their team can join another one.
+	w = doRequest(t, AddTeamMemberHandler, "POST", "/team/addMember", AddTeamMemberRequest{
+		TeamName: "frontend",
+		User:     domain.TeamMember{UserID: "u3", Username: "Carol", IsActive: true},
+	})
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, RemoveTeamMemberHandler, "POST", "/team/removeMember", RemoveTeamMemberRequest{TeamName: "backend", UserID: "f1"})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+}
+
+func TestSubmitReview_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	w := doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	for _, review := range created.PR.Reviews {
+		if review.State != "PENDING" {
+			t.Errorf("expected new reviews to be PENDING, got %s", review.State)
+		}
+	}
+
+	w = doRequest(t, SubmitReviewHandler, "POST", "/pullRequest/review", SubmitReviewRequest{PullRequestID: "pr1", ReviewerID: "u2", State: "LGTM"})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidReviewState {
+		t.Errorf("expected INVALID_REVIEW_STATE, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, SubmitReviewHandler, "POST", "/pullRequest/review", SubmitReviewRequest{PullRequestID: "pr1", ReviewerID: "u1", State: "APPROVED"})
+	if w.Code != http.StatusConflict || decodeErrorCode(t, w) != ErrorCodeNotAssigned {
+		t.Errorf("expected NOT_ASSIGNED for the author, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, SubmitReviewHandler, "POST", "/pullRequest/review", SubmitReviewRequest{PullRequestID: "pr1", ReviewerID: "u2", State: "APPROVED"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var reviewed PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &reviewed)
+	for _, review := range reviewed.PR.Reviews {
+		if review.ReviewerID == "u2" && (review.State != "APPROVED" || review.SubmittedAt == nil) {
+			t.Errorf("expected u2 review to be APPROVED with submittedAt, got %+v", review)
+		}
+	}
+
+	for userID, want := range map[string]int{"u2": 0, "u3": 1} {
+		w = doRequest(t, GetUserReviewsHandler, "GET", "/users/getReview?pending=true&user_id="+userID, nil)
+		var reviews UserPullRequestsResponse
+		json.Unmarshal(w.Body.Bytes(), &reviews)
+		if len(reviews.PullRequests) != want {
+			t.Errorf("expected %d pending reviews for %s, got %d", want, userID, len(reviews.PullRequests))
+		}
+	}
+
+	w = doRequest(t, GetUserReviewsHandler, "GET", "/users/getReview?pending=maybe&user_id=u2", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for invalid pending, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestMergePolicy_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	t.Setenv("ADMIN_TOKEN", "secret")
+	addBackendTeam(t)
+
+	w := doRequest(t, SetMergePolicyHandler, "POST", "/team/setMergePolicy", SetMergePolicyRequest{TeamName: "backend", MinApprovals: -1})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidPolicy {
+		t.Errorf("expected INVALID_POLICY, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, SetMergePolicyHandler, "POST", "/team/setMergePolicy", SetMergePolicyRequest{TeamName: "backend", MinApprovals: 1, BlockOnChangesRequested: true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, GetMergePolicyHandler, "GET", "/team/getMergePolicy?name=backend", nil)
+	var policy MergePolicyWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &policy)
+	if policy.Policy.MinApprovals != 1 || !policy.Policy.BlockOnChangesRequested {
+		t.Errorf("expected stored policy, got %+v", policy.Policy)
+	}
+
+	doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr2", PullRequestName: "Hotfix", AuthorID: "u1"})
+
+	w = doRequest(t, MergePullRequestHandler, "POST", "/pullRequest/merge", MergePullRequestRequest{PullRequestID: "pr1"})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+	var errResp ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &errResp)
+	if errResp.Error.Code != ErrorCodePolicyNotSatisfied || len(errResp.Error.UnmetRules) != 1 {
+		t.Errorf("expected POLICY_NOT_SATISFIED with one unmet rule, got %+v", errResp.Error)
+	}
+
+	doRequest(t, SubmitReviewHandler, "POST", "/pullRequest/review", SubmitReviewRequest{PullRequestID: "pr1", ReviewerID: "u2", State: "APPROVED"})
+	w = doRequest(t, MergePullRequestHandler, "POST", "/pullRequest/merge", MergePullRequestRequest{PullRequestID: "pr1"})
+	if w.Code != http.StatusOK {
+		t.Errorf("expected approved PR to merge, got %d: %s", w.Code, w.Body.String())
+	}
+
+	override := MergePullRequestRequest{PullRequestID: "pr2", Override: true, Reason: "production incident"}
+	w = doRequest(t, MergePullRequestHandler, "POST", "/pullRequest/merge", override)
+	if w.Code != http.StatusForbidden || decodeErrorCode(t, w) != ErrorCodeForbidden {
+		t.Errorf("expected FORBIDDEN without admin token, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(override)
+	req := httptest.NewRequest("POST", "/pullRequest/merge", &buf)
+	req.Header.Set("X-Admin-Token", "secret")
+	rec := httptest.NewRecorder()
+	MergePullRequestHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected admin override to merge, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	w = doRequest(t, SetMergePolicyHandler, "POST", "/team/setMergePolicy", SetMergePolicyRequest{TeamName: "backend", MinApprovals: 1, ForbidSelfApproval: true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr3", PullRequestName: "Add filters", AuthorID: "u1"})
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	reviewer := created.PR.AssignedReviewers[0]
+	doRequest(t, SubmitReviewHandler, "POST", "/pullRequest/review", SubmitReviewRequest{PullRequestID: "pr3", ReviewerID: reviewer, State: "APPROVED"})
+
+	// merge sends the merge of pr3 on behalf of the actor, if any.
+	merge := func(actor string) *httptest.ResponseRecorder {
+		t.Helper()
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(MergePullRequestRequest{PullRequestID: "pr3"})
+		req := httptest.NewRequest("POST", "/pullRequest/merge", &buf)
+		if actor != "" {
+			req.Header.Set("X-Actor", actor)
+		}
+		rec := httptest.NewRecorder()
+		WithActor(http.HandlerFunc(MergePullRequestHandler)).ServeHTTP(rec, req)
+		return rec
+	}
+	for _, actor := range []string{"", "u1"} {
+		rec = merge(actor)
+		json.Unmarshal(rec.Body.Bytes(), &errResp)
+		if rec.Code != http.StatusConflict || errResp.Error.Code != ErrorCodePolicyNotSatisfied || len(errResp.Error.UnmetRules) != 1 {
+			t.Errorf("expected POLICY_NOT_SATISFIED for actor %q, got %d: %s", actor, rec.Code, rec.Body.String())
+		}
+	}
+	if rec = merge(reviewer); rec.Code != http.StatusOK {
+		t.Errorf("expected the reviewer to merge, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestTeamSettings_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	w := doRequest(t, GetTeamSettingsHandler, "GET", "/team/getSettings?name=backend", nil)
+	var settings TeamSettingsWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &settings)
+	if w.Code != http.StatusOK || settings.Settings.ReviewersRequired != domain.DefaultReviewersRequired {
+		t.Errorf("expected default settings, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, SetTeamSettingsHandler, "POST", "/team/setSettings", SetTeamSettingsRequest{TeamName: "backend", ReviewersRequired: 11})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidReviewers {
+		t.Errorf("expected INVALID_REVIEWERS, got %d: %s", w.Code, w.Body.String())
+	}
+	w = doRequest(t, SetTeamSettingsHandler, "POST", "/team/setSettings", SetTeamSettingsRequest{TeamName: "missing", ReviewersRequired: 1})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+	w = doRequest(t, SetTeamSettingsHandler, "POST", "/team/setSettings", SetTeamSettingsRequest{TeamName: "backend", ReviewersRequired: 1})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if len(created.PR.AssignedReviewers) != 1 || created.PR.ReviewersCount != nil {
+		t.Errorf("expected one reviewer from team settings, got %+v", created.PR)
+	}
+
+	count := 2
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr2", PullRequestName: "Hotfix", AuthorID: "u1", ReviewersCount: &count})
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if len(created.PR.AssignedReviewers) != 2 || created.PR.ReviewersCount == nil || *created.PR.ReviewersCount != 2 {
+		t.Errorf("expected two reviewers from the request, got %+v", created.PR)
+	}
+
+	count = 0
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr3", PullRequestName: "Empty", AuthorID: "u1", ReviewersCount: &count})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidReviewers {
+		t.Errorf("expected INVALID_REVIEWERS, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReviewSLA_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	w := doRequest(t, SetTeamSettingsHandler, "POST", "/team/setSettings", SetTeamSettingsRequest{TeamName: "backend", ReviewersRequired: 1, ReviewSLAHours: 24, SLAAction: "escalate"})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidSLA {
+		t.Errorf("expected INVALID_SLA without team lead, got %d: %s", w.Code, w.Body.String())
+	}
+	ghost := "ghost"
+	w = doRequest(t, SetTeamSettingsHandler, "POST", "/team/setSettings", SetTeamSettingsRequest{TeamName: "backend", ReviewersRequired: 1, ReviewSLAHours: 24, TeamLead: &ghost})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for unknown team lead, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+
+	lead := "u3"
+	w = doRequest(t, SetTeamSettingsHandler, "POST", "/team/setSettings", SetTeamSettingsRequest{TeamName: "backend", ReviewersRequired: 1, ReviewSLAHours: 24, SLAAction: "ESCALATE", TeamLead: &lead})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, GetTeamSettingsHandler, "GET", "/team/getSettings?name=backend", nil)
+	var settings TeamSettingsWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &settings)
+	if settings.Settings.ReviewSLAHours != 24 || settings.Settings.SLAAction != domain.SLAEscalate || settings.Settings.TeamLead == nil || *settings.Settings.TeamLead != lead {
+		t.Errorf("expected the review SLA stored, got %+v", settings.Settings)
+	}
+
+	doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	escalations, err := application.EscalateStaleReviews(context.Background())
+	if err != nil || len(escalations) != 0 {
+		t.Errorf("expected nothing to escalate within the SLA, got %+v, %v", escalations, err)
+	}
+}
+
+func TestReviewerPool_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+	w := doRequest(t, AddTeamHandler, "POST", "/team/add", CreateTeamRequest{
+		TeamName: "security",
+		Members:  []domain.TeamMember{{UserID: "s1", Username: "Sam", IsActive: true}},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, SetReviewerPoolHandler, "POST", "/pool/set", SetReviewerPoolRequest{Name: "", Members: []string{"s1"}})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidPool {
+		t.Errorf("expected INVALID_POOL, got %d: %s", w.Code, w.Body.String())
+	}
+	w = doRequest(t, SetReviewerPoolHandler, "POST", "/pool/set", SetReviewerPoolRequest{Name: "security", Members: []string{"ghost"}})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for unknown member, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+	w = doRequest(t, SetReviewerPoolHandler, "POST", "/pool/set", SetReviewerPoolRequest{Name: "security", Members: []string{"s1"}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w = doRequest(t, GetReviewerPoolHandler, "GET", "/pool/get?name=security", nil)
+	var pool ReviewerPoolWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &pool)
+	if !slices.Equal(pool.Pool.Members, []string{"s1"}) {
+		t.Errorf("expected stored pool, got %s", w.Body.String())
+	}
+
+	poolName := "security"
+	w = doRequest(t, SetTeamSettingsHandler, "POST", "/team/setSettings", SetTeamSettingsRequest{TeamName: "backend", ReviewersRequired: 1, ReviewerPool: &poolName})
+	var settings TeamSettingsWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &settings)
+	if w.Code != http.StatusOK || settings.Settings.PoolReviewersRequired != 1 {
+		t.Fatalf("expected settings with one pool reviewer, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if len(created.PR.AssignedReviewers) != 2 || !slices.Contains(created.PR.AssignedReviewers, "s1") {
+		t.Errorf("expected one backend reviewer and s1 from the pool, got %v", created.PR.AssignedReviewers)
+	}
+}
+
+func TestCodeowners_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	w := doRequest(t, SetCodeownersHandler, "POST", "/team/setCodeowners", SetCodeownersRequest{TeamName: "backend", Content: "* @u2\n/api/[a-z]* @u3"})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidCodeowners {
+		t.Errorf("expected INVALID_CODEOWNERS, got %d: %s", w.Code, w.Body.String())
+	}
+	w = doRequest(t, SetCodeownersHandler, "POST", "/team/setCodeowners", SetCodeownersRequest{TeamName: "backend", Content: "* @u2\n/db/ @u3", Required: true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w = doRequest(t, GetCodeownersHandler, "GET", "/team/getCodeowners?name=backend", nil)
+	var file CodeownersWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &file)
+	if !file.Codeowners.Required || file.Codeowners.UpdatedAt == nil {
+		t.Errorf("expected stored file, got %s", w.Body.String())
+	}
+
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{
+		PullRequestID:   "pr1",
+		PullRequestName: "Add index",
+		AuthorID:        "u1",
+		ChangedPaths:    []string{"db/migrations/0001.sql"},
+	})
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if !slices.Equal(created.PR.AssignedReviewers, []string{"u3"}) || !slices.Equal(created.PR.ChangedPaths, []string{"db/migrations/0001.sql"}) {
+		t.Errorf("expected only the owner u3, got %+v", created.PR)
+	}
+}
+
+func TestPullRequestLifecycleStates_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	w := doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "WIP", AuthorID: "u1", Draft: true})
+	var pr PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &pr)
+	if w.Code != http.StatusCreated || pr.PR.Status != "DRAFT" || len(pr.PR.AssignedReviewers) != 0 {
+		t.Fatalf("expected DRAFT without reviewers, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, MergePullRequestHandler, "POST", "/pullRequest/merge", MergePullRequestRequest{PullRequestID: "pr1"})
+	if w.Code != http.StatusConflict || decodeErrorCode(t, w) != ErrorCodeInvalidTransition {
+		t.Errorf("expected INVALID_TRANSITION when merging a draft, got %d: %s", w.Code, w.Body.String())
+	}
+
+	steps := []struct {
+		handler    http.HandlerFunc
+		target     string
+		status     string
+		wantLength int
+	}{
+		{MarkPullRequestReadyHandler, "/pullRequest/ready", "OPEN", 2},
+		{ClosePullRequestHandler, "/pullRequest/close", "CLOSED", 0},
+		{ReopenPullRequestHandler, "/pullRequest/reopen", "OPEN", 2},
+	}
+	for _, step := range steps {
+		w = doRequest(t, step.handler, "POST", step.target, PullRequestIDRequest{PullRequestID: "pr1"})
+		json.Unmarshal(w.Body.Bytes(), &pr)
+		if w.Code != http.StatusOK || pr.PR.Status != domain.PullRequestStatus(step.status) || len(pr.PR.AssignedReviewers) != step.wantLength {
+			t.Errorf("%s: expected %s with %d reviewers, got %d: %s", step.target, step.status, step.wantLength, w.Code, w.Body.String())
+		}
+	}
+
+	w = doRequest(t, MarkPullRequestReadyHandler, "POST", "/pullRequest/ready", PullRequestIDRequest{PullRequestID: "missing"})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestGetStatsFiltered_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	w := doRequest(t, GetStatsHandler, "GET", "/stats/get?team=backend&user_id=u1&from=2020-01-01", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var stats domain.Stats
+	json.Unmarshal(w.Body.Bytes(), &stats)
+	if stats.Filters.TeamName == nil || *stats.Filters.TeamName != "backend" || stats.Filters.From == nil || stats.Filters.To != nil {
+		t.Errorf("expected applied filters to be echoed, got %+v", stats.Filters)
+	}
+	if len(stats.IndividualUserStats) != 1 || stats.UserStats.Total != 1 {
+		t.Errorf("expected stats of u1 only, got %+v", stats.IndividualUserStats)
+	}
+
+	for _, target := range []string{
+		"/stats/get?from=yesterday",
+		"/stats/get?from=2025-01-02&to=2025-01-01",
+	} {
+		w = doRequest(t, GetStatsHandler, "GET", target, nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d for %s, got %d", http.StatusBadRequest, target, w.Code)
+		}
+	}
+}
+
+func TestAuditLog_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	withActor := func(handler http.HandlerFunc, method string, target string, body any, actor string) *httptest.ResponseRecorder {
+		t.Helper()
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(body)
+		req := httptest.NewRequest(method, target, &buf)
+		req.Header.Set("X-Actor", actor)
+		rec := httptest.NewRecorder()
+		WithActor(handler).ServeHTTP(rec, req)
+		return rec
+	}
+
+	createReq := CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"}
+	if w := withActor(CreatePullRequestHandler, "POST", "/pullRequest/create", createReq, "alice"); w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	// Failed operations leave no audit events.
+	withActor(CreatePullRequestHandler, "POST", "/pullRequest/create", createReq, "alice")
+	for range 2 {
+		withActor(MergePullRequestHandler, "POST", "/pullRequest/merge", MergePullRequestRequest{PullRequestID: "pr1"}, "bob")
+	}
+	doRequest(t, DeleteTeamHandler, "DELETE", "/team/delete?name=backend", nil)
+
+	listEvents := func(target string) []domain.AuditEvent {
+		t.Helper()
+		w := doRequest(t, GetAuditEventsHandler, "GET", target, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var resp AuditEventsResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp.Events
+	}
+
+	events := listEvents("/audit/list")
+	var actions []domain.AuditAction
+	for _, event := range events {
+		actions = append(actions, event.Action)
+	}
+	want := []domain.AuditAction{domain.AuditTeamDeleted, domain.AuditPullRequestMerged, domain.AuditPullRequestCreated, domain.AuditTeamCreated}
+	if !slices.Equal(actions, want) {
+		t.Fatalf("expected events %v newest first, got %v", want, actions)
+	}
+	if events[0].Actor != nil || events[0].Details["removed_reviewers"] == nil {
+		t.Errorf("expected anonymous team deletion with removed reviewers, got %+v", events[0])
+	}
+
+	events = listEvents("/audit/list?actor=alice")
+	if len(events) != 1 || events[0].EntityID != "pr1" {
+		t.Errorf("expected only the PR created by alice, got %+v", events)
+	}
+
+	events = listEvents("/audit/list?entity_type=pull_request&entity_id=pr1&limit=1")
+	if len(events) != 1 || events[0].Action != domain.AuditPullRequestMerged || *events[0].Actor != "bob" {
+		t.Errorf("expected the latest pr1 event to be the merge by bob, got %+v", events)
+	}
+
+	if events = listEvents("/audit/list?from=2999-01-01"); len(events) != 0 {
+		t.Errorf("expected no events in the future, got %+v", events)
+	}
+
+	w := doRequest(t, GetAuditEventsHandler, "GET", "/audit/list?limit=0", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for invalid limit, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestAuditLog_AllChanges_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	t.Setenv("ADMIN_TOKEN", "secret")
+	addBackendTeam(t)
+	doRequest(t, AddTeamHandler, "POST", "/team/add", CreateTeamRequest{
+		TeamName: "frontend",
+		Members:  []domain.TeamMember{{UserID: "f1", Username: "Frank", IsActive: true}},
+	})
+
+	steps := []struct {
+		handler  http.HandlerFunc
+		method   string
+		target   string
+		body     any
+		wantCode int
+	}{
+		{CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"}, http.StatusCreated},
+		{SubmitReviewHandler, "POST", "/pullRequest/review", SubmitReviewRequest{PullRequestID: "pr1", ReviewerID: "u2", State: "APPROVED"}, http.StatusOK},
+		{ClosePullRequestHandler, "POST", "/pullRequest/close", PullRequestIDRequest{PullRequestID: "pr1"}, http.StatusOK},
+		{ClosePullRequestHandler, "POST", "/pullRequest/close", PullRequestIDRequest{PullRequestID: "pr1"}, http.StatusOK},
+		{ReopenPullRequestHandler, "POST", "/pullRequest/reopen", PullRequestIDRequest{PullRequestID: "pr1"}, http.StatusOK},
+		{CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr2", PullRequestName: "Draft", AuthorID: "u1", Draft: true}, http.StatusCreated},
+		{MarkPullRequestReadyHandler, "POST", "/pullRequest/ready", PullRequestIDRequest{PullRequestID: "pr2"}, http.StatusOK},
+		{SetMergePolicyHandler, "POST", "/team/setMergePolicy", SetMergePolicyRequest{TeamName: "backend", MinApprovals: 1}, http.StatusOK},
+		{SetTeamSettingsHandler, "POST", "/team/setSettings", SetTeamSettingsRequest{TeamName: "backend", ReviewersRequired: 1}, http.StatusOK},
+		{SetCodeownersHandler, "POST", "/team/setCodeowners", SetCodeownersRequest{TeamName: "backend", Content: "* u2"}, http.StatusOK},
+		{SetReviewerPoolHandler, "POST", "/pool/set", SetReviewerPoolRequest{Name: "security", Members: []string{"f1"}}, http.StatusOK},
+		{AddTeamMemberHandler, "POST", "/team/addMember", AddTeamMemberRequest{TeamName: "backend", User: domain.TeamMember{UserID: "u4", Username: "Dave", IsActive: true}}, http.StatusOK},
+		{RemoveTeamMemberHandler, "POST", "/team/removeMember", RemoveTeamMemberRequest{TeamName: "backend", UserID: "u4"}, http.StatusOK},
+		{MoveTeamMemberHandler, "POST", "/team/moveMember", MoveTeamMemberRequest{UserID: "u3", TeamName: "frontend"}, http.StatusOK},
+		{MoveTeamMemberHandler, "POST", "/team/moveMember", MoveTeamMemberRequest{UserID: "u3", TeamName: "frontend"}, http.StatusOK},
+		{DeleteTeamHandler, "DELETE", "/team/delete?name=frontend", nil, http.StatusNoContent},
+		{RestoreTeamHandler, "POST", "/team/restore", RestoreTeamRequest{TeamName: "frontend", ReassignReviewers: true}, http.StatusOK},
+		{DeleteTeamHandler, "DELETE", "/team/delete?name=frontend", nil, http.StatusNoContent},
+	}
+	for _, step := range steps {
+		if w := doRequest(t, step.handler, step.method, step.target, step.body); w.Code != step.wantCode {
+			t.Fatalf("%s %s: expected status %d, got %d: %s", step.method, step.target, step.wantCode, w.Code, w.Body.String())
+		}
+	}
+	req := httptest.NewRequest("DELETE", "/team/purge?older_than_days=0&confirm=true", nil)
+	req.Header.Set("X-Admin-Token", "secret")
+	rec := httptest.NewRecorder()
+	PurgeTeamsHandler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected frontend to be purged, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	w := doRequest(t, GetAuditEventsHandler, "GET", "/audit/list", nil)
+	var resp AuditEventsResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	var actions []domain.AuditAction
+	byAction := make(map[domain.AuditAction]domain.AuditEvent)
+	for _, event := range slices.Backward(resp.Events) {
+		actions = append(actions, event.Action)
+		byAction[event.Action] = event
+	}
+	want := []domain.AuditAction{
+		domain.AuditTeamCreated,
+		domain.AuditTeamCreated,
+		domain.AuditPullRequestCreated,
+		domain.AuditReviewSubmitted,
+		domain.AuditPullRequestClosed,
+		domain.AuditPullRequestReopened,
+		domain.AuditPullRequestCreated,
+		domain.AuditPullRequestReady,
+		domain.AuditMergePolicyUpdated,
+		domain.AuditTeamSettingsUpdated,
+		domain.AuditCodeownersUpdated,
+		domain.AuditReviewerPoolUpdated,
+		domain.AuditTeamMemberAdded,
+		domain.AuditTeamMemberRemoved,
+		domain.AuditTeamMemberMoved,
+		domain.AuditTeamDeleted,
+		domain.AuditTeamRestored,
+		domain.AuditTeamDeleted,
+		domain.AuditTeamPurged,
+	}
+	if !slices.Equal(actions, want) {
+		t.Fatalf("expected events %v oldest first, got %v", want, actions)
+	}
+
+	if review := byAction[domain.AuditReviewSubmitted]; review.Details["reviewer_id"] != "u2" || review.Details["state"] != string(domain.ReviewApproved) {
+		t.Errorf("expected the approval by u2 to be recorded, got %+v", review.Details)
+	}
+	moved := byAction[domain.AuditTeamMemberMoved]
+	if moved.EntityID != "frontend" || moved.Details["user_id"] != "u3" || moved.Details["from_team"] != "backend" {
+		t.Errorf("expected u3 to move from backend to frontend, got %+v", moved)
+	}
+	if reassignments, _ := moved.Details["reassignments"].([]any); len(reassignments) == 0 {
+		t.Errorf("expected the reviews released by the move to be recorded, got %+v", moved.Details)
+	}
+	if purged := byAction[domain.AuditTeamPurged]; purged.EntityID != "frontend" {
+		t.Errorf("expected frontend to be purged, got %+v", purged)
+	}
+}
+
+func TestPullRequestHistory_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	doRequest(t, ReassignPullRequestHandler, "POST", "/pullRequest/reassign", ReassignPullRequestRequest{PullRequestID: "pr1", OldUserID: "u2"})
+	doRequest(t, ClosePullRequestHandler, "POST", "/pullRequest/close", PullRequestIDRequest{PullRequestID: "pr1"})
+
+	w := doRequest(t, GetPullRequestHistoryHandler, "GET", "/pullRequest/history?pull_request_id=pr1", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp PullRequestHistoryResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	var reasons []domain.AssignmentReason
+	for _, event := range resp.History {
+		if event.Action == domain.Unassigned {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+	want := []domain.AssignmentReason{domain.AssignmentReassign, domain.AssignmentClosed}
+	if len(resp.History) != 4 || !slices.Equal(reasons, want) {
+		t.Errorf("expected 2 assignments and unassignments %v, got %+v", want, resp.History)
+	}
+
+	w = doRequest(t, GetPullRequestHistoryHandler, "GET", "/pullRequest/history?pull_request_id=missing", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for unknown PR, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestSetUserActiveReassignsReviews_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+	doRequest(t, AddTeamMemberHandler, "POST", "/team/addMember", AddTeamMemberRequest{
+		TeamName: "backend",
+		User:     domain.TeamMember{UserID: "u4", Username: "Dave", IsActive: true},
+	})
+	w := doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if len(created.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %s", w.Body.String())
+	}
+	leaving, staying := created.PR.AssignedReviewers[0], created.PR.AssignedReviewers[1]
+	spare := ""
+	for _, id := range []string{"u2", "u3", "u4"} {
+		if !slices.Contains(created.PR.AssignedReviewers, id) {
+			spare = id
+		}
+	}
+
+	keep := false
+	w = doRequest(t, SetUserActiveHandler, "POST", "/users/setIsActive", SetUserActiveRequest{UserID: leaving, IsActive: false, Reassign: &keep})
+	var status UserStatusResponse
+	json.Unmarshal(w.Body.Bytes(), &status)
+	if w.Code != http.StatusOK || status.User.IsActive || len(status.Reassignments) != 0 {
+		t.Fatalf("expected deactivation without reassignments, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, SetUserActiveHandler, "POST", "/users/setIsActive", SetUserActiveRequest{UserID: leaving, IsActive: false})
+	status = UserStatusResponse{}
+	json.Unmarshal(w.Body.Bytes(), &status)
+	if w.Code != http.StatusOK || len(status.Reassignments) != 1 || status.Reassignments[0].PullRequestID != "pr1" || status.Reassignments[0].ReplacedBy != spare {
+		t.Fatalf("expected pr1 to go to %s, got %d: %s", spare, w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, GetPullRequestHistoryHandler, "GET", "/pullRequest/history?pull_request_id=pr1", nil)
+	var history PullRequestHistoryResponse
+	json.Unmarshal(w.Body.Bytes(), &history)
+	deactivated := 0
+	for _, event := range history.History {
+		if event.Reason == domain.AssignmentUserDeactivated {
+			deactivated++
+		}
+	}
+	if deactivated != 2 {
+		t.Errorf("expected %s to be unassigned and %s assigned as user_deactivated, got %+v", leaving, spare, history.History)
+	}
+
+	w = doRequest(t, GetUserReviewsHandler, "GET", "/users/getReview?user_id="+staying, nil)
+	var reviews UserPullRequestsResponse
+	json.Unmarshal(w.Body.Bytes(), &reviews)
+	if len(reviews.PullRequests) != 1 {
+		t.Errorf("expected %s to keep reviewing pr1, got %+v", staying, reviews.PullRequests)
+	}
+}
+
+func TestBulkSetUserActive_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+	doRequest(t, AddTeamMemberHandler, "POST", "/team/addMember", AddTeamMemberRequest{
+		TeamName: "backend",
+		User:     domain.TeamMember{UserID: "u4", Username: "Dave", IsActive: true},
+	})
+	w := doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	reviewers := created.PR.AssignedReviewers
+
+	w = doRequest(t, BulkSetUserActiveHandler, "POST", "/users/bulkSetIsActive", BulkSetUserActiveRequest{Users: []UserActiveEntry{
+		{UserID: reviewers[0], IsActive: false},
+		{UserID: "ghost", IsActive: false},
+		{UserID: reviewers[1], IsActive: false},
+	}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response BulkUserStatusResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response.Results) != 3 {
+		t.Fatalf("expected 3 results, got %s", w.Body.String())
+	}
+	if ghost := response.Results[1]; ghost.UserID != "ghost" || ghost.User != nil || ghost.Error == nil || ghost.Error.Code != ErrorCodeNotFound {
+		t.Errorf("expected NOT_FOUND for ghost, got %+v", ghost)
+	}
+	for _, i := range []int{0, 2} {
+		if result := response.Results[i]; result.User == nil || result.User.IsActive || result.Error != nil {
+			t.Errorf("expected %s to be deactivated, got %+v", response.Results[i].UserID, result)
+		}
+	}
+
+	// The review of the first user must not go to the second one, who is
+	// deactivated by the same request.
+	for _, id := range reviewers {
+		w = doRequest(t, GetUserReviewsHandler, "GET", "/users/getReview?user_id="+id, nil)
+		var reviews UserPullRequestsResponse
+		json.Unmarshal(w.Body.Bytes(), &reviews)
+		if len(reviews.PullRequests) != 0 {
+			t.Errorf("expected %s to be released from pr1, got %+v", id, reviews.PullRequests)
+		}
+	}
+
+	w = doRequest(t, BulkSetUserActiveHandler, "POST", "/users/bulkSetIsActive", BulkSetUserActiveRequest{Users: []UserActiveEntry{
+		{UserID: "u2", IsActive: true},
+		{UserID: "u2", IsActive: false},
+	}})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for duplicate users, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestUserAbsence_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+
+	now := time.Now()
+	starts, ends := now.Add(-time.Hour), now.Add(24*time.Hour)
+	w := doRequest(t, AddUserAbsenceHandler, "POST", "/users/absence", AddUserAbsenceRequest{UserID: "u2", StartsAt: &starts, EndsAt: &ends})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, AddUserAbsenceHandler, "POST", "/users/absence", AddUserAbsenceRequest{UserID: "u2", StartsAt: &ends, EndsAt: &starts})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidAbsence {
+		t.Errorf("expected INVALID_ABSENCE, got %d: %s", w.Code, w.Body.String())
+	}
+	w = doRequest(t, AddUserAbsenceHandler, "POST", "/users/absence", AddUserAbsenceRequest{UserID: "ghost", StartsAt: &starts, EndsAt: &ends})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for unknown user, got %d", http.StatusNotFound, w.Code)
+	}
+
+	w = doRequest(t, GetUserAbsencesHandler, "GET", "/users/absences?user_id=u2", nil)
+	var absences UserAbsencesResponse
+	json.Unmarshal(w.Body.Bytes(), &absences)
+	if len(absences.Absences) != 1 || !absences.Absences[0].EndsAt.Equal(ends) {
+		t.Errorf("expected the scheduled absence, got %s", w.Body.String())
+	}
+
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if !slices.Equal(created.PR.AssignedReviewers, []string{"u3"}) {
+		t.Errorf("expected absent u2 to be skipped, got %v", created.PR.AssignedReviewers)
+	}
+
+	w = doRequest(t, GetStatsHandler, "GET", "/stats/get", nil)
+	var stats domain.Stats
+	json.Unmarshal(w.Body.Bytes(), &stats)
+	if stats.UserStats.Active != 2 || stats.UserStats.Absent != 1 || stats.UserStats.Inactive != 0 {
+		t.Errorf("expected 2 active and 1 absent user, got %+v", stats.UserStats)
+	}
+}
+
+func TestWebhooks_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+	t.Setenv("ADMIN_TOKEN", "secret")
+
+	type received struct {
+		event     domain.WebhookEvent
+		signature string
+		body      []byte
+	}
+	events := make(chan received, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event domain.WebhookEvent
+		json.Unmarshal(body, &event)
+		events <- received{event: event, signature: r.Header.Get(webhook.SignatureHeader), body: body}
+	}))
+	defer server.Close()
+
+	subscribe := SubscribeWebhookRequest{
+		URL:    server.URL,
+		Events: []string{"pr.created", "pr.merged", "user.deactivated"},
+		Secret: "s3cr3t",
+	}
+	w := doRequest(t, SubscribeWebhookHandler, "POST", "/webhooks/subscribe", subscribe)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d without admin token, got %d: %s", http.StatusForbidden, w.Code, w.Body.String())
+	}
+	w = doAdminRequest(t, SubscribeWebhookHandler, "POST", "/webhooks/subscribe", subscribe)
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidWebhook {
+		t.Errorf("expected a loopback target to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE", "true")
+	w = doAdminRequest(t, SubscribeWebhookHandler, "POST", "/webhooks/subscribe", SubscribeWebhookRequest{URL: server.URL, Events: []string{"pr.opened"}, Secret: "s3cr3t"})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidWebhook {
+		t.Errorf("expected INVALID_WEBHOOK, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doAdminRequest(t, SubscribeWebhookHandler, "POST", "/webhooks/subscribe", subscribe)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "s3cr3t") {
+		t.Errorf("expected the secret not to be returned, got %s", w.Body.String())
+	}
+	var subscribed WebhookWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &subscribed)
+	id := subscribed.Subscription.ID
+
+	// relay publishes the outbox and returns the events the webhook received.
+	relay := func() []received {
+		t.Helper()
+		if _, err := application.RelayOutbox(context.Background()); err != nil {
+			t.Fatalf("failed to relay outbox: %v", err)
+		}
+		var result []received
+		for {
+			select {
+			case r := <-events:
+				if !webhook.Verify("s3cr3t", r.body, r.signature) {
+					t.Errorf("unexpected signature %q of %s", r.signature, r.body)
+				}
+				result = append(result, r)
+			default:
+				return result
+			}
+		}
+	}
+
+	doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	doRequest(t, MergePullRequestHandler, "POST", "/pullRequest/merge", MergePullRequestRequest{PullRequestID: "pr1"})
+	doRequest(t, MergePullRequestHandler, "POST", "/pullRequest/merge", MergePullRequestRequest{PullRequestID: "pr1"})
+	doRequest(t, MergePullRequestHandler, "POST", "/pullRequest/merge", MergePullRequestRequest{PullRequestID: "missing"})
+	doRequest(t, SetUserActiveHandler, "POST", "/users/setIsActive", SetUserActiveRequest{UserID: "u3", IsActive: false})
+
+	got := relay()
+	want := []domain.WebhookEventType{domain.EventPullRequestCreated, domain.EventPullRequestMerged, domain.EventUserDeactivated}
+	if len(got) != len(want) {
+		t.Fatalf("expected events %v, got %d", want, len(got))
+	}
+	for i, r := range got {
+		if r.event.Type != want[i] || r.event.ID == "" {
+			t.Errorf("expected event %d to be %s, got %+v", i, want[i], r.event)
+		}
+	}
+	if pr, ok := got[0].event.Data["pull_request"].(map[string]any); !ok || pr["pull_request_id"] != "pr1" {
+		t.Errorf("expected pr1 in the payload, got %+v", got[0].event.Data)
+	}
+	if again := relay(); len(again) != 0 {
+		t.Errorf("expected published events not to be sent again, got %d", len(again))
+	}
+
+	w = doRequest(t, GetWebhookDeliveriesHandler, "GET", fmt.Sprintf("/webhooks/deliveries?id=%d", id), nil)
+	var deliveries WebhookDeliveriesResponse
+	json.Unmarshal(w.Body.Bytes(), &deliveries)
+	if len(deliveries.Deliveries) != 3 {
+		t.Fatalf("expected 3 deliveries, got %+v", deliveries)
+	}
+	for _, delivery := range deliveries.Deliveries {
+		if delivery.StatusCode == nil || *delivery.StatusCode != http.StatusOK || delivery.Error != nil || delivery.Attempt != 1 {
+			t.Errorf("expected a successful first attempt, got %+v", delivery)
+		}
+	}
+
+	w = doRequest(t, UnsubscribeWebhookHandler, "DELETE", fmt.Sprintf("/webhooks/unsubscribe?id=%d", id), nil)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status %d without admin token, got %d: %s", http.StatusForbidden, w.Code, w.Body.String())
+	}
+	w = doAdminRequest(t, UnsubscribeWebhookHandler, "DELETE", fmt.Sprintf("/webhooks/unsubscribe?id=%d", id), nil)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	w = doRequest(t, GetWebhookDeliveriesHandler, "GET", fmt.Sprintf("/webhooks/deliveries?id=%d", id), nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+}
+
+func TestReassignmentEvents_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	outboxFile := filepath.Join(t.TempDir(), "outbox.jsonl")
+	t.Setenv("OUTBOX_SINKS", "file")
+	t.Setenv("OUTBOX_FILE", outboxFile)
+	w := doRequest(t, AddTeamHandler, "POST", "/team/add", CreateTeamRequest{
+		TeamName: "backend",
+		Members: []domain.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Carol", IsActive: true},
+			{UserID: "u4", Username: "Dave", IsActive: true},
+		},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	w = doRequest(t, CreatePullRequestHandler, "POST", "/pullRequest/create", CreatePullRequestRequest{PullRequestID: "pr1", PullRequestName: "Add search", AuthorID: "u1"})
+	var created PullRequestWrapperResponse
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if len(created.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", created.PR.AssignedReviewers)
+	}
+	deactivated, removed := created.PR.AssignedReviewers[0], created.PR.AssignedReviewers[1]
+
+	// relay publishes the outbox and returns the events written since the
+	// last call.
+	var seen int
+	relay := func() []domain.WebhookEvent {
+		t.Helper()
+		if _, err := application.RelayOutbox(context.Background()); err != nil {
+			t.Fatalf("failed to relay outbox: %v", err)
+		}
+		data, err := os.ReadFile(outboxFile)
+		if err != nil {
+			t.Fatalf("failed to read outbox file: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		var events []domain.WebhookEvent
+		for _, line := range lines[seen:] {
+			var event domain.WebhookEvent
+			json.Unmarshal([]byte(line), &event)
+			events = append(events, event)
+		}
+		seen = len(lines)
+		return events
+	}
+	relay()
+
+	doRequest(t, SetUserActiveHandler, "POST", "/users/setIsActive", SetUserActiveRequest{UserID: deactivated, IsActive: false})
+	events := relay()
+	if len(events) != 2 || events[0].Type != domain.EventPullRequestReassigned || events[1].Type != domain.EventUserDeactivated {
+		t.Fatalf("expected pr.reassigned and user.deactivated, got %+v", events)
+	}
+	if events[0].Data["old_reviewer_id"] != deactivated || events[0].Data["reason"] != string(domain.AssignmentUserDeactivated) {
+		t.Errorf("expected the review of %s handed over, got %+v", deactivated, events[0].Data)
+	}
+
+	doRequest(t, SetUserActiveHandler, "POST", "/users/setIsActive", SetUserActiveRequest{UserID: deactivated, IsActive: false})
+	doRequest(t, BulkSetUserActiveHandler, "POST", "/users/bulkSetIsActive", BulkSetUserActiveRequest{Users: []UserActiveEntry{{UserID: deactivated, IsActive: false}}})
+	if events := relay(); len(events) != 0 {
+		t.Errorf("expected no events for an inactive user, got %+v", events)
+	}
+
+	doRequest(t, RemoveTeamMemberHandler, "POST", "/team/removeMember", RemoveTeamMemberRequest{TeamName: "backend", UserID: removed})
+	events = relay()
+	if len(events) != 1 || events[0].Type != domain.EventPullRequestReassigned {
+		t.Fatalf("expected pr.reassigned, got %+v", events)
+	}
+	if events[0].Data["old_reviewer_id"] != removed || events[0].Data["reason"] != string(domain.AssignmentMemberLeft) {
+		t.Errorf("expected the review of %s handed over, got %+v", removed, events[0].Data)
+	}
+}
+
+func TestProviderWebhooks_Memory(t *testing.T) {
+	useMemoryStorage(t)
+	addBackendTeam(t)
+	t.Setenv("GITHUB_WEBHOOK_SECRET", "github-secret")
+	t.Setenv("GITLAB_WEBHOOK_TOKEN", "gitlab-token")
+
+	deliver := func(handler http.HandlerFunc, fixture string, headers map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		body, err := os.ReadFile(filepath.Join("..", "..", "internal", "integration", "testdata", fixture))
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+		req := httptest.NewRequest("POST", "/integrations/webhook", bytes.NewReader(body))
+		for name, value := range headers {
+			if value == "sign" {
+				value = webhook.Sign("github-secret", body)
+			}
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+	github := func(event string, fixture string) *httptest.ResponseRecorder {
+		t.Helper()
+		return deliver(GitHubWebhookHandler, fixture, map[string]string{integration.GitHubEventHeader: event, integration.GitHubSignatureHeader: "sign"})
+	}
+	gitlab := func(fixture string) *httptest.ResponseRecorder {
+		t.Helper()
+		return deliver(GitLabWebhookHandler, fixture, map[string]string{integration.GitLabEventHeader: "Merge Request Hook", integration.GitLabTokenHeader: "gitlab-token"})
+	}
+	status := func(w *httptest.ResponseRecorder) domain.PullRequestStatus {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var resp PullRequestWrapperResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp.PR.Status
+	}
+
+	w := deliver(GitHubWebhookHandler, "github_pull_request_opened.json", map[string]string{integration.GitHubEventHeader: "pull_request", integration.GitHubSignatureHeader: "sha256=00"})
+	if w.Code != http.StatusUnauthorized || decodeErrorCode(t, w) != ErrorCodeInvalidSignature {
+		t.Errorf("expected INVALID_SIGNATURE, got %d: %s", w.Code, w.Body.String())
+	}
+	w = github("pull_request", "github_pull_request_opened.json")
+	if w.Code != http.StatusUnprocessableEntity || decodeErrorCode(t, w) != ErrorCodeAccountNotLinked {
+		t.Errorf("expected ACCOUNT_NOT_LINKED, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(t, LinkProviderAccountHandler, "POST", "/integrations/accounts/set", LinkProviderAccountRequest{Provider: "bitbucket", Login: "octocat", UserID: "u1"})
+	if w.Code != http.StatusBadRequest || decodeErrorCode(t, w) != ErrorCodeInvalidAccount {
+		t.Errorf("expected INVALID_ACCOUNT, got %d: %s", w.Code, w.Body.String())
+	}
+	w = doRequest(t, LinkProviderAccountHandler, "POST", "/integrations/accounts/set", LinkProviderAccountRequest{Provider: "github", Login: "octocat", UserID: "u9"})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for an unknown user, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+	for _, req := range []LinkProviderAccountRequest{
+		{Provider: "github", Login: "octocat", UserID: "u1"},
+		{Provider: "gitlab", Login: "jdoe", UserID: "u2"},
+	} {
+		if w := doRequest(t, LinkProviderAccountHandler, "POST", "/integrations/accounts/set", req); w.Code != http.StatusOK {
+			t.Fatalf("failed to link %+v: %d %s", req, w.Code, w.Body.String())
+		}
+	}
+	w = doRequest(t, GetProviderAccountsHandler, "GET", "/integrations/accounts/list?provider=github", nil)
+	var accounts ProviderAccountsResponse
+	json.Unmarshal(w.Body.Bytes(), &accounts)
+	if len(accounts.Accounts) != 1 || accounts.Accounts[0] != (domain.ProviderAccount{Provider: domain.ProviderGitHub, Login: "octocat", UserID: "u1"}) {
+		t.Errorf("expected the octocat account, got %+v", accounts)
+	}
+
+	if got := status(github("pull_request", "github_pull_request_opened.json")); got != "OPEN" {
+		t.Errorf("expected the opened PR to be open, got %s", got)
+	}
+	if got := status(github("pull_request", "github_pull_request_opened.json")); got != "OPEN" {
+		t.Errorf("expected a redelivered opened event to return the PR, got %s", got)
+	}
+	if w := github("ping", "github_ping.json"); w.Code != http.StatusNoContent {
+		t.Errorf("expected ping to be acknowledged with %d, got %d", http.StatusNoContent, w.Code)
+	}
+	// The merge has happened on GitHub, so the unmet policy does not stop it.
+	w = doRequest(t, SetMergePolicyHandler, "POST", "/team/setMergePolicy", SetMergePolicyRequest{TeamName: "backend", MinApprovals: 2})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got := status(github("pull_request", "github_pull_request_merged.json")); got != "MERGED" {
+		t.Errorf("expected the PR to be merged, got %s", got)
+	}
+
+	w = doRequest(t, GetAuditEventsHandler, "GET", "/audit/list?entity_id="+url.QueryEscape("github:octo-org/search#42"), nil)
+	var audit AuditEventsResponse
+	json.Unmarshal(w.Body.Bytes(), &audit)
+	if len(audit.Events) != 2 || audit.Events[0].Actor == nil || *audit.Events[0].Actor != "github:hubot" {
+		t.Errorf("expected the merge by an unlinked login to be attributed to github:hubot, got %+v", audit.Events)
+	}
+	if len(audit.Events) == 2 && (audit.Events[1].Actor == nil || *audit.Events[1].Actor != "u1") {
+		t.Errorf("expected the PR opened by octocat to be attributed to u1, got %+v", audit.Events[1])
+	}
+	if len(audit.Events) > 0 && audit.Events[0].Details["override_reason"] != "merged on github" {
+		t.Errorf("expected the merge to be recorded as merged on github, got %+v", audit.Events[0])
+	}
+
+	oversized := bytes.Repeat([]byte(" "), maxProviderWebhookBody+1)
+	for _, handler := range []http.HandlerFunc{GitHubWebhookHandler, GitLabWebhookHandler} {
+		req := httptest.NewRequest("POST", "/integrations/webhook", bytes.NewReader(oversized))
+		req.Header.Set(integration.GitLabTokenHeader, "gitlab-token")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusRequestEntityTooLarge || decodeErrorCode(t, w) != ErrorCodePayloadTooLarge {
+			t.Errorf("expected PAYLOAD_TOO_LARGE, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	w = deliver(GitLabWebhookHandler, "gitlab_merge_request_open.json", map[string]string{integration.GitLabEventHeader: "Merge Request Hook", integration.GitLabTokenHeader: "wrong"})
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d for a wrong token, got %d", http.StatusUnauthorized, w.Code)
+	}
+	if got := status(gitlab("gitlab_merge_request_open.json")); got != "DRAFT" {
+		t.Errorf("expected the draft MR to be a draft, got %s", got)
+	}
+	if w := gitlab("gitlab_merge_request_update.json"); w.Code != http.StatusNoContent {
+		t.Errorf("expected an unrelated update to be acknowledged with %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if got := status(gitlab("gitlab_merge_request_ready.json")); got != "OPEN" {
+		t.Errorf("expected the MR to be open once ready, got %s", got)
+	}
+	if got := status(gitlab("gitlab_merge_request_merge.json")); got != "MERGED" {
+		t.Errorf("expected the MR to be merged, got %s", got)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/application"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+func SetReviewerPoolHandler(w http.ResponseWriter, r *http.Request) {
+	var req SetReviewerPoolRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+
+	result, err := application.SetReviewerPool(r.Context(), requestToDomainReviewerPool(req))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPool) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidPool, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ReviewerPoolWrapperResponse{
+		Pool: result,
+	})
+}
+
+func GetReviewerPoolHandler(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "name parameter is required")
+		return
+	}
+
+	result, err := application.GetReviewerPool(r.Context(), name)
+	if err != nil {
+		if errors.Is(err, domain.ErrPoolNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ReviewerPoolWrapperResponse{
+		Pool: result,
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -19,6 +20,10 @@ func CreatePullRequestHandler(w http.ResponseWriter, r *http.Request) {
 	pr := requestToDomainPR(req)
 	result, err := application.CreatePullRequest(r.Context(), pr)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidReviewers) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidReviewers, err.Error())
+			return
+		}
 		if errors.Is(err, domain.ErrPRExists) {
 			writeError(w, http.StatusConflict, ErrorCodePRExists, "PR id already exists")
 			return
@@ -44,8 +49,31 @@ func MergePullRequestHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	pr := requestToDomainPRForMerge(req)
-	result, err := application.MergePullRequest(r.Context(), pr)
+	var result domain.PullRequest
+	var err error
+	if req.Override {
+		if !application.IsAdmin(r.Header.Get("X-Admin-Token")) {
+			writeError(w, http.StatusForbidden, ErrorCodeForbidden, "admin token required for override")
+			return
+		}
+		if req.Reason == "" {
+			writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "reason is required for override")
+			return
+		}
+		result, err = application.ForceMergePullRequest(r.Context(), pr, req.Reason)
+	} else {
+		result, err = application.MergePullRequest(r.Context(), pr)
+	}
 	if err != nil {
+		var policyErr *domain.PolicyError
+		if errors.As(err, &policyErr) {
+			writePolicyError(w, policyErr)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			writeError(w, http.StatusConflict, ErrorCodeInvalidTransition, "only open PR can be merged")
+			return
+		}
 		if errors.Is(err, domain.ErrNotFound) {
 			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
 			return
@@ -72,6 +100,10 @@ func ReassignPullRequestHandler(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusConflict, ErrorCodePRMerged, "cannot reassign on merged PR")
 			return
 		}
+		if errors.Is(err, domain.ErrPRNotOpen) {
+			writeError(w, http.StatusConflict, ErrorCodePRNotOpen, "cannot reassign on draft or closed PR")
+			return
+		}
 		if errors.Is(err, domain.ErrNotAssigned) {
 			writeError(w, http.StatusConflict, ErrorCodeNotAssigned, "reviewer is not assigned to this PR")
 			return
@@ -95,3 +127,114 @@ func ReassignPullRequestHandler(w http.ResponseWriter, r *http.Request) {
 		ReplacedBy: newReviewer,
 	})
 }
+
+func SubmitReviewHandler(w http.ResponseWriter, r *http.Request) {
+	var req SubmitReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+
+	state, err := domain.ParseReviewState(req.State)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidReviewState, err.Error())
+		return
+	}
+
+	result, err := application.SubmitReview(r.Context(), req.PullRequestID, domain.Review{
+		ReviewerID: req.ReviewerID,
+		State:      state,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrPRMerged) {
+			writeError(w, http.StatusConflict, ErrorCodePRMerged, "cannot review merged PR")
+			return
+		}
+		if errors.Is(err, domain.ErrPRNotOpen) {
+			writeError(w, http.StatusConflict, ErrorCodePRNotOpen, "cannot review draft or closed PR")
+			return
+		}
+		if errors.Is(err, domain.ErrNotAssigned) {
+			writeError(w, http.StatusConflict, ErrorCodeNotAssigned, "reviewer is not assigned to this PR")
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PullRequestWrapperResponse{
+		PR: domainPRToResponse(result),
+	})
+}
+
+func GetPullRequestHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	pullRequestID := r.URL.Query().Get("pull_request_id")
+	if pullRequestID == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "pull_request_id parameter is required")
+		return
+	}
+
+	history, err := application.GetPullRequestHistory(r.Context(), pullRequestID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+	if history == nil {
+		history = []domain.AssignmentEvent{}
+	}
+
+	writeJSON(w, http.StatusOK, PullRequestHistoryResponse{
+		PullRequestID: pullRequestID,
+		History:       history,
+	})
+}
+
+func MarkPullRequestReadyHandler(w http.ResponseWriter, r *http.Request) {
+	pullRequestTransitionHandler(w, r, application.MarkPullRequestReady)
+}
+
+func ClosePullRequestHandler(w http.ResponseWriter, r *http.Request) {
+	pullRequestTransitionHandler(w, r, application.ClosePullRequest)
+}
+
+func ReopenPullRequestHandler(w http.ResponseWriter, r *http.Request) {
+	pullRequestTransitionHandler(w, r, application.ReopenPullRequest)
+}
+
+func pullRequestTransitionHandler(w http.ResponseWriter, r *http.Request, transition func(context.Context, string) (domain.PullRequest, error)) {
+	var req PullRequestIDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+
+	result, err := transition(r.Context(), req.PullRequestID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPRMerged) {
+			writeError(w, http.StatusConflict, ErrorCodePRMerged, "cannot change status of merged PR")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTransition) {
+			writeError(w, http.StatusConflict, ErrorCodeInvalidTransition, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrNoPossibleAssigners) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PullRequestWrapperResponse{
+		PR: domainPRToResponse(result),
+	})
+}
@@ -169,7 +169,7 @@ func TestDomainPRToResponse(t *testing.T) {
 			AuthorID: "user1",
 			Status:   domain.Open,
 		},
-		AssignedReviewers: "[user2, user3]",
+		AssignedReviewers: []string{"user2", "user3"},
 		CreatedAt:         &now,
 		MergedAt:          nil,
 	}
@@ -2,11 +2,56 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/zemld/pr-manager/pr-manager/internal/application"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
 )
 
 func GetStatsHandler(w http.ResponseWriter, r *http.Request) {
-	stats := application.GetStats(r.Context())
+	query := r.URL.Query()
+
+	filter := domain.StatsFilter{}
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "from must be an RFC 3339 time or a YYYY-MM-DD date")
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "to must be an RFC 3339 time or a YYYY-MM-DD date")
+		return
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "from must be before to")
+		return
+	}
+	if team := query.Get("team"); team != "" {
+		filter.TeamName = &team
+	}
+	if userID := query.Get("user_id"); userID != "" {
+		filter.UserID = &userID
+	}
+
+	stats, err := application.GetStats(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
 	writeJSON(w, http.StatusOK, stats)
 }
+
+// parseTimeParam parses a bound of a time window. A date means its
+// midnight in UTC.
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/zemld/pr-manager/pr-manager/internal/application"
 	"github.com/zemld/pr-manager/pr-manager/internal/domain"
@@ -100,3 +102,240 @@ func DeleteTeamHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func GetDeletedTeamsHandler(w http.ResponseWriter, r *http.Request) {
+	teams, err := application.GetDeletedTeams(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+	if teams == nil {
+		teams = []domain.DeletedTeam{}
+	}
+
+	writeJSON(w, http.StatusOK, DeletedTeamsResponse{
+		Teams: teams,
+	})
+}
+
+func RestoreTeamHandler(w http.ResponseWriter, r *http.Request) {
+	var req RestoreTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+	if req.TeamName == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "team_name is required")
+		return
+	}
+
+	team, updated, err := application.RestoreTeam(r.Context(), req.TeamName, req.ReassignReviewers)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamExists) {
+			writeError(w, http.StatusConflict, ErrorCodeTeamExists, "team_name already exists")
+			return
+		}
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	prs := make([]PullRequestResponse, 0, len(updated))
+	for _, pr := range updated {
+		prs = append(prs, domainPRToResponse(pr))
+	}
+	writeJSON(w, http.StatusOK, RestoreTeamResponse{
+		Team:         team,
+		PullRequests: prs,
+	})
+}
+
+func PurgeTeamsHandler(w http.ResponseWriter, r *http.Request) {
+	if !application.IsAdmin(r.Header.Get("X-Admin-Token")) {
+		writeError(w, http.StatusForbidden, ErrorCodeForbidden, "admin token required")
+		return
+	}
+
+	var retention *time.Duration
+	if olderThanDays := r.URL.Query().Get("older_than_days"); olderThanDays != "" {
+		days, err := strconv.Atoi(olderThanDays)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "older_than_days must be an integer")
+			return
+		}
+		if days < 1 && r.URL.Query().Get("confirm") != "true" {
+			writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "older_than_days below 1 purges recently deleted teams and requires confirm=true")
+			return
+		}
+		value := time.Duration(days) * 24 * time.Hour
+		retention = &value
+	}
+
+	purged, err := application.PurgeDeletedTeams(r.Context(), retention)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRetention) {
+			writeError(w, http.StatusBadRequest, ErrorCodeNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+	if purged == nil {
+		purged = []string{}
+	}
+
+	writeJSON(w, http.StatusOK, PurgeTeamsResponse{
+		PurgedTeams: purged,
+	})
+}
+
+func SetMergePolicyHandler(w http.ResponseWriter, r *http.Request) {
+	var req SetMergePolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+
+	result, err := application.SetMergePolicy(r.Context(), requestToDomainMergePolicy(req))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPolicy) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidPolicy, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, MergePolicyWrapperResponse{
+		Policy: result,
+	})
+}
+
+func GetMergePolicyHandler(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "name parameter is required")
+		return
+	}
+
+	result, err := application.GetMergePolicy(r.Context(), teamName)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, MergePolicyWrapperResponse{
+		Policy: result,
+	})
+}
+
+func SetTeamSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	var req SetTeamSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+
+	result, err := application.SetTeamSettings(r.Context(), requestToDomainTeamSettings(req))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidReviewers) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidReviewers, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidSLA) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidSLA, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrTeamNotFound) || errors.Is(err, domain.ErrPoolNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TeamSettingsWrapperResponse{
+		Settings: result,
+	})
+}
+
+func GetTeamSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "name parameter is required")
+		return
+	}
+
+	result, err := application.GetTeamSettings(r.Context(), teamName)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TeamSettingsWrapperResponse{
+		Settings: result,
+	})
+}
+
+func SetCodeownersHandler(w http.ResponseWriter, r *http.Request) {
+	var req SetCodeownersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+
+	result, err := application.SetCodeowners(r.Context(), requestToDomainCodeowners(req))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCodeowners) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidCodeowners, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CodeownersWrapperResponse{
+		Codeowners: result,
+	})
+}
+
+func GetCodeownersHandler(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("name")
+	if teamName == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "name parameter is required")
+		return
+	}
+
+	result, err := application.GetCodeowners(r.Context(), teamName)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, CodeownersWrapperResponse{
+		Codeowners: result,
+	})
+}
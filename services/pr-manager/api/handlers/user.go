@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/zemld/pr-manager/pr-manager/internal/application"
 	"github.com/zemld/pr-manager/pr-manager/internal/domain"
@@ -22,7 +23,9 @@ func SetUserActiveHandler(w http.ResponseWriter, r *http.Request) {
 		IsActive: req.IsActive,
 	}
 
-	result, err := application.UpdateUserStatus(r.Context(), user)
+	reassign := req.Reassign == nil || *req.Reassign
+
+	result, reassignments, err := application.UpdateUserStatus(r.Context(), user, reassign)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrNotFound) {
 			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
@@ -32,8 +35,114 @@ func SetUserActiveHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, UserWrapperResponse{
-		User: result,
+	writeJSON(w, http.StatusOK, UserStatusResponse{
+		User:          result,
+		Reassignments: reassignments,
+	})
+}
+
+func BulkSetUserActiveHandler(w http.ResponseWriter, r *http.Request) {
+	var req BulkSetUserActiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+	if len(req.Users) == 0 {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "users must not be empty")
+		return
+	}
+
+	users := make([]domain.User, len(req.Users))
+	seen := make(map[string]bool, len(req.Users))
+	for i, entry := range req.Users {
+		if entry.UserID == "" {
+			writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "user_id is required")
+			return
+		}
+		if seen[entry.UserID] {
+			writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "duplicate user_id "+entry.UserID)
+			return
+		}
+		seen[entry.UserID] = true
+		users[i] = domain.User{UserID: entry.UserID, IsActive: entry.IsActive}
+	}
+	reassign := req.Reassign == nil || *req.Reassign
+
+	results, err := application.BulkUpdateUserStatus(r.Context(), users, reassign)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	response := BulkUserStatusResponse{Results: make([]UserStatusResult, len(results))}
+	for i, result := range results {
+		response.Results[i] = UserStatusResult{UserID: result.User.UserID}
+		if result.Err != nil {
+			response.Results[i].Error = &ErrorDetail{Code: ErrorCodeNotFound, Message: "user not found"}
+			continue
+		}
+		response.Results[i].User = &result.User
+		response.Results[i].Reassignments = result.Reassignments
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func AddUserAbsenceHandler(w http.ResponseWriter, r *http.Request) {
+	var req AddUserAbsenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+	if req.UserID == "" || req.StartsAt == nil || req.EndsAt == nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "user_id, starts_at and ends_at are required")
+		return
+	}
+
+	absence := domain.Absence{
+		UserID:   req.UserID,
+		StartsAt: *req.StartsAt,
+		EndsAt:   *req.EndsAt,
+	}
+
+	result, err := application.AddUserAbsence(r.Context(), absence)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAbsence) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidAbsence, err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, AbsenceWrapperResponse{
+		Absence: result,
+	})
+}
+
+func GetUserAbsencesHandler(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "user_id parameter is required")
+		return
+	}
+
+	absences, err := application.GetUserAbsences(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, UserAbsencesResponse{
+		UserID:   userID,
+		Absences: absences,
 	})
 }
 
@@ -44,7 +153,17 @@ func GetUserReviewsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	prs, err := application.GetUserPullRequestsReviews(r.Context(), userID)
+	pendingOnly := false
+	if pending := r.URL.Query().Get("pending"); pending != "" {
+		var err error
+		pendingOnly, err = strconv.ParseBool(pending)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "pending parameter must be a boolean")
+			return
+		}
+	}
+
+	prs, err := application.GetUserPullRequestsReviews(r.Context(), userID, pendingOnly)
 	if err != nil {
 		log.Printf("error getting user pull requests reviews: %v\n", err)
 		prs = []domain.PullRequest{}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/application"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+func SubscribeWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	if !application.IsAdmin(r.Header.Get("X-Admin-Token")) {
+		writeError(w, http.StatusForbidden, ErrorCodeForbidden, "admin token required")
+		return
+	}
+	var req SubscribeWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "invalid request body")
+		return
+	}
+
+	result, err := application.SubscribeWebhook(r.Context(), requestToDomainWebhookSubscription(req))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidWebhook) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidWebhook, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, WebhookWrapperResponse{
+		Subscription: result,
+	})
+}
+
+func UnsubscribeWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	if !application.IsAdmin(r.Header.Get("X-Admin-Token")) {
+		writeError(w, http.StatusForbidden, ErrorCodeForbidden, "admin token required")
+		return
+	}
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "id parameter must be a webhook id")
+		return
+	}
+
+	err = application.UnsubscribeWebhook(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, domain.ErrWebhookNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func GetWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	id, err := strconv.ParseInt(query.Get("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "id parameter must be a webhook id")
+		return
+	}
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, ErrorCodeNotFound, "limit must be a positive integer")
+			return
+		}
+	}
+
+	deliveries, err := application.GetWebhookDeliveries(r.Context(), id, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrWebhookNotFound) {
+			writeError(w, http.StatusNotFound, ErrorCodeNotFound, "resource not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, ErrorCodeNotFound, err.Error())
+		return
+	}
+	if deliveries == nil {
+		deliveries = []domain.WebhookDelivery{}
+	}
+
+	writeJSON(w, http.StatusOK, WebhookDeliveriesResponse{
+		SubscriptionID: id,
+		Deliveries:     deliveries,
+	})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,29 +12,80 @@ import (
 )
 
 func main() {
-	ctx := context.Background()
-	if err := application.InitializeDB(ctx); err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+	storage := flag.String("storage", "postgres", "storage backend: postgres or memory")
+	flag.Parse()
+
+	switch *storage {
+	case "memory":
+		application.UseMemoryStorage()
+		log.Println("Using in-memory storage, data will be lost on restart")
+	case "postgres":
+		ctx := context.Background()
+		applied, err := application.MigrateUp(ctx)
+		if err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		for _, migration := range applied {
+			log.Printf("Applied migration %04d_%s\n", migration.Version, migration.Name)
+		}
+	default:
+		log.Fatalf("Unknown storage backend: %s", *storage)
 	}
 
+	go application.RunEscalationScheduler(context.Background())
+	go application.RunOutboxRelay(context.Background())
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("POST /team/add", handlers.AddTeamHandler)
 	mux.HandleFunc("GET /team/get", handlers.GetTeamHandler)
 	mux.HandleFunc("DELETE /team/delete", handlers.DeleteTeamHandler)
+	mux.HandleFunc("GET /team/listDeleted", handlers.GetDeletedTeamsHandler)
+	mux.HandleFunc("POST /team/restore", handlers.RestoreTeamHandler)
+	mux.HandleFunc("DELETE /team/purge", handlers.PurgeTeamsHandler)
+	mux.HandleFunc("POST /team/addMember", handlers.AddTeamMemberHandler)
+	mux.HandleFunc("POST /team/removeMember", handlers.RemoveTeamMemberHandler)
+	mux.HandleFunc("POST /team/moveMember", handlers.MoveTeamMemberHandler)
+	mux.HandleFunc("POST /team/setMergePolicy", handlers.SetMergePolicyHandler)
+	mux.HandleFunc("GET /team/getMergePolicy", handlers.GetMergePolicyHandler)
+	mux.HandleFunc("POST /team/setSettings", handlers.SetTeamSettingsHandler)
+	mux.HandleFunc("GET /team/getSettings", handlers.GetTeamSettingsHandler)
+	mux.HandleFunc("POST /team/setCodeowners", handlers.SetCodeownersHandler)
+	mux.HandleFunc("GET /team/getCodeowners", handlers.GetCodeownersHandler)
+	mux.HandleFunc("POST /pool/set", handlers.SetReviewerPoolHandler)
+	mux.HandleFunc("GET /pool/get", handlers.GetReviewerPoolHandler)
 
 	mux.HandleFunc("POST /users/setIsActive", handlers.SetUserActiveHandler)
+	mux.HandleFunc("POST /users/bulkSetIsActive", handlers.BulkSetUserActiveHandler)
 	mux.HandleFunc("GET /users/getReview", handlers.GetUserReviewsHandler)
+	mux.HandleFunc("POST /users/absence", handlers.AddUserAbsenceHandler)
+	mux.HandleFunc("GET /users/absences", handlers.GetUserAbsencesHandler)
 
 	mux.HandleFunc("POST /pullRequest/create", handlers.CreatePullRequestHandler)
 	mux.HandleFunc("POST /pullRequest/merge", handlers.MergePullRequestHandler)
 	mux.HandleFunc("POST /pullRequest/reassign", handlers.ReassignPullRequestHandler)
+	mux.HandleFunc("POST /pullRequest/review", handlers.SubmitReviewHandler)
+	mux.HandleFunc("POST /pullRequest/ready", handlers.MarkPullRequestReadyHandler)
+	mux.HandleFunc("POST /pullRequest/close", handlers.ClosePullRequestHandler)
+	mux.HandleFunc("POST /pullRequest/reopen", handlers.ReopenPullRequestHandler)
+	mux.HandleFunc("GET /pullRequest/history", handlers.GetPullRequestHistoryHandler)
 
 	mux.HandleFunc("GET /stats/get", handlers.GetStatsHandler)
 
+	mux.HandleFunc("GET /audit/list", handlers.GetAuditEventsHandler)
+
+	mux.HandleFunc("POST /webhooks/subscribe", handlers.SubscribeWebhookHandler)
+	mux.HandleFunc("DELETE /webhooks/unsubscribe", handlers.UnsubscribeWebhookHandler)
+	mux.HandleFunc("GET /webhooks/deliveries", handlers.GetWebhookDeliveriesHandler)
+
+	mux.HandleFunc("POST /integrations/github/webhook", handlers.GitHubWebhookHandler)
+	mux.HandleFunc("POST /integrations/gitlab/webhook", handlers.GitLabWebhookHandler)
+	mux.HandleFunc("POST /integrations/accounts/set", handlers.LinkProviderAccountHandler)
+	mux.HandleFunc("GET /integrations/accounts/list", handlers.GetProviderAccountsHandler)
+
 	port := "8080"
 	fmt.Printf("Server starting on port %s\n", port)
-	if err := http.ListenAndServe(":"+port, mux); err != nil {
+	if err := http.ListenAndServe(":"+port, handlers.WithActor(mux)); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
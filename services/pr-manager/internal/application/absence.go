@@ -0,0 +1,38 @@
+package application
+
+import (
+	"context"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+)
+
+func AddUserAbsence(ctx context.Context, absence domain.Absence) (domain.Absence, error) {
+	var result domain.Absence
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		absenceManager := manager.NewAbsenceManager(storage.UserStorage, storage.AbsenceStorage)
+		var err error
+		result, err = absenceManager.AddAbsence(absence)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditUserAbsenceAdded, domain.AuditEntityUser, result.UserID, map[string]any{
+			"absence_id": result.ID,
+			"starts_at":  result.StartsAt,
+			"ends_at":    result.EndsAt,
+		})
+	}, false)
+	return result, err
+}
+
+func GetUserAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
+	var result []domain.Absence
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		absenceManager := manager.NewAbsenceManager(storage.UserStorage, storage.AbsenceStorage)
+		var err error
+		result, err = absenceManager.GetAbsences(userID)
+		return err
+	}, true)
+	return result, err
+}
@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type actorKey struct{}
+
+// WithActor returns a context identifying who performs the operations run
+// with it. They are attributed to the actor in the audit log.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func actorFrom(ctx context.Context) *string {
+	actor, ok := ctx.Value(actorKey{}).(string)
+	if !ok || actor == "" {
+		return nil
+	}
+	return &actor
+}
+
+// recordAudit appends an audit event in the transaction of the change it
+// records, so the event exists if and only if the change does.
+func recordAudit(ctx context.Context, storage *db.Storage, action domain.AuditAction, entityType domain.AuditEntity, entityID string, details map[string]any) error {
+	return storage.AuditStorage.Insert(domain.AuditEvent{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Actor:      actorFrom(ctx),
+		Details:    details,
+	})
+}
+
+// GetAuditEvents returns the audit events selected by the filter, newest
+// first. Without a limit at most defaultAuditLimit events are returned.
+func GetAuditEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	filter.Limit = min(filter.Limit, maxAuditLimit)
+
+	var result []domain.AuditEvent
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		var err error
+		result, err = storage.AuditStorage.Select(filter)
+		return err
+	}, true)
+	return result, err
+}
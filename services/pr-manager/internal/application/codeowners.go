@@ -0,0 +1,36 @@
+package application
+
+import (
+	"context"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+)
+
+func SetCodeowners(ctx context.Context, file domain.Codeowners) (domain.Codeowners, error) {
+	var result domain.Codeowners
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		codeownersManager := manager.NewCodeownersManager(storage.TeamStorage, storage.CodeownersStorage)
+		var err error
+		result, err = codeownersManager.SetCodeowners(file)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditCodeownersUpdated, domain.AuditEntityTeam, result.TeamName, map[string]any{
+			"codeowners": result,
+		})
+	}, false)
+	return result, err
+}
+
+func GetCodeowners(ctx context.Context, teamName string) (domain.Codeowners, error) {
+	var result domain.Codeowners
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		codeownersManager := manager.NewCodeownersManager(storage.TeamStorage, storage.CodeownersStorage)
+		var err error
+		result, err = codeownersManager.GetCodeowners(teamName)
+		return err
+	}, true)
+	return result, err
+}
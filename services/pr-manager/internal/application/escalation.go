@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+)
+
+const defaultEscalationInterval = 5 * time.Minute
+
+// escalationLockKey identifies the pg_try_advisory_xact_lock taken by every
+// escalation run, so that of several replicas only one acts on stale reviews
+// at a time.
+const escalationLockKey int64 = 0x70722d657363616c
+
+// EscalateStaleReviews acts on the reviews pending for longer than the review
+// SLA of the author's team and returns what was done. A review that fails to
+// escalate is logged and skipped with its changes undone, so that it does not
+// hold back the others. If another replica is escalating at the same time,
+// nothing is done.
+func EscalateStaleReviews(ctx context.Context) ([]domain.Escalation, error) {
+	var result []domain.Escalation
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		locked, err := storage.LockStorage.TryLock(escalationLockKey)
+		if err != nil || !locked {
+			return err
+		}
+
+		reviews, err := storage.EscalationStorage.SelectStaleReviews()
+		if err != nil {
+			return err
+		}
+		pullRequestManager := newPullRequestManager(storage)
+		for _, review := range reviews {
+			var escalation domain.Escalation
+			err := storage.SavepointStorage.Savepoint(func() error {
+				var err error
+				escalation, err = escalateStaleReview(ctx, storage, pullRequestManager, review)
+				return err
+			})
+			if err != nil {
+				log.Printf("Failed to escalate the stale review of %s by %s: %v\n", review.PullRequestID, review.ReviewerID, err)
+				continue
+			}
+			result = append(result, escalation)
+		}
+		return nil
+	}, false)
+	return result, err
+}
+
+func escalateStaleReview(ctx context.Context, storage *db.Storage, pullRequestManager *manager.PullRequestManager, review domain.StaleReview) (domain.Escalation, error) {
+	escalation, err := pullRequestManager.EscalateStaleReview(review)
+	if err != nil {
+		return domain.Escalation{}, err
+	}
+	err = recordAudit(ctx, storage, domain.AuditReviewEscalated, domain.AuditEntityPullRequest, escalation.PullRequestID, map[string]any{
+		"reviewer_id": escalation.ReviewerID,
+		"action":      escalation.Action,
+		"assignee_id": escalation.AssigneeID,
+	})
+	if err != nil || escalation.Action != domain.SLAReassign {
+		return escalation, err
+	}
+	reassignment := domain.Reassignment{PullRequestID: escalation.PullRequestID}
+	if escalation.AssigneeID != nil {
+		reassignment.ReplacedBy = *escalation.AssigneeID
+	}
+	err = publishReassignments(storage, pullRequestManager, escalation.ReviewerID, []domain.Reassignment{reassignment}, domain.AssignmentEscalated)
+	return escalation, err
+}
+
+// RunEscalationScheduler escalates stale reviews every interval set by
+// REVIEW_SLA_CHECK_INTERVAL until ctx is done. Every replica runs it; the
+// advisory lock lets one of them act per run.
+func RunEscalationScheduler(ctx context.Context) {
+	interval := escalationInterval()
+	if interval == 0 {
+		log.Println("Review SLA escalation is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			escalations, err := EscalateStaleReviews(ctx)
+			if err != nil {
+				log.Printf("Failed to escalate stale reviews: %v\n", err)
+				continue
+			}
+			for _, escalation := range escalations {
+				log.Printf("Stale review of %s by %s: %s\n", escalation.PullRequestID, escalation.ReviewerID, escalation.Action)
+			}
+		}
+	}
+}
+
+func escalationInterval() time.Duration {
+	value := os.Getenv("REVIEW_SLA_CHECK_INTERVAL")
+	if value == "" {
+		return defaultEscalationInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Printf("invalid REVIEW_SLA_CHECK_INTERVAL %q, using %s\n", value, defaultEscalationInterval)
+		return defaultEscalationInterval
+	}
+	return interval
+}
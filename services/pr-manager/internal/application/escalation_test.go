@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/memory"
+)
+
+func TestEscalateStaleReviews_SkipsBrokenReview(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := memory.NewStoreWithClock(func() time.Time { return now })
+	backend = memoryBackend{store: store}
+	t.Cleanup(UsePostgresStorage)
+	ctx := context.Background()
+
+	teams := []domain.Team{
+		{TeamName: "backend", Members: []domain.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Carol", IsActive: true},
+		}},
+		{TeamName: "frontend", Members: []domain.TeamMember{
+			{UserID: "f1", Username: "Frank", IsActive: true},
+		}},
+	}
+	for _, team := range teams {
+		if _, err := AddTeam(ctx, team); err != nil {
+			t.Fatalf("failed to add team %s: %v", team.TeamName, err)
+		}
+	}
+	if _, err := SetTeamSettings(ctx, domain.TeamSettings{TeamName: "backend", ReviewersRequired: 2, ReviewSLAHours: 24, SLAAction: domain.SLAReassign}); err != nil {
+		t.Fatalf("failed to set team settings: %v", err)
+	}
+	for _, id := range []string{"pr1", "pr2"} {
+		pr := domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: id, Name: id, AuthorID: "u1"}}
+		if _, err := CreatePullRequest(ctx, pr); err != nil {
+			t.Fatalf("failed to create %s: %v", id, err)
+		}
+	}
+
+	// The review of pr1 by f1 cannot be escalated: the team of f1 is gone.
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		prID := "pr1"
+		prs, err := storage.PullRequestStorage.Select(&prID)
+		if err != nil {
+			return err
+		}
+		pr := prs[0]
+		pr.AssignedReviewers = []string{"f1"}
+		if err := storage.PullRequestStorage.Reassign(pr, domain.AssignmentReassign); err != nil {
+			return err
+		}
+		return storage.TeamStorage.Delete("frontend")
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to break the review: %v", err)
+	}
+
+	now = now.Add(25 * time.Hour)
+	escalations, err := EscalateStaleReviews(ctx)
+	if err != nil {
+		t.Fatalf("expected the broken review to be skipped, got %v", err)
+	}
+	if len(escalations) != 2 || slices.ContainsFunc(escalations, func(e domain.Escalation) bool { return e.PullRequestID != "pr2" }) {
+		t.Errorf("expected both reviews of pr2 escalated, got %+v", escalations)
+	}
+
+	history, err := GetPullRequestHistory(ctx, "pr1")
+	if err != nil {
+		t.Fatalf("failed to get history: %v", err)
+	}
+	if last := history[len(history)-1]; last.Reason == domain.AssignmentEscalated {
+		t.Errorf("expected no escalation recorded for pr1, got %+v", last)
+	}
+}
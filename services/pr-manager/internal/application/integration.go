@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+)
+
+// ProviderWebhookSecret returns the secret webhooks of the provider are
+// verified with: GITHUB_WEBHOOK_SECRET or GITLAB_WEBHOOK_TOKEN. Webhooks of a
+// provider without a secret are rejected.
+func ProviderWebhookSecret(provider domain.Provider) string {
+	switch provider {
+	case domain.ProviderGitHub:
+		return os.Getenv("GITHUB_WEBHOOK_SECRET")
+	case domain.ProviderGitLab:
+		return os.Getenv("GITLAB_WEBHOOK_TOKEN")
+	default:
+		return ""
+	}
+}
+
+func LinkProviderAccount(ctx context.Context, account domain.ProviderAccount) (domain.ProviderAccount, error) {
+	var result domain.ProviderAccount
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		accountManager := manager.NewAccountManager(storage.AccountStorage, storage.UserStorage)
+		var err error
+		result, err = accountManager.Link(account)
+		return err
+	}, false)
+	return result, err
+}
+
+func GetProviderAccounts(ctx context.Context, provider domain.Provider) ([]domain.ProviderAccount, error) {
+	var result []domain.ProviderAccount
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		accountManager := manager.NewAccountManager(storage.AccountStorage, storage.UserStorage)
+		var err error
+		result, err = accountManager.GetAccounts(provider)
+		return err
+	}, true)
+	return result, err
+}
+
+// IngestPullRequestEvent applies a pull request event received from a
+// provider and returns the pull request. The operations are attributed to
+// the user the login that caused the event is linked to, or to the login
+// itself, as provider:login, when it is not linked. Providers redeliver events, so
+// opening a pull request that exists already changes nothing, as does
+// repeating any other action. A merge on the provider has happened already, so
+// it is recorded as a forced merge without checking the merge policy.
+func IngestPullRequestEvent(ctx context.Context, event domain.ProviderPullRequestEvent) (domain.PullRequest, error) {
+	if event.SenderLogin != "" {
+		actor, err := providerActor(ctx, event.Provider, event.SenderLogin)
+		if err != nil {
+			return domain.PullRequest{}, err
+		}
+		ctx = WithActor(ctx, actor)
+	}
+
+	switch event.Action {
+	case domain.ProviderOpened:
+		return openProviderPullRequest(ctx, event)
+	case domain.ProviderReadyForReview:
+		return MarkPullRequestReady(ctx, event.PullRequestID)
+	case domain.ProviderMerged:
+		pullRequest := domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: event.PullRequestID}}
+		return ForceMergePullRequest(ctx, pullRequest, fmt.Sprintf("merged on %s", event.Provider))
+	case domain.ProviderClosed:
+		return ClosePullRequest(ctx, event.PullRequestID)
+	case domain.ProviderReopened:
+		return ReopenPullRequest(ctx, event.PullRequestID)
+	default:
+		return domain.PullRequest{}, fmt.Errorf("unsupported provider action %q", event.Action)
+	}
+}
+
+// providerActor returns the ID of the user the provider login is linked to,
+// or provider:login when it is not linked.
+func providerActor(ctx context.Context, provider domain.Provider, login string) (string, error) {
+	var actor string
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		accountManager := manager.NewAccountManager(storage.AccountStorage, storage.UserStorage)
+		var err error
+		actor, err = accountManager.Resolve(provider, login)
+		return err
+	}, true)
+	if errors.Is(err, domain.ErrAccountNotLinked) {
+		return fmt.Sprintf("%s:%s", provider, login), nil
+	}
+	return actor, err
+}
+
+func openProviderPullRequest(ctx context.Context, event domain.ProviderPullRequestEvent) (domain.PullRequest, error) {
+	var authorID string
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		accountManager := manager.NewAccountManager(storage.AccountStorage, storage.UserStorage)
+		var err error
+		authorID, err = accountManager.Resolve(event.Provider, event.AuthorLogin)
+		return err
+	}, true)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	status := domain.Open
+	if event.Draft {
+		status = domain.Draft
+	}
+	result, err := CreatePullRequest(ctx, domain.PullRequest{
+		PullRequestShort: domain.PullRequestShort{
+			ID:       event.PullRequestID,
+			Name:     event.Title,
+			AuthorID: authorID,
+			Status:   status,
+		},
+	})
+	if !errors.Is(err, domain.ErrPRExists) {
+		return result, err
+	}
+
+	err = backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
+		var err error
+		result, err = pullRequestManager.GetPullRequest(&event.PullRequestID)
+		return err
+	}, true)
+	return result, err
+}
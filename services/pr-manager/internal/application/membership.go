@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+)
+
+func AddTeamMember(ctx context.Context, teamName string, member domain.TeamMember) (domain.Team, error) {
+	var result domain.Team
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		membershipManager := manager.NewMembershipManager(newPullRequestManager(storage))
+		var err error
+		result, err = membershipManager.AddMember(teamName, member)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditTeamMemberAdded, domain.AuditEntityTeam, teamName, map[string]any{
+			"user_id": member.UserID,
+		})
+	}, false)
+	return result, err
+}
+
+func RemoveTeamMember(ctx context.Context, teamName string, userID string) (domain.Team, []domain.Reassignment, error) {
+	var team domain.Team
+	var reassignments []domain.Reassignment
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		membershipManager := manager.NewMembershipManager(newPullRequestManager(storage))
+		var err error
+		team, reassignments, err = membershipManager.RemoveMember(teamName, userID)
+		if err != nil {
+			return err
+		}
+		err = recordAudit(ctx, storage, domain.AuditTeamMemberRemoved, domain.AuditEntityTeam, teamName, map[string]any{
+			"user_id":       userID,
+			"reassignments": reassignments,
+		})
+		if err != nil {
+			return err
+		}
+		return publishReassignments(storage, newPullRequestManager(storage), userID, reassignments, domain.AssignmentMemberLeft)
+	}, false)
+	return team, reassignments, err
+}
+
+// MoveTeamMember moves the user into the team. Moving a user into their own
+// team changes nothing and is not audited.
+func MoveTeamMember(ctx context.Context, userID string, teamName string) (domain.Team, []domain.Reassignment, error) {
+	var team domain.Team
+	var reassignments []domain.Reassignment
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		users, err := storage.UserStorage.Select(&userID)
+		if err != nil {
+			return err
+		}
+		var fromTeam string
+		if len(users) > 0 {
+			fromTeam = users[0].TeamName
+		}
+
+		membershipManager := manager.NewMembershipManager(newPullRequestManager(storage))
+		team, reassignments, err = membershipManager.MoveMember(userID, teamName)
+		if err != nil || fromTeam == teamName {
+			return err
+		}
+		err = recordAudit(ctx, storage, domain.AuditTeamMemberMoved, domain.AuditEntityTeam, teamName, map[string]any{
+			"user_id":       userID,
+			"from_team":     fromTeam,
+			"reassignments": reassignments,
+		})
+		if err != nil {
+			return err
+		}
+		return publishReassignments(storage, newPullRequestManager(storage), userID, reassignments, domain.AssignmentMemberLeft)
+	}, false)
+	return team, reassignments, err
+}
@@ -0,0 +1,39 @@
+package application
+
+import (
+	"context"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db/migrations"
+)
+
+func MigrateUp(ctx context.Context) ([]migrations.Migration, error) {
+	migrator, err := newMigrator()
+	if err != nil {
+		return nil, err
+	}
+	return migrator.Up(ctx)
+}
+
+func MigrateDown(ctx context.Context, steps int) ([]migrations.Migration, error) {
+	migrator, err := newMigrator()
+	if err != nil {
+		return nil, err
+	}
+	return migrator.Down(ctx, steps)
+}
+
+func MigrationStatus(ctx context.Context) ([]migrations.Status, error) {
+	migrator, err := newMigrator()
+	if err != nil {
+		return nil, err
+	}
+	return migrator.Status(ctx)
+}
+
+func newMigrator() (*migrations.Migrator, error) {
+	embedded, err := migrations.Embedded()
+	if err != nil {
+		return nil, err
+	}
+	return migrations.NewMigrator(config, embedded), nil
+}
@@ -0,0 +1,181 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+	"github.com/zemld/pr-manager/pr-manager/internal/outbox"
+)
+
+const (
+	defaultOutboxRelayInterval = time.Second
+	defaultOutboxSinks         = "webhook"
+	defaultOutboxFile          = "outbox.jsonl"
+	defaultOutboxRetention     = 7 * 24 * time.Hour
+)
+
+// publishEvent writes the event to the outbox in the transaction of the
+// change it reports. It is published by the outbox relay after the commit.
+func publishEvent(storage *db.Storage, eventType domain.WebhookEventType, data map[string]any) error {
+	return storage.OutboxStorage.Insert(domain.WebhookEvent{
+		ID:   rand.Text(),
+		Type: eventType,
+		Data: data,
+	})
+}
+
+// publishReassignment publishes pr.reassigned for the reviewer of the pull
+// request replaced with another one. An empty oldReviewerID means the new
+// reviewer replaced nobody, an empty newReviewerID that nobody replaced the
+// old one.
+func publishReassignment(storage *db.Storage, pullRequest domain.PullRequest, oldReviewerID string, newReviewerID string, reason domain.AssignmentReason) error {
+	return publishEvent(storage, domain.EventPullRequestReassigned, map[string]any{
+		"pull_request":    pullRequest,
+		"old_reviewer_id": oldReviewerID,
+		"new_reviewer_id": newReviewerID,
+		"reason":          reason,
+	})
+}
+
+// publishReassignments publishes pr.reassigned for every open review of the
+// reviewer handed over to somebody else.
+func publishReassignments(storage *db.Storage, pullRequestManager *manager.PullRequestManager, reviewerID string, reassignments []domain.Reassignment, reason domain.AssignmentReason) error {
+	for _, reassignment := range reassignments {
+		pullRequest, err := pullRequestManager.GetPullRequest(&reassignment.PullRequestID)
+		if err != nil {
+			return err
+		}
+		err = publishReassignment(storage, pullRequest, reviewerID, reassignment.ReplacedBy, reason)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RelayOutbox publishes the due events of the outbox to the sinks set by
+// OUTBOX_SINKS and returns how many were handled.
+func RelayOutbox(ctx context.Context) (int, error) {
+	return newOutboxRelay().Drain(ctx)
+}
+
+// RunOutboxRelay publishes the outbox every interval set by
+// OUTBOX_RELAY_INTERVAL until ctx is done. Replicas running it at the same
+// time claim different events.
+func RunOutboxRelay(ctx context.Context) {
+	interval := outboxRelayInterval()
+	if interval == 0 {
+		log.Println("Outbox relay is disabled")
+		return
+	}
+	newOutboxRelay().Run(ctx, interval)
+}
+
+func newOutboxRelay() *outbox.Relay {
+	relay := outbox.NewRelay(outboxStore{backend: backend}, outboxSinks()...)
+	relay.Retention = outboxRetention()
+	return relay
+}
+
+// outboxSinks returns the sinks named in the comma-separated OUTBOX_SINKS:
+// webhook, log and file, which appends to OUTBOX_FILE.
+func outboxSinks() []outbox.Sink {
+	names := os.Getenv("OUTBOX_SINKS")
+	if names == "" {
+		names = defaultOutboxSinks
+	}
+
+	var sinks []outbox.Sink
+	for name := range strings.SplitSeq(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "webhook":
+			sinks = append(sinks, webhookSink{backend: backend, dispatcher: webhookDispatcher})
+		case "log":
+			sinks = append(sinks, outbox.LogSink{})
+		case "file":
+			path := os.Getenv("OUTBOX_FILE")
+			if path == "" {
+				path = defaultOutboxFile
+			}
+			sinks = append(sinks, outbox.NewFileSink(path))
+		case "":
+		default:
+			log.Printf("unknown outbox sink %q in OUTBOX_SINKS, skipping it\n", name)
+		}
+	}
+	return sinks
+}
+
+func outboxRelayInterval() time.Duration {
+	value := os.Getenv("OUTBOX_RELAY_INTERVAL")
+	if value == "" {
+		return defaultOutboxRelayInterval
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		log.Printf("invalid OUTBOX_RELAY_INTERVAL %q, using %s\n", value, defaultOutboxRelayInterval)
+		return defaultOutboxRelayInterval
+	}
+	return interval
+}
+
+// outboxRetention is how long published events are kept, set by
+// OUTBOX_RETENTION. Zero keeps them forever.
+func outboxRetention() time.Duration {
+	value := os.Getenv("OUTBOX_RETENTION")
+	if value == "" {
+		return defaultOutboxRetention
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention < 0 {
+		log.Printf("invalid OUTBOX_RETENTION %q, using %s\n", value, defaultOutboxRetention)
+		return defaultOutboxRetention
+	}
+	return retention
+}
+
+// outboxStore runs every outbox operation of the relay as its own unit of
+// work, so a claimed batch is not held in a transaction while it is
+// published.
+type outboxStore struct {
+	backend storageBackend
+}
+
+func (s outboxStore) Claim(ctx context.Context, limit int, maxAttempts int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	var result []domain.OutboxMessage
+	err := s.backend.withStorage(ctx, func(storage *db.Storage) error {
+		var err error
+		result, err = storage.OutboxStorage.Claim(limit, maxAttempts, lease)
+		return err
+	}, false)
+	return result, err
+}
+
+func (s outboxStore) MarkPublished(ctx context.Context, id int64) error {
+	return s.backend.withStorage(ctx, func(storage *db.Storage) error {
+		return storage.OutboxStorage.MarkPublished(id)
+	}, false)
+}
+
+func (s outboxStore) MarkFailed(ctx context.Context, id int64, reason string, retryAfter time.Duration) error {
+	return s.backend.withStorage(ctx, func(storage *db.Storage) error {
+		return storage.OutboxStorage.MarkFailed(id, reason, retryAfter)
+	}, false)
+}
+
+func (s outboxStore) DeletePublished(ctx context.Context, retention time.Duration) (int64, error) {
+	var result int64
+	err := s.backend.withStorage(ctx, func(storage *db.Storage) error {
+		var err error
+		result, err = storage.OutboxStorage.DeletePublished(retention)
+		return err
+	}, false)
+	return result, err
+}
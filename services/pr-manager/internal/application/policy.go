@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"os"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+)
+
+// IsAdmin reports whether token grants admin rights. Admin actions are
+// disabled when ADMIN_TOKEN is not set.
+func IsAdmin(token string) bool {
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	return adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
+
+func SetMergePolicy(ctx context.Context, policy domain.MergePolicy) (domain.MergePolicy, error) {
+	var result domain.MergePolicy
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		policyManager := manager.NewPolicyManager(storage.TeamStorage, storage.PolicyStorage)
+		var err error
+		result, err = policyManager.SetMergePolicy(policy)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditMergePolicyUpdated, domain.AuditEntityTeam, result.TeamName, map[string]any{
+			"policy": result,
+		})
+	}, false)
+	return result, err
+}
+
+func GetMergePolicy(ctx context.Context, teamName string) (domain.MergePolicy, error) {
+	var result domain.MergePolicy
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		policyManager := manager.NewPolicyManager(storage.TeamStorage, storage.PolicyStorage)
+		var err error
+		result, err = policyManager.GetMergePolicy(teamName)
+		return err
+	}, true)
+	return result, err
+}
@@ -10,22 +10,143 @@ import (
 
 func CreatePullRequest(ctx context.Context, pullRequest domain.PullRequest) (domain.PullRequest, error) {
 	var result domain.PullRequest
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		pullRequestManager := manager.NewPullRequestManager(configureStorage(tx))
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
 		var err error
 		result, err = pullRequestManager.CreatePullRequest(pullRequest)
-		return err
+		if err != nil {
+			return err
+		}
+		err = recordAudit(ctx, storage, domain.AuditPullRequestCreated, domain.AuditEntityPullRequest, result.ID, map[string]any{
+			"author_id":          result.AuthorID,
+			"assigned_reviewers": result.AssignedReviewers,
+		})
+		if err != nil {
+			return err
+		}
+		return publishEvent(storage, domain.EventPullRequestCreated, map[string]any{
+			"pull_request": result,
+		})
 	}, false)
 	return result, err
 }
 
 func MergePullRequest(ctx context.Context, pullRequest domain.PullRequest) (domain.PullRequest, error) {
 	var result domain.PullRequest
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		pullRequestManager := manager.NewPullRequestManager(configureStorage(tx))
-		var err error
-		result, err = pullRequestManager.MergePullRequest(pullRequest)
-		return err
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
+		wasMerged, err := isMerged(pullRequestManager, pullRequest.ID)
+		if err != nil {
+			return err
+		}
+		result, err = pullRequestManager.MergePullRequest(pullRequest, actorFrom(ctx))
+		if err != nil || wasMerged {
+			return err
+		}
+		err = recordAudit(ctx, storage, domain.AuditPullRequestMerged, domain.AuditEntityPullRequest, result.ID, nil)
+		if err != nil {
+			return err
+		}
+		return publishEvent(storage, domain.EventPullRequestMerged, map[string]any{
+			"pull_request": result,
+		})
+	}, false)
+	return result, err
+}
+
+func ForceMergePullRequest(ctx context.Context, pullRequest domain.PullRequest, reason string) (domain.PullRequest, error) {
+	var result domain.PullRequest
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
+		wasMerged, err := isMerged(pullRequestManager, pullRequest.ID)
+		if err != nil {
+			return err
+		}
+		result, err = pullRequestManager.ForceMergePullRequest(pullRequest, actorFrom(ctx), reason)
+		if err != nil || wasMerged {
+			return err
+		}
+		err = recordAudit(ctx, storage, domain.AuditPullRequestMerged, domain.AuditEntityPullRequest, result.ID, map[string]any{
+			"override_reason": reason,
+		})
+		if err != nil {
+			return err
+		}
+		return publishEvent(storage, domain.EventPullRequestMerged, map[string]any{
+			"pull_request":    result,
+			"override_reason": reason,
+		})
+	}, false)
+	return result, err
+}
+
+// isMerged reports whether the pull request is merged already. Merging it
+// again changes nothing and is not audited.
+func isMerged(pullRequestManager *manager.PullRequestManager, pullRequestID string) (bool, error) {
+	return hasStatus(pullRequestManager, pullRequestID, domain.Merged)
+}
+
+// hasStatus reports whether the pull request is in the status already. Moving
+// it there again changes nothing and is not audited.
+func hasStatus(pullRequestManager *manager.PullRequestManager, pullRequestID string, status domain.PullRequestStatus) (bool, error) {
+	current, err := pullRequestManager.GetPullRequest(&pullRequestID)
+	if err != nil {
+		return false, err
+	}
+	return current.Status == status, nil
+}
+
+func MarkPullRequestReady(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	var result domain.PullRequest
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
+		wasInStatus, err := hasStatus(pullRequestManager, pullRequestID, domain.Open)
+		if err != nil {
+			return err
+		}
+		result, err = pullRequestManager.MarkReady(pullRequestID)
+		if err != nil || wasInStatus {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditPullRequestReady, domain.AuditEntityPullRequest, result.ID, map[string]any{
+			"assigned_reviewers": result.AssignedReviewers,
+		})
+	}, false)
+	return result, err
+}
+
+func ClosePullRequest(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	var result domain.PullRequest
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
+		wasInStatus, err := hasStatus(pullRequestManager, pullRequestID, domain.Closed)
+		if err != nil {
+			return err
+		}
+		result, err = pullRequestManager.ClosePullRequest(pullRequestID)
+		if err != nil || wasInStatus {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditPullRequestClosed, domain.AuditEntityPullRequest, result.ID, nil)
+	}, false)
+	return result, err
+}
+
+func ReopenPullRequest(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	var result domain.PullRequest
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
+		wasInStatus, err := hasStatus(pullRequestManager, pullRequestID, domain.Open)
+		if err != nil {
+			return err
+		}
+		result, err = pullRequestManager.ReopenPullRequest(pullRequestID)
+		if err != nil || wasInStatus {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditPullRequestReopened, domain.AuditEntityPullRequest, result.ID, map[string]any{
+			"assigned_reviewers": result.AssignedReviewers,
+		})
 	}, false)
 	return result, err
 }
@@ -33,21 +154,63 @@ func MergePullRequest(ctx context.Context, pullRequest domain.PullRequest) (doma
 func ReassignPullRequest(ctx context.Context, pullRequestID string, oldReviewerID string) (domain.PullRequest, string, error) {
 	var result domain.PullRequest
 	var newReviewer string
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		pullRequestManager := manager.NewPullRequestManager(configureStorage(tx))
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
 		var err error
 		result, newReviewer, err = pullRequestManager.ReassignPullRequest(pullRequestID, oldReviewerID)
-		return err
+		if err != nil {
+			return err
+		}
+		err = recordAudit(ctx, storage, domain.AuditPullRequestReassigned, domain.AuditEntityPullRequest, result.ID, map[string]any{
+			"old_reviewer_id": oldReviewerID,
+			"new_reviewer_id": newReviewer,
+		})
+		if err != nil {
+			return err
+		}
+		return publishReassignment(storage, result, oldReviewerID, newReviewer, domain.AssignmentReassign)
 	}, false)
 	return result, newReviewer, err
 }
 
-func GetUserPullRequestsReviews(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+func SubmitReview(ctx context.Context, pullRequestID string, review domain.Review) (domain.PullRequest, error) {
+	var result domain.PullRequest
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
+		var err error
+		result, err = pullRequestManager.SubmitReview(pullRequestID, review)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditReviewSubmitted, domain.AuditEntityPullRequest, result.ID, map[string]any{
+			"reviewer_id": review.ReviewerID,
+			"state":       review.State,
+		})
+	}, false)
+	return result, err
+}
+
+func GetUserPullRequestsReviews(ctx context.Context, userID string, pendingOnly bool) ([]domain.PullRequest, error) {
 	var result []domain.PullRequest
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		pullRequestManager := manager.NewPullRequestManager(configureStorage(tx))
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
 		var err error
-		result, err = pullRequestManager.UserPullRequestsReviews(userID)
+		if pendingOnly {
+			result, err = pullRequestManager.UserPendingReviews(userID)
+		} else {
+			result, err = pullRequestManager.UserPullRequestsReviews(userID)
+		}
+		return err
+	}, true)
+	return result, err
+}
+
+func GetPullRequestHistory(ctx context.Context, pullRequestID string) ([]domain.AssignmentEvent, error) {
+	var result []domain.AssignmentEvent
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
+		var err error
+		result, err = pullRequestManager.GetHistory(pullRequestID)
 		return err
 	}, true)
 	return result, err
@@ -55,34 +218,11 @@ func GetUserPullRequestsReviews(ctx context.Context, userID string) ([]domain.Pu
 
 func GetPullRequests(ctx context.Context) ([]domain.PullRequest, error) {
 	var result []domain.PullRequest
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		pullRequestManager := manager.NewPullRequestManager(configureStorage(tx))
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		pullRequestManager := newPullRequestManager(storage)
 		var err error
 		result, err = pullRequestManager.GetPullRequests(nil)
 		return err
 	}, true)
 	return result, err
 }
-
-func configureStorage(tx *db.Transactor) *db.Storage {
-	userStorage := db.NewUserStorage(config, *tx)
-	userStorage.SetSelectQuery(db.SelectUser)
-
-	teamStorage := db.NewTeamStorage(config, *tx)
-	teamStorage.SetSelectQuery(db.SelectTeam)
-
-	pullRequestStorage := db.NewPullRequestStorage(config, *tx)
-	pullRequestStorage.SetSelectQuery(db.SelectPullRequest)
-	pullRequestStorage.SetCreateQuery(db.CreatePullRequest)
-	pullRequestStorage.SetMergeQuery(db.MergePullRequest)
-	pullRequestStorage.SetReassignQuery(db.ReassignPullRequest)
-	pullRequestStorage.SetUserPullRequestsReviewsQuery(db.UserPullRequestsReviews)
-
-	return &db.Storage{
-		Config:             config,
-		Transactor:         *tx,
-		UserStorage:        userStorage,
-		TeamStorage:        teamStorage,
-		PullRequestStorage: pullRequestStorage,
-	}
-}
@@ -70,7 +70,7 @@ func TestGetUserPullRequestsReviews_Integration(t *testing.T) {
 	ctx := context.Background()
 	userID := "test-user-1"
 
-	_, err := GetUserPullRequestsReviews(ctx, userID)
+	_, err := GetUserPullRequestsReviews(ctx, userID, false)
 	if err != nil {
 		t.Logf("GetUserPullRequestsReviews error (may be expected if test data not set up): %v", err)
 	}
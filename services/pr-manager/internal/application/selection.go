@@ -0,0 +1,107 @@
+package application
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+)
+
+type selectionConfig struct {
+	defaultStrategy manager.SelectionStrategy
+	teamStrategies  map[string]manager.SelectionStrategy
+	weights         map[string]float64
+}
+
+var reviewerSelectionConfig = loadSelectionConfig(
+	os.Getenv("REVIEWER_SELECTION_STRATEGY"),
+	os.Getenv("REVIEWER_SELECTION_TEAM_STRATEGIES"),
+	os.Getenv("REVIEWER_SELECTION_WEIGHTS"),
+)
+
+// Round-robin has to remember its position between requests, so it is shared.
+var roundRobinSelector = manager.NewRoundRobinSelector()
+
+func loadSelectionConfig(defaultStrategy string, teamStrategies string, weights string) selectionConfig {
+	cfg := selectionConfig{
+		defaultStrategy: manager.LeastLoadedSelection,
+		teamStrategies:  make(map[string]manager.SelectionStrategy),
+		weights:         make(map[string]float64),
+	}
+
+	if defaultStrategy != "" {
+		strategy, err := manager.ParseSelectionStrategy(defaultStrategy)
+		if err != nil {
+			log.Printf("invalid REVIEWER_SELECTION_STRATEGY %q, using %s: %v\n", defaultStrategy, cfg.defaultStrategy, err)
+		} else {
+			cfg.defaultStrategy = strategy
+		}
+	}
+
+	for teamName, value := range parseKeyValueList(teamStrategies) {
+		strategy, err := manager.ParseSelectionStrategy(value)
+		if err != nil {
+			log.Printf("invalid reviewer selection strategy %q for team %s: %v\n", value, teamName, err)
+			continue
+		}
+		cfg.teamStrategies[teamName] = strategy
+	}
+
+	for userID, value := range parseKeyValueList(weights) {
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			log.Printf("invalid reviewer weight %q for user %s: %v\n", value, userID, err)
+			continue
+		}
+		cfg.weights[userID] = weight
+	}
+
+	return cfg
+}
+
+// parseKeyValueList parses "key1:value1,key2:value2".
+func parseKeyValueList(list string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(list, ",") {
+		key, value, found := strings.Cut(pair, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			continue
+		}
+		result[key] = strings.TrimSpace(value)
+	}
+	return result
+}
+
+func newSelector(strategy manager.SelectionStrategy, storage *db.Storage) manager.ReviewerSelector {
+	switch strategy {
+	case manager.RoundRobinSelection:
+		return roundRobinSelector
+	case manager.LeastLoadedSelection:
+		return manager.NewLeastLoadedSelector(storage.PullRequestStorage)
+	case manager.WeightedSelection:
+		return manager.NewWeightedSelector(reviewerSelectionConfig.weights)
+	default:
+		return manager.NewRandomSelector()
+	}
+}
+
+func reviewerSelection(storage *db.Storage) manager.ReviewerSelection {
+	teams := make(map[string]manager.ReviewerSelector, len(reviewerSelectionConfig.teamStrategies))
+	for teamName, strategy := range reviewerSelectionConfig.teamStrategies {
+		teams[teamName] = newSelector(strategy, storage)
+	}
+	return manager.ReviewerSelection{
+		Default: newSelector(reviewerSelectionConfig.defaultStrategy, storage),
+		Teams:   teams,
+	}
+}
+
+func newPullRequestManager(storage *db.Storage) *manager.PullRequestManager {
+	pullRequestManager := manager.NewPullRequestManager(storage)
+	pullRequestManager.Selection = reviewerSelection(storage)
+	return pullRequestManager
+}
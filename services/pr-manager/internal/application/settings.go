@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+)
+
+func SetTeamSettings(ctx context.Context, settings domain.TeamSettings) (domain.TeamSettings, error) {
+	var result domain.TeamSettings
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		settingsManager := manager.NewSettingsManager(storage.TeamStorage, storage.SettingsStorage, storage.PoolStorage, storage.UserStorage)
+		var err error
+		result, err = settingsManager.SetTeamSettings(settings)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditTeamSettingsUpdated, domain.AuditEntityTeam, result.TeamName, map[string]any{
+			"settings": result,
+		})
+	}, false)
+	return result, err
+}
+
+func GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	var result domain.TeamSettings
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		settingsManager := manager.NewSettingsManager(storage.TeamStorage, storage.SettingsStorage, storage.PoolStorage, storage.UserStorage)
+		var err error
+		result, err = settingsManager.GetTeamSettings(teamName)
+		return err
+	}, true)
+	return result, err
+}
+
+func SetReviewerPool(ctx context.Context, pool domain.ReviewerPool) (domain.ReviewerPool, error) {
+	var result domain.ReviewerPool
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		poolManager := manager.NewPoolManager(storage.UserStorage, storage.PoolStorage)
+		var err error
+		result, err = poolManager.SetPool(pool)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, storage, domain.AuditReviewerPoolUpdated, domain.AuditEntityReviewerPool, result.Name, map[string]any{
+			"members": result.Members,
+		})
+	}, false)
+	return result, err
+}
+
+func GetReviewerPool(ctx context.Context, name string) (domain.ReviewerPool, error) {
+	var result domain.ReviewerPool
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		poolManager := manager.NewPoolManager(storage.UserStorage, storage.PoolStorage)
+		var err error
+		result, err = poolManager.GetPool(name)
+		return err
+	}, true)
+	return result, err
+}
@@ -0,0 +1,123 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+func TestCalculateIndividualTeamStats_SumsMemberActivity(t *testing.T) {
+	d := data{
+		users: []domain.UserActivity{
+			{UserID: "u1", TeamName: "backend", IsActive: true, PRsCreated: 2, PRsReviewed: 1, PRsMerged: 1, PRsOpen: 1},
+			{UserID: "u2", TeamName: "backend", IsActive: false, PRsCreated: 1, PRsReviewed: 3, PRsWaitingForReview: 2},
+			{UserID: "u3", TeamName: "", IsActive: true, PRsCreated: 5},
+		},
+		teams: []domain.TeamActivity{
+			{TeamName: "backend", TotalMembers: 2, ActiveMembers: 1, AverageMergeTimeHours: 2},
+		},
+	}
+
+	stats := domain.Stats{}
+	calculateIndividualTeamStats(&d, &stats)
+
+	got := stats.IndividualTeamStats["backend"]
+	want := domain.IndividualTeamStats{
+		TotalMembers:          2,
+		ActiveMembers:         1,
+		InactiveMembers:       1,
+		PRsCreated:            3,
+		PRsReviewed:           4,
+		PRsMerged:             1,
+		PRsOpen:               1,
+		PRsWaitingForReview:   2,
+		AverageMergeTimeHours: 2,
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if len(stats.IndividualTeamStats) != 1 {
+		t.Errorf("users without a team must not form a team, got %+v", stats.IndividualTeamStats)
+	}
+}
+
+func TestCalculateTeamStats_MinMax(t *testing.T) {
+	d := data{
+		teams: []domain.TeamActivity{
+			{TeamName: "backend", TotalMembers: 4, ActiveMembers: 4},
+			{TeamName: "frontend", TotalMembers: 2, ActiveMembers: 1},
+		},
+	}
+
+	stats := domain.Stats{}
+	calculateTeamStats(&d, &stats)
+
+	want := domain.TeamStats{
+		Total:                         2,
+		AverageMembersPerTeam:         3,
+		MostMembersInTeam:             4,
+		LeastMembersInTeam:            2,
+		AverageActiveMembersPerTeam:   2.5,
+		MostActiveMembersInTeam:       4,
+		LeastActiveMembersInTeam:      1,
+		AverageInactiveMembersPerTeam: 0.5,
+		MostInactiveMembersInTeam:     1,
+		LeastInactiveMembersInTeam:    0,
+	}
+	if stats.TeamStats != want {
+		t.Errorf("expected %+v, got %+v", want, stats.TeamStats)
+	}
+}
+
+func TestCalculatePullRequestStats(t *testing.T) {
+	d := data{
+		users: []domain.UserActivity{
+			{UserID: "u1", PRsCreated: 3, PRsReviewed: 0},
+			{UserID: "u2", PRsCreated: 1, PRsReviewed: 2},
+			{UserID: "u3", PRsCreated: 0, PRsReviewed: 4},
+		},
+		pullRequests: domain.PullRequestTotals{Total: 4, AverageMergeTimeHours: 1.5},
+	}
+
+	stats := domain.Stats{}
+	calculatePullRequestStats(&d, &stats)
+
+	want := domain.PullRequestStats{
+		Total:                 4,
+		AveragePRsPerUser:     4.0 / 3,
+		MostPRsPerUser:        3,
+		LeastPRsPerUser:       0,
+		AverageMergeTimeHours: 1.5,
+		AveragePRsPerReviewer: 3,
+		MostPRsPerReviewer:    4,
+		LeastPRsPerReviewer:   0,
+	}
+	if stats.PullRequestStats != want {
+		t.Errorf("expected %+v, got %+v", want, stats.PullRequestStats)
+	}
+}
+
+func TestCalculateUserStats_SplitsAbsentUsers(t *testing.T) {
+	d := data{
+		users: []domain.UserActivity{
+			{UserID: "u1", IsActive: true},
+			{UserID: "u2", IsActive: true, IsAbsent: true},
+			{UserID: "u3", IsActive: false, IsAbsent: true},
+		},
+		teams: []domain.TeamActivity{
+			{TeamName: "backend", TotalMembers: 3, ActiveMembers: 2, AbsentMembers: 1},
+		},
+	}
+
+	stats := domain.Stats{}
+	calculateUserStats(&d, &stats)
+	calculateIndividualTeamStats(&d, &stats)
+
+	if want := (domain.UserStats{Total: 3, Active: 1, Absent: 1, Inactive: 1}); stats.UserStats != want {
+		t.Errorf("expected %+v, got %+v", want, stats.UserStats)
+	}
+	team := stats.IndividualTeamStats["backend"]
+	if team.ActiveMembers != 1 || team.AbsentMembers != 1 || team.InactiveMembers != 1 {
+		t.Errorf("expected 1 active, 1 absent and 1 inactive member, got %+v", team)
+	}
+}
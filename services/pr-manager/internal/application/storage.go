@@ -0,0 +1,170 @@
+package application
+
+import (
+	"context"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/memory"
+)
+
+// storageBackend runs fn against one of the storage implementations as a single unit of work.
+type storageBackend interface {
+	withStorage(ctx context.Context, fn func(*db.Storage) error, isReadOnly bool) error
+	// withSnapshot runs read-only fn with all reads seeing the same data.
+	withSnapshot(ctx context.Context, fn func(*db.Storage) error) error
+}
+
+type postgresBackend struct{}
+
+func (postgresBackend) withStorage(ctx context.Context, fn func(*db.Storage) error, isReadOnly bool) error {
+	return executor.withTransaction(ctx, func(tx *db.Transactor) error {
+		return fn(configureStorage(tx))
+	}, isReadOnly)
+}
+
+func (postgresBackend) withSnapshot(ctx context.Context, fn func(*db.Storage) error) error {
+	return executor.withSnapshot(ctx, func(tx *db.Transactor) error {
+		return fn(configureStorage(tx))
+	})
+}
+
+type memoryBackend struct {
+	store *memory.Store
+}
+
+func (b memoryBackend) withStorage(ctx context.Context, fn func(*db.Storage) error, isReadOnly bool) error {
+	return b.store.WithTransaction(fn, isReadOnly)
+}
+
+func (b memoryBackend) withSnapshot(ctx context.Context, fn func(*db.Storage) error) error {
+	return b.store.WithTransaction(fn, true)
+}
+
+var backend storageBackend = postgresBackend{}
+
+// UseMemoryStorage switches the application to a new, empty in-memory store.
+func UseMemoryStorage() {
+	backend = memoryBackend{store: memory.NewStore()}
+}
+
+// UsePostgresStorage switches the application back to the Postgres storage, which is the default.
+func UsePostgresStorage() {
+	backend = postgresBackend{}
+}
+
+func configureStorage(tx *db.Transactor) *db.Storage {
+	userStorage := db.NewUserStorage(config, *tx)
+	userStorage.SetSelectQuery(db.SelectUser)
+	userStorage.SetUpdateQuery(db.UpdateUserStatus)
+	userStorage.SetInsertQuery(db.InsertUser)
+
+	teamStorage := db.NewTeamStorage(config, *tx)
+	teamStorage.SetSelectQuery(db.SelectTeam)
+	teamStorage.SetInsertQuery(db.UpsertTeamMember)
+	teamStorage.SetSelectUserQuery(db.SelectUser)
+	teamStorage.SetDeleteQuery(db.DeleteTeam)
+	teamStorage.SetSetTeamQuery(db.SetUserTeam)
+	teamStorage.SetSelectDeletedQuery(db.SelectDeletedTeams)
+	teamStorage.SetRestoreQuery(db.RestoreTeam)
+	teamStorage.SetPurgeReviewersQuery(db.PurgeDeletedTeamReviewers)
+	teamStorage.SetPurgePullRequestsQuery(db.PurgeDeletedTeamPullRequests)
+	teamStorage.SetPurgeTeamDataQuery(db.PurgeDeletedTeamData)
+	teamStorage.SetPurgeQuery(db.PurgeDeletedTeams)
+
+	pullRequestStorage := db.NewPullRequestStorage(config, *tx)
+	pullRequestStorage.SetSelectQuery(db.SelectPullRequest)
+	pullRequestStorage.SetCreateQuery(db.CreatePullRequest)
+	pullRequestStorage.SetMergeQuery(db.MergePullRequest)
+	pullRequestStorage.SetReassignQuery(db.ReassignPullRequest)
+	pullRequestStorage.SetAssignReviewersQuery(db.AssignPullRequestReviewers)
+	pullRequestStorage.SetUserPullRequestsReviewsQuery(db.UserPullRequestsReviews)
+	pullRequestStorage.SetCountUserOpenReviewsQuery(db.CountUserOpenReviews)
+	pullRequestStorage.SetSubmitReviewQuery(db.SubmitPullRequestReview)
+	pullRequestStorage.SetHistoryQuery(db.SelectPullRequestAssignments)
+	pullRequestStorage.SetUpdateStatusQuery(db.UpdatePullRequestStatus)
+
+	policyStorage := db.NewPolicyStorage(config, *tx)
+	policyStorage.SetSelectQuery(db.SelectMergePolicy)
+	policyStorage.SetUpsertQuery(db.UpsertMergePolicy)
+	policyStorage.SetInsertOverrideQuery(db.InsertMergePolicyOverride)
+
+	statsStorage := db.NewStatsStorage(config, *tx)
+	statsStorage.SetUserActivityQuery(db.SelectUserActivity)
+	statsStorage.SetTeamActivityQuery(db.SelectTeamActivity)
+	statsStorage.SetPullRequestTotalsQuery(db.SelectPullRequestTotals)
+
+	auditStorage := db.NewAuditStorage(config, *tx)
+	auditStorage.SetInsertQuery(db.InsertAuditEvent)
+	auditStorage.SetSelectQuery(db.SelectAuditEvents)
+
+	absenceStorage := db.NewAbsenceStorage(config, *tx)
+	absenceStorage.SetInsertQuery(db.InsertUserAbsence)
+	absenceStorage.SetSelectQuery(db.SelectUserAbsences)
+	absenceStorage.SetAbsentUserQuery(db.SelectAbsentUserIDs)
+
+	settingsStorage := db.NewSettingsStorage(config, *tx)
+	settingsStorage.SetSelectQuery(db.SelectTeamSettings)
+	settingsStorage.SetUpsertQuery(db.UpsertTeamSettings)
+
+	poolStorage := db.NewPoolStorage(config, *tx)
+	poolStorage.SetSelectQuery(db.SelectReviewerPool)
+	poolStorage.SetUpsertQuery(db.UpsertReviewerPool)
+	poolStorage.SetReplaceMembersQuery(db.ReplaceReviewerPoolMembers)
+
+	codeownersStorage := db.NewCodeownersStorage(config, *tx)
+	codeownersStorage.SetSelectQuery(db.SelectCodeowners)
+	codeownersStorage.SetUpsertQuery(db.UpsertCodeowners)
+
+	escalationStorage := db.NewEscalationStorage(config, *tx)
+	escalationStorage.SetSelectStaleQuery(db.SelectStaleReviews)
+	escalationStorage.SetInsertQuery(db.InsertReviewEscalation)
+
+	lockStorage := db.NewLockStorage(config, *tx)
+	lockStorage.SetTryLockQuery(db.TryAdvisoryLock)
+
+	savepointStorage := db.NewSavepointStorage(config, *tx)
+	savepointStorage.SetCreateQuery(db.CreateSavepoint)
+	savepointStorage.SetRollbackQuery(db.RollbackToSavepoint)
+	savepointStorage.SetReleaseQuery(db.ReleaseSavepoint)
+
+	webhookStorage := db.NewWebhookStorage(config, *tx)
+	webhookStorage.SetInsertQuery(db.InsertWebhookSubscription)
+	webhookStorage.SetSelectQuery(db.SelectWebhookSubscription)
+	webhookStorage.SetSelectSubscribedQuery(db.SelectSubscribedWebhooks)
+	webhookStorage.SetDeleteQuery(db.DeleteWebhookSubscription)
+	webhookStorage.SetInsertDeliveryQuery(db.InsertWebhookDelivery)
+	webhookStorage.SetSelectDeliveriesQuery(db.SelectWebhookDeliveries)
+	webhookStorage.SetSelectEventDeliveriesQuery(db.SelectWebhookEventDeliveries)
+
+	outboxStorage := db.NewOutboxStorage(config, *tx)
+	outboxStorage.SetInsertQuery(db.InsertOutboxMessage)
+	outboxStorage.SetClaimQuery(db.ClaimOutboxMessages)
+	outboxStorage.SetMarkPublishedQuery(db.MarkOutboxMessagePublished)
+	outboxStorage.SetMarkFailedQuery(db.MarkOutboxMessageFailed)
+	outboxStorage.SetDeletePublishedQuery(db.DeletePublishedOutboxMessages)
+
+	accountStorage := db.NewAccountStorage(config, *tx)
+	accountStorage.SetUpsertQuery(db.UpsertProviderAccount)
+	accountStorage.SetSelectQuery(db.SelectProviderAccounts)
+
+	return &db.Storage{
+		Config:             config,
+		Transactor:         *tx,
+		UserStorage:        userStorage,
+		TeamStorage:        teamStorage,
+		PullRequestStorage: pullRequestStorage,
+		PolicyStorage:      policyStorage,
+		StatsStorage:       statsStorage,
+		AuditStorage:       auditStorage,
+		AbsenceStorage:     absenceStorage,
+		SettingsStorage:    settingsStorage,
+		PoolStorage:        poolStorage,
+		CodeownersStorage:  codeownersStorage,
+		EscalationStorage:  escalationStorage,
+		LockStorage:        lockStorage,
+		SavepointStorage:   savepointStorage,
+		WebhookStorage:     webhookStorage,
+		OutboxStorage:      outboxStorage,
+		AccountStorage:     accountStorage,
+	}
+}
@@ -2,6 +2,11 @@ package application
 
 import (
 	"context"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/zemld/pr-manager/pr-manager/internal/domain"
 	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
@@ -10,24 +15,28 @@ import (
 
 func AddTeam(ctx context.Context, team domain.Team) (domain.Team, error) {
 	var result domain.Team
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		teamStorage := db.NewTeamStorage(config, *tx)
-		teamStorage.SetInsertQuery(db.InsertUser)
-		teamStorage.SetSelectUserQuery(db.SelectUser)
-		teamManager := manager.NewTeamManager(teamStorage, nil)
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		teamManager := manager.NewTeamManager(storage.TeamStorage, nil)
 		var err error
 		result, err = teamManager.AddTeam(team)
-		return err
+		if err != nil {
+			return err
+		}
+		members := make([]string, 0, len(result.Members))
+		for _, member := range result.Members {
+			members = append(members, member.UserID)
+		}
+		return recordAudit(ctx, storage, domain.AuditTeamCreated, domain.AuditEntityTeam, result.TeamName, map[string]any{
+			"members": members,
+		})
 	}, false)
 	return result, err
 }
 
 func GetTeam(ctx context.Context, teamName *string) (domain.Team, error) {
 	var result domain.Team
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		teamStorage := db.NewTeamStorage(config, *tx)
-		teamStorage.SetSelectQuery(db.SelectTeam)
-		teamManager := manager.NewTeamManager(teamStorage, nil)
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		teamManager := manager.NewTeamManager(storage.TeamStorage, nil)
 		var err error
 		result, err = teamManager.GetTeam(teamName)
 		return err
@@ -37,10 +46,8 @@ func GetTeam(ctx context.Context, teamName *string) (domain.Team, error) {
 
 func GetTeams(ctx context.Context) ([]domain.Team, error) {
 	var result []domain.Team
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		teamStorage := db.NewTeamStorage(config, *tx)
-		teamStorage.SetSelectQuery(db.SelectTeam)
-		teamManager := manager.NewTeamManager(teamStorage, nil)
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		teamManager := manager.NewTeamManager(storage.TeamStorage, nil)
 		var err error
 		result, err = teamManager.GetTeams(nil)
 		return err
@@ -49,17 +56,131 @@ func GetTeams(ctx context.Context) ([]domain.Team, error) {
 }
 
 func DeleteTeam(ctx context.Context, teamName string) error {
-	return executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		teamStorage := db.NewTeamStorage(config, *tx)
-		teamStorage.SetSelectQuery(db.SelectTeam)
-		teamStorage.SetDeleteQuery(db.DeleteTeam)
+	return backend.withStorage(ctx, func(storage *db.Storage) error {
+		teamManager := manager.NewTeamManager(storage.TeamStorage, storage.PullRequestStorage)
+		removed, err := teamManager.DeleteTeam(teamName)
+		if err != nil {
+			return err
+		}
+		err = recordAudit(ctx, storage, domain.AuditTeamDeleted, domain.AuditEntityTeam, teamName, map[string]any{
+			"removed_reviewers": removed,
+		})
+		if err != nil {
+			return err
+		}
+		return publishEvent(storage, domain.EventTeamDeleted, map[string]any{
+			"team_name":         teamName,
+			"removed_reviewers": removed,
+		})
+	}, false)
+}
+
+// defaultTeamRetention is how long deleted teams are kept when
+// TEAM_RETENTION_DAYS is not set.
+const defaultTeamRetention = 30 * 24 * time.Hour
+
+func GetDeletedTeams(ctx context.Context) ([]domain.DeletedTeam, error) {
+	var result []domain.DeletedTeam
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		teamManager := manager.NewTeamManager(storage.TeamStorage, nil)
+		var err error
+		result, err = teamManager.GetDeletedTeams()
+		return err
+	}, true)
+	return result, err
+}
+
+// RestoreTeam restores a deleted team. With fillReviewers set, open pull
+// requests of its members that lost reviewers on deletion get new ones.
+func RestoreTeam(ctx context.Context, teamName string, fillReviewers bool) (domain.Team, []domain.PullRequest, error) {
+	var team domain.Team
+	var updated []domain.PullRequest
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		teamManager := manager.NewTeamManager(storage.TeamStorage, storage.PullRequestStorage)
+		var err error
+		team, err = teamManager.RestoreTeam(teamName)
+		if err != nil {
+			return err
+		}
+		pullRequestManager := newPullRequestManager(storage)
+		before := make(map[string][]string)
+		if fillReviewers {
+			prs, err := pullRequestManager.GetPullRequests(nil)
+			if err != nil {
+				return err
+			}
+			for _, pr := range prs {
+				before[pr.ID] = pr.AssignedReviewers
+			}
+			updated, err = pullRequestManager.FillReviewers(team)
+			if err != nil {
+				return err
+			}
+		}
+		filled := make(map[string][]string, len(updated))
+		for _, pr := range updated {
+			filled[pr.ID] = pr.AssignedReviewers
+		}
+		err = recordAudit(ctx, storage, domain.AuditTeamRestored, domain.AuditEntityTeam, teamName, map[string]any{
+			"reassign_reviewers": fillReviewers,
+			"assigned_reviewers": filled,
+		})
+		if err != nil {
+			return err
+		}
+		for _, pr := range updated {
+			for _, reviewerID := range pr.AssignedReviewers {
+				if slices.Contains(before[pr.ID], reviewerID) {
+					continue
+				}
+				if err := publishReassignment(storage, pr, "", reviewerID, domain.AssignmentTeamRestored); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}, false)
+	return team, updated, err
+}
 
-		pullRequestStorage := db.NewPullRequestStorage(config, *tx)
-		pullRequestStorage.SetSelectQuery(db.SelectPullRequest)
-		pullRequestStorage.SetReassignQuery(db.ReassignPullRequest)
-		pullRequestStorage.SetUserPullRequestsReviewsQuery(db.UserPullRequestsReviews)
+// PurgeDeletedTeams hard-deletes teams deleted at least retention ago. If
+// retention is nil, TEAM_RETENTION_DAYS is used.
+func PurgeDeletedTeams(ctx context.Context, retention *time.Duration) ([]string, error) {
+	if retention == nil {
+		configured := teamRetention()
+		retention = &configured
+	}
 
-		teamManager := manager.NewTeamManager(teamStorage, pullRequestStorage)
-		return teamManager.DeleteTeam(teamName)
+	var result []string
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		teamManager := manager.NewTeamManager(storage.TeamStorage, storage.PullRequestStorage)
+		var err error
+		result, err = teamManager.PurgeDeletedTeams(*retention)
+		if err != nil {
+			return err
+		}
+		for _, teamName := range result {
+			err = recordAudit(ctx, storage, domain.AuditTeamPurged, domain.AuditEntityTeam, teamName, map[string]any{
+				"retention_days": retention.Hours() / 24,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
 	}, false)
+	return result, err
+}
+
+func teamRetention() time.Duration {
+	days := os.Getenv("TEAM_RETENTION_DAYS")
+	if days == "" {
+		return defaultTeamRetention
+	}
+	value, err := strconv.Atoi(days)
+	if err != nil || value < 0 {
+		log.Printf("invalid TEAM_RETENTION_DAYS %q, using %s\n", days, defaultTeamRetention)
+		return defaultTeamRetention
+	}
+	return time.Duration(value) * 24 * time.Hour
 }
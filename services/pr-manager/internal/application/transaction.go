@@ -43,17 +43,9 @@ func NewTransactionExecutor(pool *pgxpool.Pool) *TransactionExecutor {
 var executor *TransactionExecutor
 
 func (e *TransactionExecutor) withTransaction(ctx context.Context, fn func(*db.Transactor) error, isReadOnly bool) error {
-	if e == nil || e.pool == nil {
-		p, err := getPool()
-		if err != nil {
-			return fmt.Errorf("failed to get database pool: %w", err)
-		}
-		if e == nil {
-			executor = NewTransactionExecutor(p)
-			e = executor
-		} else {
-			e.pool = p
-		}
+	e, err := e.ready()
+	if err != nil {
+		return err
 	}
 
 	transactor := db.NewTransactor(e.pool, ctx, isReadOnly)
@@ -74,3 +66,37 @@ func (e *TransactionExecutor) withTransaction(ctx context.Context, fn func(*db.T
 	}
 	return nil
 }
+
+// withSnapshot runs fn in a read-only transaction in which all queries see the
+// same snapshot of the database.
+func (e *TransactionExecutor) withSnapshot(ctx context.Context, fn func(*db.Transactor) error) error {
+	e, err := e.ready()
+	if err != nil {
+		return err
+	}
+
+	transactor := db.NewSnapshotTransactor(e.pool, ctx)
+	if err := transactor.Begin(ctx); err != nil {
+		return err
+	}
+	defer transactor.Rollback()
+
+	return fn(transactor)
+}
+
+// ready connects the executor to the database pool on first use.
+func (e *TransactionExecutor) ready() (*TransactionExecutor, error) {
+	if e != nil && e.pool != nil {
+		return e, nil
+	}
+	p, err := getPool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database pool: %w", err)
+	}
+	if e == nil {
+		executor = NewTransactionExecutor(p)
+		return executor, nil
+	}
+	e.pool = p
+	return e, nil
+}
@@ -2,35 +2,102 @@ package application
 
 import (
 	"context"
+	"errors"
 
 	"github.com/zemld/pr-manager/pr-manager/internal/domain"
 	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
 	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
 )
 
-func UpdateUserStatus(ctx context.Context, user domain.User) (domain.User, error) {
-	var updatedUser domain.User
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		userStorage := db.NewUserStorage(config, *tx)
-		userStorage.SetUpdateQuery(db.UpdateUserStatus)
-		userStorage.SetSelectQuery(db.SelectUser)
-		userManager := manager.NewUserManager(userStorage)
-		var err error
-		updatedUser, err = userManager.UpdateUserStatus(user)
-		return err
+// UpdateUserStatus sets the user's activity flag. When the user is
+// deactivated and reassign is set, their open reviews are handed over to
+// active teammates within the same transaction.
+func UpdateUserStatus(ctx context.Context, user domain.User, reassign bool) (domain.User, []domain.Reassignment, error) {
+	var result domain.UserStatusResult
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		results, err := updateUserStatuses(ctx, storage, []domain.User{user}, reassign)
+		if err != nil {
+			return err
+		}
+		result = results[0]
+		return result.Err
 	}, false)
-	return updatedUser, err
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+	return result.User, result.Reassignments, nil
 }
 
-func GetUsers(ctx context.Context) ([]domain.User, error) {
-	var result []domain.User
-	err := executor.withTransaction(ctx, func(tx *db.Transactor) error {
-		userStorage := db.NewUserStorage(config, *tx)
-		userStorage.SetSelectQuery(db.SelectUser)
-		userManager := manager.NewUserManager(userStorage)
+// BulkUpdateUserStatus sets the activity flags of many users in one
+// transaction. Unknown users get an error in their result and do not affect
+// the others.
+func BulkUpdateUserStatus(ctx context.Context, users []domain.User, reassign bool) ([]domain.UserStatusResult, error) {
+	var results []domain.UserStatusResult
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
 		var err error
-		result, err = userManager.SelectUsers(nil)
+		results, err = updateUserStatuses(ctx, storage, users, reassign)
 		return err
-	}, true)
-	return result, err
+	}, false)
+	return results, err
+}
+
+// updateUserStatuses releases reviews only after every status is applied, so
+// they are never handed over to a user deactivated by the same call. Only users
+// who were active before get user.deactivated.
+func updateUserStatuses(ctx context.Context, storage *db.Storage, users []domain.User, reassign bool) ([]domain.UserStatusResult, error) {
+	userManager := manager.NewUserManager(storage.UserStorage)
+	results := make([]domain.UserStatusResult, len(users))
+	wasActive := make([]bool, len(users))
+	for i, user := range users {
+		existingUser, err := userManager.SelectUser(&user.UserID)
+		if err == nil {
+			wasActive[i] = existingUser.IsActive
+			user, err = userManager.UpdateUserStatus(user)
+		}
+		if errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrNotFound) {
+			results[i] = domain.UserStatusResult{User: users[i], Err: err}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		results[i] = domain.UserStatusResult{User: user, Reassignments: []domain.Reassignment{}}
+	}
+
+	pullRequestManager := newPullRequestManager(storage)
+	for i, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		details := map[string]any{
+			"is_active": result.User.IsActive,
+		}
+		if !result.User.IsActive && reassign {
+			reassignments, err := pullRequestManager.ReleaseDeactivatedReviewer(result.User)
+			if err != nil {
+				return nil, err
+			}
+			results[i].Reassignments = reassignments
+			details["reassignments"] = reassignments
+		}
+		err := recordAudit(ctx, storage, domain.AuditUserStatusUpdated, domain.AuditEntityUser, result.User.UserID, details)
+		if err != nil {
+			return nil, err
+		}
+		err = publishReassignments(storage, pullRequestManager, result.User.UserID, results[i].Reassignments, domain.AssignmentUserDeactivated)
+		if err != nil {
+			return nil, err
+		}
+		if result.User.IsActive || !wasActive[i] {
+			continue
+		}
+		err = publishEvent(storage, domain.EventUserDeactivated, map[string]any{
+			"user":          result.User,
+			"reassignments": results[i].Reassignments,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
 }
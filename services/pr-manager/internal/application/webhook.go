@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/manager"
+	"github.com/zemld/pr-manager/pr-manager/internal/webhook"
+)
+
+const (
+	defaultDeliveryLimit = 100
+	maxDeliveryLimit     = 1000
+)
+
+var webhookDispatcher = webhook.NewDispatcher(allowPrivateWebhooks)
+
+// allowPrivateWebhooks reports whether webhooks may target loopback,
+// link-local and private addresses. They are refused unless
+// WEBHOOK_ALLOW_PRIVATE is true.
+func allowPrivateWebhooks() bool {
+	return os.Getenv("WEBHOOK_ALLOW_PRIVATE") == "true"
+}
+
+func SubscribeWebhook(ctx context.Context, subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	var result domain.WebhookSubscription
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		webhookManager := manager.NewWebhookManager(storage.WebhookStorage)
+		webhookManager.AllowPrivateTargets = allowPrivateWebhooks()
+		var err error
+		result, err = webhookManager.Subscribe(subscription)
+		return err
+	}, false)
+	return result, err
+}
+
+func UnsubscribeWebhook(ctx context.Context, id int64) error {
+	return backend.withStorage(ctx, func(storage *db.Storage) error {
+		webhookManager := manager.NewWebhookManager(storage.WebhookStorage)
+		return webhookManager.Unsubscribe(id)
+	}, false)
+}
+
+// GetWebhookDeliveries returns the latest delivery attempts of the webhook,
+// newest first. Without a limit at most defaultDeliveryLimit are returned.
+func GetWebhookDeliveries(ctx context.Context, id int64, limit int) ([]domain.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	limit = min(limit, maxDeliveryLimit)
+
+	var result []domain.WebhookDelivery
+	err := backend.withStorage(ctx, func(storage *db.Storage) error {
+		webhookManager := manager.NewWebhookManager(storage.WebhookStorage)
+		var err error
+		result, err = webhookManager.GetDeliveries(id, limit)
+		return err
+	}, true)
+	return result, err
+}
+
+// webhookSink posts events to the webhooks subscribed to their type,
+// recording every attempt. Webhooks that accepted the event before are
+// skipped, so a retried event reaches only those that did not.
+type webhookSink struct {
+	backend    storageBackend
+	dispatcher *webhook.Dispatcher
+}
+
+func (webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s webhookSink) Publish(ctx context.Context, event domain.WebhookEvent) error {
+	var subscriptions []domain.WebhookSubscription
+	var deliveries []domain.WebhookDelivery
+	err := s.backend.withStorage(ctx, func(storage *db.Storage) error {
+		var err error
+		subscriptions, err = storage.WebhookStorage.SelectSubscribed(event.Type)
+		if err != nil {
+			return err
+		}
+		deliveries, err = storage.WebhookStorage.SelectEventDeliveries(event.ID)
+		return err
+	}, true)
+	if err != nil {
+		return err
+	}
+
+	attempts := make(map[int64]int)
+	accepted := make(map[int64]bool)
+	for _, delivery := range deliveries {
+		attempts[delivery.SubscriptionID]++
+		if delivery.Error == nil {
+			accepted[delivery.SubscriptionID] = true
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(subscriptions))
+	for i, subscription := range subscriptions {
+		if accepted[subscription.ID] {
+			continue
+		}
+		wg.Go(func() {
+			errs[i] = s.deliver(ctx, subscription, event, attempts[subscription.ID]+1)
+		})
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (s webhookSink) deliver(ctx context.Context, subscription domain.WebhookSubscription, event domain.WebhookEvent, attempt int) error {
+	delivery := s.dispatcher.Deliver(ctx, subscription, event, attempt)
+	err := s.backend.withStorage(ctx, func(storage *db.Storage) error {
+		return storage.WebhookStorage.InsertDelivery(delivery)
+	}, false)
+	if err != nil {
+		return fmt.Errorf("failed to record delivery to webhook %d: %w", subscription.ID, err)
+	}
+	if delivery.Error != nil {
+		return fmt.Errorf("webhook %d: %s", subscription.ID, *delivery.Error)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Absence is a period in which an active user is not picked as a reviewer,
+// e.g. a vacation. The period includes StartsAt and excludes EndsAt.
+type Absence struct {
+	ID       int64     `json:"id"`
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// Covers reports whether t falls into the absence.
+func (a Absence) Covers(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
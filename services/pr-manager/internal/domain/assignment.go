@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// AssignmentReason tells why reviewers were assigned to or unassigned from a
+// pull request.
+type AssignmentReason string
+
+const (
+	// AssignmentInitial is the assignment when the pull request opens.
+	AssignmentInitial         AssignmentReason = "initial"
+	AssignmentReassign        AssignmentReason = "reassign"
+	AssignmentUserDeactivated AssignmentReason = "user_deactivated"
+	AssignmentTeamDeleted     AssignmentReason = "team_deleted"
+	AssignmentTeamRestored    AssignmentReason = "team_restored"
+	AssignmentMemberLeft      AssignmentReason = "member_left"
+	AssignmentClosed          AssignmentReason = "pr_closed"
+	AssignmentReopened        AssignmentReason = "pr_reopened"
+	// AssignmentTeamPurged is a reviewer removed when their deleted team is
+	// purged.
+	AssignmentTeamPurged AssignmentReason = "team_purged"
+	// AssignmentEscalated is the reassignment of a stale review or the team
+	// lead assigned to it.
+	AssignmentEscalated AssignmentReason = "review_escalated"
+)
+
+type AssignmentAction string
+
+const (
+	Assigned   AssignmentAction = "assigned"
+	Unassigned AssignmentAction = "unassigned"
+)
+
+// AssignmentEvent records a reviewer assigned to or unassigned from a pull
+// request.
+type AssignmentEvent struct {
+	ReviewerID string           `json:"reviewer_id"`
+	Action     AssignmentAction `json:"action"`
+	Reason     AssignmentReason `json:"reason"`
+	CreatedAt  *time.Time       `json:"created_at"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditPullRequestCreated    AuditAction = "pull_request.created"
+	AuditPullRequestMerged     AuditAction = "pull_request.merged"
+	AuditPullRequestReassigned AuditAction = "pull_request.reassigned"
+	AuditPullRequestReady      AuditAction = "pull_request.ready"
+	AuditPullRequestClosed     AuditAction = "pull_request.closed"
+	AuditPullRequestReopened   AuditAction = "pull_request.reopened"
+	AuditReviewSubmitted       AuditAction = "pull_request.review_submitted"
+	AuditReviewEscalated       AuditAction = "pull_request.review_escalated"
+	AuditTeamCreated           AuditAction = "team.created"
+	AuditTeamDeleted           AuditAction = "team.deleted"
+	AuditTeamRestored          AuditAction = "team.restored"
+	AuditTeamPurged            AuditAction = "team.purged"
+	AuditTeamMemberAdded       AuditAction = "team.member_added"
+	AuditTeamMemberRemoved     AuditAction = "team.member_removed"
+	AuditTeamMemberMoved       AuditAction = "team.member_moved"
+	AuditMergePolicyUpdated    AuditAction = "team.merge_policy_updated"
+	AuditTeamSettingsUpdated   AuditAction = "team.settings_updated"
+	AuditCodeownersUpdated     AuditAction = "team.codeowners_updated"
+	AuditReviewerPoolUpdated   AuditAction = "reviewer_pool.updated"
+	AuditUserStatusUpdated     AuditAction = "user.status_updated"
+	AuditUserAbsenceAdded      AuditAction = "user.absence_added"
+)
+
+type AuditEntity string
+
+const (
+	AuditEntityPullRequest  AuditEntity = "pull_request"
+	AuditEntityTeam         AuditEntity = "team"
+	AuditEntityUser         AuditEntity = "user"
+	AuditEntityReviewerPool AuditEntity = "reviewer_pool"
+)
+
+// AuditEvent records a state-changing operation. Actor is nil if the caller
+// did not identify itself. Details hold what the operation changed.
+type AuditEvent struct {
+	ID         int64          `json:"id"`
+	Action     AuditAction    `json:"action"`
+	EntityType AuditEntity    `json:"entity_type"`
+	EntityID   string         `json:"entity_id"`
+	Actor      *string        `json:"actor"`
+	Details    map[string]any `json:"details"`
+	CreatedAt  *time.Time     `json:"created_at,omitempty"`
+}
+
+// AuditFilter selects audit events, newest first. Unset fields do not filter.
+// The time window includes From and excludes To.
+type AuditFilter struct {
+	EntityType *AuditEntity
+	EntityID   *string
+	Actor      *string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+// Matches reports whether the event is selected by the filter, regardless of
+// the limit.
+func (f AuditFilter) Matches(event AuditEvent) bool {
+	if f.EntityType != nil && event.EntityType != *f.EntityType {
+		return false
+	}
+	if f.EntityID != nil && event.EntityID != *f.EntityID {
+		return false
+	}
+	if f.Actor != nil && (event.Actor == nil || *event.Actor != *f.Actor) {
+		return false
+	}
+	if event.CreatedAt == nil {
+		return f.From == nil && f.To == nil
+	}
+	return (f.From == nil || !event.CreatedAt.Before(*f.From)) && (f.To == nil || event.CreatedAt.Before(*f.To))
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// Codeowners is the ownership file of a team in CODEOWNERS format. Owners of
+// the paths a pull request changes are preferred as its reviewers or, if
+// Required is set, are the only ones picked.
+type Codeowners struct {
+	TeamName  string     `json:"team_name"`
+	Content   string     `json:"content"`
+	Required  bool       `json:"required"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,147 @@
+// Package codeowners parses CODEOWNERS-style ownership files and finds the
+// owners of changed paths.
+//
+// A file holds one rule per line: a path pattern followed by the owners' user
+// IDs, optionally prefixed with "@". Empty lines and lines starting with "#"
+// are ignored. Patterns follow the CODEOWNERS rules: "*" matches within a path
+// segment, "**" across segments, "?" a single character; a leading "/" or a
+// "/" in the middle anchors the pattern to the repository root, a trailing "/"
+// matches directories only. The last matching rule wins, and a rule without
+// owners leaves the path unowned. Negation ("!") and character ranges ("[ ]")
+// are not supported, as on GitHub.
+package codeowners
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var ErrUnsupportedPattern = errors.New("unsupported pattern")
+
+// Rule assigns owners to the paths matching Pattern.
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+	re      *regexp.Regexp
+}
+
+// Rules are the rules of one file in file order.
+type Rules []Rule
+
+// SyntaxError reports the line of the file that could not be parsed.
+type SyntaxError struct {
+	Line int
+	Err  error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+func Parse(content string) (Rules, error) {
+	var rules Rules
+	for i, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		pattern := strings.TrimPrefix(fields[0], `\`)
+		re, err := compile(pattern)
+		if err != nil {
+			return nil, &SyntaxError{Line: i + 1, Err: err}
+		}
+
+		rule := Rule{Pattern: pattern, Owners: []string{}, Line: i + 1, re: re}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			owner = strings.TrimPrefix(owner, "@")
+			if owner != "" && !slices.Contains(rule.Owners, owner) {
+				rule.Owners = append(rule.Owners, owner)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Match reports whether the rule's pattern matches path.
+func (r Rule) Match(path string) bool {
+	return r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// OwnersOf returns the owners of path given by the last matching rule, or nil
+// if the path is unowned.
+func (rules Rules) OwnersOf(path string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].Match(path) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// Owners returns the owners of any of the paths, each once, in the order
+// they are first found.
+func (rules Rules) Owners(paths []string) []string {
+	owners := []string{}
+	for _, path := range paths {
+		for _, owner := range rules.OwnersOf(path) {
+			if !slices.Contains(owners, owner) {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, "!") || strings.ContainsAny(pattern, "[]") {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedPattern, pattern)
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+	if trimmed == "" {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedPattern, pattern)
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch c := trimmed[i]; {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if dirOnly {
+		expr.WriteString("/.*$")
+	} else {
+		expr.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(expr.String())
+}
@@ -0,0 +1,115 @@
+package codeowners
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	rules, err := Parse(`
+# Default owners
+*            @u1
+
+/docs/       @u2 @u2   # inline comment
+*.go         u3
+\#notes      @u4
+/build/logs/
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules) != 5 {
+		t.Fatalf("expected 5 rules, got %d", len(rules))
+	}
+	if !slices.Equal(rules[1].Owners, []string{"u2"}) || rules[1].Line != 5 {
+		t.Errorf("unexpected rule %+v", rules[1])
+	}
+	if rules[3].Pattern != "#notes" {
+		t.Errorf("expected escaped pattern #notes, got %q", rules[3].Pattern)
+	}
+	if len(rules[4].Owners) != 0 {
+		t.Errorf("expected rule without owners, got %v", rules[4].Owners)
+	}
+}
+
+func TestParse_Unsupported(t *testing.T) {
+	for _, content := range []string{"!*.go @u1", "*.[ch] @u1", "* @u1\n/ @u2"} {
+		_, err := Parse(content)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || !errors.Is(err, ErrUnsupportedPattern) {
+			t.Errorf("expected unsupported pattern error for %q, got %v", content, err)
+		}
+	}
+}
+
+func TestRule_Match(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "main.go", true},
+		{"*", "cmd/main.go", true},
+		{"*.go", "internal/domain/pr.go", true},
+		{"*.go", "README.md", false},
+		{"docs", "docs/index.md", true},
+		{"docs", "api/docs/index.md", true},
+		{"/docs", "api/docs/index.md", false},
+		{"docs/", "docs", false},
+		{"docs/", "api/docs/index.md", true},
+		{"/docs/", "docs/guide/index.md", true},
+		{"apps/*", "apps/web", true},
+		{"apps/*", "apps/web/index.js", true},
+		{"apps/*", "tools/apps/web", false},
+		{"**/logs", "build/logs/today.log", true},
+		{"**/logs", "logs/today.log", true},
+		{"/build/**/*.log", "build/a/b/c.log", true},
+		{"/build/**/*.log", "build/c.log", true},
+		{"src/**", "src/a/b.go", true},
+		{"src/**", "lib/src/a.go", false},
+		{"file?.txt", "dir/file1.txt", true},
+		{"file?.txt", "file12.txt", false},
+		{"a.go", "/a.go", true},
+	}
+	for _, tt := range tests {
+		rules, err := Parse(tt.pattern + " @u1")
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", tt.pattern, err)
+		}
+		if got := rules[0].Match(tt.path); got != tt.want {
+			t.Errorf("%q matching %q: expected %v, got %v", tt.pattern, tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestRules_Owners(t *testing.T) {
+	rules, err := Parse(`
+*             @u1
+/api/         @u2 @u3
+/api/docs/
+*.sql         @u4
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{"default owner", []string{"README.md"}, []string{"u1"}},
+		{"last matching rule wins", []string{"api/handlers/team.go"}, []string{"u2", "u3"}},
+		{"rule without owners", []string{"api/docs/index.md"}, []string{}},
+		{"union of paths", []string{"api/dto.go", "migrations/0001.sql", "api/x.go"}, []string{"u2", "u3", "u4"}},
+		{"no paths", nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules.Owners(tt.paths); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
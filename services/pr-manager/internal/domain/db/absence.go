@@ -0,0 +1,105 @@
+package db
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type AbsenceStorage struct {
+	Config
+	Transactor
+	insertQuery     string
+	selectQuery     string
+	absentUserQuery string
+}
+
+func NewAbsenceStorage(config Config, transactor Transactor) *AbsenceStorage {
+	return &AbsenceStorage{Config: config, Transactor: transactor}
+}
+
+func (s *AbsenceStorage) SetInsertQuery(insertQuery string) {
+	s.insertQuery = insertQuery
+}
+
+func (s *AbsenceStorage) SetSelectQuery(selectQuery string) {
+	s.selectQuery = selectQuery
+}
+
+func (s *AbsenceStorage) SetAbsentUserQuery(absentUserQuery string) {
+	s.absentUserQuery = absentUserQuery
+}
+
+func (s *AbsenceStorage) Insert(absence domain.Absence) (domain.Absence, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.insertQuery,
+		absence.UserID,
+		absence.StartsAt,
+		absence.EndsAt,
+	)
+	if err != nil {
+		return domain.Absence{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&absence.ID); err != nil {
+			return domain.Absence{}, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return domain.Absence{}, err
+	}
+
+	return absence, nil
+}
+
+func (s *AbsenceStorage) Select(userID string) ([]domain.Absence, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var absences []domain.Absence
+	for rows.Next() {
+		var absence domain.Absence
+		err = rows.Scan(
+			&absence.ID,
+			&absence.UserID,
+			&absence.StartsAt,
+			&absence.EndsAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		absences = append(absences, absence)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return absences, nil
+}
+
+func (s *AbsenceStorage) SelectAbsentUserIDs() ([]string, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.absentUserQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err = rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
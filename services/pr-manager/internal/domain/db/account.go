@@ -0,0 +1,60 @@
+package db
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type AccountStorage struct {
+	Config
+	Transactor
+	upsertQuery string
+	selectQuery string
+}
+
+func NewAccountStorage(config Config, transactor Transactor) *AccountStorage {
+	return &AccountStorage{Config: config, Transactor: transactor}
+}
+
+func (s *AccountStorage) SetUpsertQuery(upsertQuery string) {
+	s.upsertQuery = upsertQuery
+}
+
+func (s *AccountStorage) SetSelectQuery(selectQuery string) {
+	s.selectQuery = selectQuery
+}
+
+func (s *AccountStorage) Upsert(account domain.ProviderAccount) error {
+	_, err := s.Transactor.Exec(s.ctx, s.upsertQuery,
+		account.Provider,
+		account.Login,
+		account.UserID,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *AccountStorage) Select(provider domain.Provider, login *string) ([]domain.ProviderAccount, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectQuery, provider, login)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []domain.ProviderAccount
+	for rows.Next() {
+		var account domain.ProviderAccount
+		err = rows.Scan(&account.Provider, &account.Login, &account.UserID)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type AuditStorage struct {
+	Config
+	Transactor
+	insertQuery string
+	selectQuery string
+}
+
+func NewAuditStorage(config Config, transactor Transactor) *AuditStorage {
+	return &AuditStorage{Config: config, Transactor: transactor}
+}
+
+func (s *AuditStorage) SetInsertQuery(insertQuery string) {
+	s.insertQuery = insertQuery
+}
+
+func (s *AuditStorage) SetSelectQuery(selectQuery string) {
+	s.selectQuery = selectQuery
+}
+
+func (s *AuditStorage) Insert(event domain.AuditEvent) error {
+	details := event.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+	_, err := s.Transactor.Exec(s.ctx, s.insertQuery,
+		event.Action,
+		event.EntityType,
+		event.EntityID,
+		event.Actor,
+		details,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *AuditStorage) Select(filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectQuery,
+		filter.EntityType,
+		filter.EntityID,
+		filter.Actor,
+		filter.From,
+		filter.To,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.AuditEvent
+	for rows.Next() {
+		var event domain.AuditEvent
+		err = rows.Scan(
+			&event.ID,
+			&event.Action,
+			&event.EntityType,
+			&event.EntityID,
+			&event.Actor,
+			&event.Details,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type CodeownersStorage struct {
+	Config
+	Transactor
+	selectQuery string
+	upsertQuery string
+}
+
+func NewCodeownersStorage(config Config, transactor Transactor) *CodeownersStorage {
+	return &CodeownersStorage{Config: config, Transactor: transactor}
+}
+
+func (s *CodeownersStorage) SetSelectQuery(selectQuery string) {
+	s.selectQuery = selectQuery
+}
+
+func (s *CodeownersStorage) SetUpsertQuery(upsertQuery string) {
+	s.upsertQuery = upsertQuery
+}
+
+func (s *CodeownersStorage) Select(teamName string) ([]domain.Codeowners, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectQuery, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []domain.Codeowners
+	for rows.Next() {
+		var codeowners domain.Codeowners
+		err = rows.Scan(
+			&codeowners.TeamName,
+			&codeowners.Content,
+			&codeowners.Required,
+			&codeowners.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, codeowners)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func (s *CodeownersStorage) Upsert(codeowners domain.Codeowners) error {
+	_, err := s.Transactor.Exec(s.ctx, s.upsertQuery,
+		codeowners.TeamName,
+		codeowners.Content,
+		codeowners.Required,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
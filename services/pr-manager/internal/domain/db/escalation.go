@@ -0,0 +1,66 @@
+package db
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type EscalationStorage struct {
+	Config
+	Transactor
+	selectStaleQuery string
+	insertQuery      string
+}
+
+func NewEscalationStorage(config Config, transactor Transactor) *EscalationStorage {
+	return &EscalationStorage{Config: config, Transactor: transactor}
+}
+
+func (s *EscalationStorage) SetSelectStaleQuery(selectStaleQuery string) {
+	s.selectStaleQuery = selectStaleQuery
+}
+
+func (s *EscalationStorage) SetInsertQuery(insertQuery string) {
+	s.insertQuery = insertQuery
+}
+
+func (s *EscalationStorage) SelectStaleReviews() ([]domain.StaleReview, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectStaleQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []domain.StaleReview
+	for rows.Next() {
+		var review domain.StaleReview
+		err = rows.Scan(
+			&review.PullRequestID,
+			&review.ReviewerID,
+			&review.TeamName,
+			&review.AssignedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func (s *EscalationStorage) Insert(escalation domain.Escalation) error {
+	_, err := s.Transactor.Exec(s.ctx, s.insertQuery,
+		escalation.PullRequestID,
+		escalation.ReviewerID,
+		escalation.Action,
+		escalation.AssigneeID,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
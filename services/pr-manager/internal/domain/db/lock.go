@@ -0,0 +1,36 @@
+package db
+
+type LockStorage struct {
+	Config
+	Transactor
+	tryLockQuery string
+}
+
+func NewLockStorage(config Config, transactor Transactor) *LockStorage {
+	return &LockStorage{Config: config, Transactor: transactor}
+}
+
+func (s *LockStorage) SetTryLockQuery(tryLockQuery string) {
+	s.tryLockQuery = tryLockQuery
+}
+
+func (s *LockStorage) TryLock(key int64) (bool, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.tryLockQuery, key)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	locked := false
+	for rows.Next() {
+		if err = rows.Scan(&locked); err != nil {
+			return false, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+
+	return locked, nil
+}
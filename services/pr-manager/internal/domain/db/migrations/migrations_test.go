@@ -0,0 +1,115 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		wantErr  error
+		validate func(*testing.T, []Migration)
+	}{
+		{
+			name: "migrations are paired and ordered by version",
+			files: fstest.MapFS{
+				"0010_add_index.up.sql":   {Data: []byte("CREATE INDEX i ON t (c);")},
+				"0010_add_index.down.sql": {Data: []byte("DROP INDEX i;")},
+				"0002_create.up.sql":      {Data: []byte("CREATE TABLE t (c INT);")},
+				"0002_create.down.sql":    {Data: []byte("DROP TABLE t;")},
+			},
+			validate: func(t *testing.T, migrations []Migration) {
+				if len(migrations) != 2 {
+					t.Fatalf("expected 2 migrations, got %d", len(migrations))
+				}
+				if migrations[0].Version != 2 || migrations[0].Name != "create" {
+					t.Errorf("expected 0002_create first, got %d_%s", migrations[0].Version, migrations[0].Name)
+				}
+				if migrations[1].Version != 10 {
+					t.Errorf("expected version 10 second, got %d", migrations[1].Version)
+				}
+				if migrations[0].Up != "CREATE TABLE t (c INT);" || migrations[0].Down != "DROP TABLE t;" {
+					t.Errorf("unexpected migration content: %+v", migrations[0])
+				}
+			},
+		},
+		{
+			name: "empty down migration is allowed",
+			files: fstest.MapFS{
+				"0001_seed.up.sql":   {Data: []byte("INSERT INTO t VALUES (1);")},
+				"0001_seed.down.sql": {Data: []byte("")},
+			},
+			validate: func(t *testing.T, migrations []Migration) {
+				if len(migrations) != 1 {
+					t.Fatalf("expected 1 migration, got %d", len(migrations))
+				}
+			},
+		},
+		{
+			name: "missing down migration",
+			files: fstest.MapFS{
+				"0001_create.up.sql": {Data: []byte("CREATE TABLE t (c INT);")},
+			},
+			wantErr: ErrMissingDirection,
+		},
+		{
+			name: "same version with different names",
+			files: fstest.MapFS{
+				"0001_create.up.sql":   {Data: []byte("CREATE TABLE t (c INT);")},
+				"0001_create.down.sql": {Data: []byte("DROP TABLE t;")},
+				"0001_other.up.sql":    {Data: []byte("CREATE TABLE o (c INT);")},
+				"0001_other.down.sql":  {Data: []byte("DROP TABLE o;")},
+			},
+			wantErr: ErrDuplicateVersion,
+		},
+		{
+			name: "invalid file name",
+			files: fstest.MapFS{
+				"create_users.sql": {Data: []byte("CREATE TABLE users (id TEXT);")},
+			},
+			wantErr: ErrInvalidFileName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Load(tt.files)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.validate != nil {
+				tt.validate(t, migrations)
+			}
+		})
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	migrations, err := Embedded()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "initial_schema" {
+		t.Errorf("expected 0001_initial_schema first, got %d_%s", migrations[0].Version, migrations[0].Name)
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("expected consecutive versions, got %d at position %d", migration.Version, i)
+		}
+	}
+}
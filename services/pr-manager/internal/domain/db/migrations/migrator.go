@@ -0,0 +1,206 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+)
+
+// lockKey identifies the pg_advisory_lock held while migrating, so replicas
+// starting at the same time apply migrations one after another.
+const lockKey int64 = 0x70726d6967726174
+
+const (
+	createSchemaMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (version)
+	)
+	`
+	selectAppliedMigrations = `
+	SELECT
+		version,
+		name,
+		applied_at
+	FROM
+		schema_migrations
+	ORDER BY
+		version
+	`
+	insertAppliedMigration = `
+	INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, NOW())
+	`
+	deleteAppliedMigration = `
+	DELETE FROM schema_migrations WHERE version = $1
+	`
+	lockMigrations = `
+	SELECT pg_advisory_lock($1)
+	`
+	unlockMigrations = `
+	SELECT pg_advisory_unlock($1)
+	`
+)
+
+type Migrator struct {
+	Config     db.Config
+	Migrations []Migration
+}
+
+func NewMigrator(config db.Config, migrations []Migration) *Migrator {
+	return &Migrator{Config: config, Migrations: migrations}
+}
+
+// Up applies every migration that is not recorded in schema_migrations yet.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		appliedVersions, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.Migrations {
+			if _, ok := appliedVersions[migration.Version]; ok {
+				continue
+			}
+			err = m.run(ctx, conn, migration.Up, insertAppliedMigration, migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the latest steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var rolledBack []Migration
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		appliedVersions, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(appliedVersions) == 0 {
+			return ErrNothingToRollback
+		}
+		for version := range appliedVersions {
+			if !slices.ContainsFunc(m.Migrations, func(migration Migration) bool {
+				return migration.Version == version
+			}) {
+				return fmt.Errorf("%w: %d", ErrUnknownMigration, version)
+			}
+		}
+		for i := len(m.Migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			migration := m.Migrations[i]
+			if _, ok := appliedVersions[migration.Version]; !ok {
+				continue
+			}
+			err = m.run(ctx, conn, migration.Down, deleteAppliedMigration, migration.Version)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			delete(appliedVersions, migration.Version)
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Status lists known migrations with the time they were applied, if ever.
+// Applied versions this build does not know about are listed after them.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		appliedVersions, err := m.appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.Migrations {
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if applied, ok := appliedVersions[migration.Version]; ok {
+				status.AppliedAt = &applied.AppliedAt
+				delete(appliedVersions, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for _, version := range slices.Sorted(maps.Keys(appliedVersions)) {
+			applied := appliedVersions[version]
+			statuses = append(statuses, Status{Version: applied.Version, Name: applied.Name, AppliedAt: &applied.AppliedAt})
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context, conn *pgx.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.Query(ctx, selectAppliedMigrations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var migration appliedMigration
+		err = rows.Scan(&migration.Version, &migration.Name, &migration.AppliedAt)
+		if err != nil {
+			return nil, err
+		}
+		applied[migration.Version] = migration
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) run(ctx context.Context, conn *pgx.Conn, migrationSQL string, bookkeepingSQL string, args ...any) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, migrationSQL); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, bookkeepingSQL, args...); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(*pgx.Conn) error) error {
+	conn, err := pgx.Connect(ctx, m.Config.GetConnectionString())
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err = conn.Exec(ctx, lockMigrations, lockKey); err != nil {
+		return err
+	}
+	defer conn.Exec(context.Background(), unlockMigrations, lockKey)
+
+	if _, err = conn.Exec(ctx, createSchemaMigrationsTable); err != nil {
+		return err
+	}
+	return fn(conn)
+}
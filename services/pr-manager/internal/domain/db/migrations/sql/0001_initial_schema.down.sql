@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS pull_requests_statuses;
+DROP TABLE IF EXISTS users;
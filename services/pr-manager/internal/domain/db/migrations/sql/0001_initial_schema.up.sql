@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT NOT NULL,
+	username TEXT NOT NULL,
+	team_name TEXT NOT NULL,
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	team_deleted BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS pull_requests_statuses (
+	id SERIAL,
+	status TEXT NOT NULL UNIQUE,
+	PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS pull_requests (
+	id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	author_id TEXT NOT NULL,
+	status_id INT NOT NULL,
+	assigned_reviewers TEXT NOT NULL,
+	created_at TIMESTAMP,
+	merged_at TIMESTAMP,
+	PRIMARY KEY (id),
+	FOREIGN KEY (author_id) REFERENCES users (id),
+	FOREIGN KEY (status_id) REFERENCES pull_requests_statuses (id)
+);
+
+INSERT INTO pull_requests_statuses (status) VALUES ('open'), ('merged')
+ON CONFLICT (status) DO NOTHING;
@@ -0,0 +1,19 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS assigned_reviewers TEXT NOT NULL DEFAULT '[]';
+
+UPDATE
+	pull_requests
+SET
+	assigned_reviewers = '[' || COALESCE(
+		(
+			SELECT
+				string_agg(user_id, ', ' ORDER BY assigned_at, user_id)
+			FROM
+				pull_request_reviewers
+			WHERE pr_id = pull_requests.id
+		),
+		''
+	) || ']';
+
+ALTER TABLE pull_requests ALTER COLUMN assigned_reviewers DROP DEFAULT;
+
+DROP TABLE IF EXISTS pull_request_reviewers;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS pull_request_reviewers (
+	pr_id TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	assigned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	state TEXT NOT NULL DEFAULT 'pending',
+	PRIMARY KEY (pr_id, user_id),
+	FOREIGN KEY (pr_id) REFERENCES pull_requests (id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS pull_request_reviewers_user_id_idx ON pull_request_reviewers (user_id);
+
+-- Databases initialized before the reviewers table existed may have already
+-- dropped the legacy column, so the conversion is guarded.
+DO $$
+BEGIN
+	IF EXISTS (
+		SELECT
+			1
+		FROM
+			information_schema.columns
+		WHERE table_name = 'pull_requests'
+			AND column_name = 'assigned_reviewers'
+	) THEN
+		INSERT INTO
+			pull_request_reviewers
+			(pr_id, user_id, assigned_at)
+		SELECT
+			pull_requests.id,
+			trim(reviewer),
+			COALESCE(pull_requests.created_at, NOW())
+		FROM
+			pull_requests,
+			regexp_split_to_table(trim(both '[]' from pull_requests.assigned_reviewers), ',') AS reviewer
+		WHERE trim(reviewer) <> ''
+			AND EXISTS (SELECT 1 FROM users WHERE users.id = trim(reviewer))
+		ON CONFLICT
+			(pr_id, user_id) DO NOTHING;
+
+		ALTER TABLE pull_requests DROP COLUMN assigned_reviewers;
+	END IF;
+END
+$$;
@@ -0,0 +1,3 @@
+ALTER TABLE pull_request_reviewers DROP COLUMN IF EXISTS submitted_at;
+
+UPDATE pull_request_reviewers SET state = 'pending';
@@ -0,0 +1 @@
+ALTER TABLE pull_request_reviewers ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP NULL;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS merge_policy_overrides;
+
+DROP TABLE IF EXISTS team_merge_policies;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS team_merge_policies (
+	team_name TEXT NOT NULL,
+	min_approvals INTEGER NOT NULL DEFAULT 0,
+	block_on_changes_requested BOOLEAN NOT NULL DEFAULT FALSE,
+	forbid_self_approval BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (team_name),
+	CHECK (min_approvals >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS merge_policy_overrides (
+	id BIGSERIAL NOT NULL,
+	pr_id TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	unmet_rules TEXT[] NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (id),
+	FOREIGN KEY (pr_id) REFERENCES pull_requests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS merge_policy_overrides_pr_id_idx ON merge_policy_overrides (pr_id);
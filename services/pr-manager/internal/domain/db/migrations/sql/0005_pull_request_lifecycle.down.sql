@@ -0,0 +1,10 @@
+-- Draft and closed pull requests cannot be represented without these statuses;
+-- they are reopened so that no rows reference the removed statuses.
+UPDATE
+	pull_requests
+SET
+	status_id = (SELECT id FROM pull_requests_statuses WHERE status = 'open' LIMIT 1)
+WHERE
+	status_id IN (SELECT id FROM pull_requests_statuses WHERE status IN ('draft', 'closed'));
+
+DELETE FROM pull_requests_statuses WHERE status IN ('draft', 'closed');
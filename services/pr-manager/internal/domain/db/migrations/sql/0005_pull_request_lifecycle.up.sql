@@ -0,0 +1,2 @@
+INSERT INTO pull_requests_statuses (status) VALUES ('draft'), ('closed')
+ON CONFLICT (status) DO NOTHING;
@@ -0,0 +1,3 @@
+UPDATE users SET team_name = '', team_deleted = TRUE WHERE team_name IS NULL;
+
+ALTER TABLE users ALTER COLUMN team_name SET NOT NULL;
@@ -0,0 +1,3 @@
+-- Users removed from their team keep their row (they may still author or have
+-- reviewed pull requests) with no team.
+ALTER TABLE users ALTER COLUMN team_name DROP NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS team_deleted_at;
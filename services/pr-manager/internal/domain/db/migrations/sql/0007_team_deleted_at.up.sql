@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS team_deleted_at TIMESTAMP NULL;
+
+-- The deletion time of teams deleted before this migration is unknown, so
+-- their retention period starts now.
+UPDATE users SET team_deleted_at = NOW() WHERE team_deleted = TRUE AND team_deleted_at IS NULL;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS pull_request_reviews (
+	id BIGSERIAL PRIMARY KEY,
+	pr_id TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	state TEXT NOT NULL,
+	submitted_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	FOREIGN KEY (pr_id, user_id) REFERENCES pull_request_reviewers (pr_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS pull_request_reviews_pr_id_user_id_idx ON pull_request_reviews (pr_id, user_id);
+
+-- Only the latest review of each reviewer is known for existing data.
+INSERT INTO
+	pull_request_reviews
+	(pr_id, user_id, state, submitted_at)
+SELECT
+	pr_id,
+	user_id,
+	state,
+	submitted_at
+FROM
+	pull_request_reviewers
+WHERE state <> 'pending'
+	AND submitted_at IS NOT NULL;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS pull_request_assignments (
+	id BIGSERIAL NOT NULL,
+	pr_id TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	action TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (id),
+	FOREIGN KEY (pr_id) REFERENCES pull_requests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS pull_request_assignments_pr_id_idx ON pull_request_assignments (pr_id);
+
+-- Earlier changes are unknown, so current reviewers count as assigned initially.
+INSERT INTO
+	pull_request_assignments
+	(pr_id, user_id, action, reason, created_at)
+SELECT
+	pr_id,
+	user_id,
+	'assigned',
+	'initial',
+	assigned_at
+FROM
+	pull_request_reviewers
+ORDER BY assigned_at, pr_id, user_id;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS user_absences (
+	id BIGSERIAL NOT NULL,
+	user_id TEXT NOT NULL,
+	starts_at TIMESTAMPTZ NOT NULL,
+	ends_at TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (id),
+	FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+	CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS user_absences_user_id_idx ON user_absences (user_id, starts_at);
+CREATE INDEX IF NOT EXISTS user_absences_ends_at_idx ON user_absences (ends_at);
@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS reviewers_count;
+DROP TABLE IF EXISTS team_settings;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS team_settings (
+	team_name TEXT NOT NULL,
+	reviewers_required INTEGER NOT NULL DEFAULT 2,
+	PRIMARY KEY (team_name),
+	CHECK (reviewers_required BETWEEN 1 AND 10)
+);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS reviewers_count INTEGER NULL CHECK (reviewers_count BETWEEN 1 AND 10);
@@ -0,0 +1,4 @@
+ALTER TABLE team_settings DROP COLUMN IF EXISTS pool_reviewers_required;
+ALTER TABLE team_settings DROP COLUMN IF EXISTS reviewer_pool;
+DROP TABLE IF EXISTS reviewer_pool_members;
+DROP TABLE IF EXISTS reviewer_pools;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS reviewer_pools (
+	name TEXT NOT NULL,
+	PRIMARY KEY (name)
+);
+
+CREATE TABLE IF NOT EXISTS reviewer_pool_members (
+	pool_name TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	PRIMARY KEY (pool_name, user_id),
+	FOREIGN KEY (pool_name) REFERENCES reviewer_pools (name) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS reviewer_pool TEXT NULL REFERENCES reviewer_pools (name);
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS pool_reviewers_required INTEGER NOT NULL DEFAULT 0 CHECK (pool_reviewers_required BETWEEN 0 AND 10);
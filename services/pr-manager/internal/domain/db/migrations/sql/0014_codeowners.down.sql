@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS changed_paths;
+DROP TABLE IF EXISTS team_codeowners;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS team_codeowners (
+	team_name TEXT NOT NULL,
+	content TEXT NOT NULL,
+	required BOOLEAN NOT NULL DEFAULT FALSE,
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (team_name)
+);
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS changed_paths TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS review_escalations;
+ALTER TABLE team_settings DROP COLUMN IF EXISTS team_lead;
+ALTER TABLE team_settings DROP COLUMN IF EXISTS sla_action;
+ALTER TABLE team_settings DROP COLUMN IF EXISTS review_sla_hours;
@@ -0,0 +1,16 @@
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS review_sla_hours INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_hours >= 0);
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS sla_action TEXT NULL CHECK (sla_action IN ('reassign', 'escalate'));
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS team_lead TEXT NULL REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS review_escalations (
+	id BIGSERIAL NOT NULL,
+	pr_id TEXT NOT NULL,
+	reviewer_id TEXT NOT NULL,
+	action TEXT NOT NULL,
+	assignee_id TEXT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (id),
+	FOREIGN KEY (pr_id) REFERENCES pull_requests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS review_escalations_pr_id_reviewer_id_idx ON review_escalations (pr_id, reviewer_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS outbox (
+	id BIGSERIAL NOT NULL,
+	event_id TEXT NOT NULL,
+	event_type TEXT NOT NULL,
+	payload JSONB NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NULL,
+	available_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	published_at TIMESTAMP NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (id),
+	UNIQUE (event_id)
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (available_at, id) WHERE published_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_event_id_idx ON webhook_deliveries (event_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS provider_accounts (
+	provider TEXT NOT NULL,
+	login TEXT NOT NULL,
+	user_id TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (provider, login),
+	FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
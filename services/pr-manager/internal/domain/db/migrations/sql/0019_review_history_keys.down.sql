@@ -0,0 +1,13 @@
+ALTER TABLE pull_request_reviews DROP CONSTRAINT IF EXISTS pull_request_reviews_user_id_fkey;
+ALTER TABLE pull_request_reviews DROP CONSTRAINT IF EXISTS pull_request_reviews_pr_id_fkey;
+-- Reviews of unassigned reviewers cannot reference a reviewer row.
+DELETE FROM pull_request_reviews
+WHERE NOT EXISTS (
+	SELECT
+		1
+	FROM
+		pull_request_reviewers
+	WHERE pull_request_reviewers.pr_id = pull_request_reviews.pr_id
+		AND pull_request_reviewers.user_id = pull_request_reviews.user_id
+);
+ALTER TABLE pull_request_reviews ADD CONSTRAINT pull_request_reviews_pr_id_user_id_fkey FOREIGN KEY (pr_id, user_id) REFERENCES pull_request_reviewers (pr_id, user_id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+-- Reviews outlive the assignment of their reviewer, so they reference the pull
+-- request and the user instead of the reviewer row.
+ALTER TABLE pull_request_reviews DROP CONSTRAINT IF EXISTS pull_request_reviews_pr_id_user_id_fkey;
+ALTER TABLE pull_request_reviews ADD CONSTRAINT pull_request_reviews_pr_id_fkey FOREIGN KEY (pr_id) REFERENCES pull_requests (id) ON DELETE CASCADE;
+ALTER TABLE pull_request_reviews ADD CONSTRAINT pull_request_reviews_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
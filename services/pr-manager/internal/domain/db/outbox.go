@@ -0,0 +1,118 @@
+package db
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type OutboxStorage struct {
+	Config
+	Transactor
+	insertQuery          string
+	claimQuery           string
+	markPublishedQuery   string
+	markFailedQuery      string
+	deletePublishedQuery string
+}
+
+func NewOutboxStorage(config Config, transactor Transactor) *OutboxStorage {
+	return &OutboxStorage{Config: config, Transactor: transactor}
+}
+
+func (s *OutboxStorage) SetInsertQuery(insertQuery string) {
+	s.insertQuery = insertQuery
+}
+
+func (s *OutboxStorage) SetClaimQuery(claimQuery string) {
+	s.claimQuery = claimQuery
+}
+
+func (s *OutboxStorage) SetMarkPublishedQuery(markPublishedQuery string) {
+	s.markPublishedQuery = markPublishedQuery
+}
+
+func (s *OutboxStorage) SetMarkFailedQuery(markFailedQuery string) {
+	s.markFailedQuery = markFailedQuery
+}
+
+func (s *OutboxStorage) SetDeletePublishedQuery(deletePublishedQuery string) {
+	s.deletePublishedQuery = deletePublishedQuery
+}
+
+func (s *OutboxStorage) Insert(event domain.WebhookEvent) error {
+	data := event.Data
+	if data == nil {
+		data = map[string]any{}
+	}
+	_, err := s.Transactor.Exec(s.ctx, s.insertQuery,
+		event.ID,
+		event.Type,
+		data,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *OutboxStorage) Claim(limit int, maxAttempts int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.claimQuery, limit, maxAttempts, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []domain.OutboxMessage
+	for rows.Next() {
+		var message domain.OutboxMessage
+		err = rows.Scan(
+			&message.ID,
+			&message.Event.ID,
+			&message.Event.Type,
+			&message.Event.Data,
+			&message.Attempts,
+			&message.Event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING does not keep the order of the subquery.
+	slices.SortFunc(messages, func(a, b domain.OutboxMessage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return messages, nil
+}
+
+func (s *OutboxStorage) MarkPublished(id int64) error {
+	_, err := s.Transactor.Exec(s.ctx, s.markPublishedQuery, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *OutboxStorage) MarkFailed(id int64, reason string, retryAfter time.Duration) error {
+	_, err := s.Transactor.Exec(s.ctx, s.markFailedQuery, id, reason, retryAfter.Seconds())
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *OutboxStorage) DeletePublished(retention time.Duration) (int64, error) {
+	commandTag, err := s.Transactor.Exec(s.ctx, s.deletePublishedQuery, retention.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return commandTag.RowsAffected(), nil
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type PolicyStorage struct {
+	Config
+	Transactor
+	selectQuery         string
+	upsertQuery         string
+	insertOverrideQuery string
+}
+
+func NewPolicyStorage(config Config, transactor Transactor) *PolicyStorage {
+	return &PolicyStorage{Config: config, Transactor: transactor}
+}
+
+func (s *PolicyStorage) SetSelectQuery(selectQuery string) {
+	s.selectQuery = selectQuery
+}
+
+func (s *PolicyStorage) SetUpsertQuery(upsertQuery string) {
+	s.upsertQuery = upsertQuery
+}
+
+func (s *PolicyStorage) SetInsertOverrideQuery(insertOverrideQuery string) {
+	s.insertOverrideQuery = insertOverrideQuery
+}
+
+func (s *PolicyStorage) Select(teamName string) ([]domain.MergePolicy, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectQuery, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []domain.MergePolicy
+	for rows.Next() {
+		var policy domain.MergePolicy
+		err = rows.Scan(
+			&policy.TeamName,
+			&policy.MinApprovals,
+			&policy.BlockOnChangesRequested,
+			&policy.ForbidSelfApproval,
+		)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+func (s *PolicyStorage) Upsert(policy domain.MergePolicy) error {
+	_, err := s.Transactor.Exec(s.ctx, s.upsertQuery,
+		policy.TeamName,
+		policy.MinApprovals,
+		policy.BlockOnChangesRequested,
+		policy.ForbidSelfApproval,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PolicyStorage) InsertOverride(override domain.MergePolicyOverride) error {
+	_, err := s.Transactor.Exec(s.ctx, s.insertOverrideQuery,
+		override.PullRequestID,
+		override.Reason,
+		override.UnmetRules,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type PoolStorage struct {
+	Config
+	Transactor
+	selectQuery         string
+	upsertQuery         string
+	replaceMembersQuery string
+}
+
+func NewPoolStorage(config Config, transactor Transactor) *PoolStorage {
+	return &PoolStorage{Config: config, Transactor: transactor}
+}
+
+func (s *PoolStorage) SetSelectQuery(selectQuery string) {
+	s.selectQuery = selectQuery
+}
+
+func (s *PoolStorage) SetUpsertQuery(upsertQuery string) {
+	s.upsertQuery = upsertQuery
+}
+
+func (s *PoolStorage) SetReplaceMembersQuery(replaceMembersQuery string) {
+	s.replaceMembersQuery = replaceMembersQuery
+}
+
+func (s *PoolStorage) Select(name string) ([]domain.ReviewerPool, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectQuery, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pools []domain.ReviewerPool
+	for rows.Next() {
+		var pool domain.ReviewerPool
+		err = rows.Scan(
+			&pool.Name,
+			&pool.Members,
+		)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, pool)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return pools, nil
+}
+
+func (s *PoolStorage) Upsert(pool domain.ReviewerPool) error {
+	_, err := s.Transactor.Exec(s.ctx, s.upsertQuery, pool.Name)
+	if err != nil {
+		return err
+	}
+	members := pool.Members
+	if members == nil {
+		members = []string{}
+	}
+	_, err = s.Transactor.Exec(s.ctx, s.replaceMembersQuery, pool.Name, members)
+	return err
+}
@@ -13,7 +13,12 @@ type PullRequestStorage struct {
 	createQuery                  string
 	mergeQuery                   string
 	reassignQuery                string
+	assignReviewersQuery         string
 	userPullRequestsReviewsQuery string
+	countUserOpenReviewsQuery    string
+	submitReviewQuery            string
+	updateStatusQuery            string
+	historyQuery                 string
 }
 
 func NewPullRequestStorage(config Config, transactor Transactor) *PullRequestStorage {
@@ -36,10 +41,30 @@ func (s *PullRequestStorage) SetReassignQuery(reassignQuery string) {
 	s.reassignQuery = reassignQuery
 }
 
+func (s *PullRequestStorage) SetAssignReviewersQuery(assignReviewersQuery string) {
+	s.assignReviewersQuery = assignReviewersQuery
+}
+
 func (s *PullRequestStorage) SetUserPullRequestsReviewsQuery(query string) {
 	s.userPullRequestsReviewsQuery = query
 }
 
+func (s *PullRequestStorage) SetCountUserOpenReviewsQuery(query string) {
+	s.countUserOpenReviewsQuery = query
+}
+
+func (s *PullRequestStorage) SetHistoryQuery(query string) {
+	s.historyQuery = query
+}
+
+func (s *PullRequestStorage) SetSubmitReviewQuery(query string) {
+	s.submitReviewQuery = query
+}
+
+func (s *PullRequestStorage) SetUpdateStatusQuery(query string) {
+	s.updateStatusQuery = query
+}
+
 func (s *PullRequestStorage) Select(pullRequestID *string) ([]domain.PullRequest, error) {
 	var filter any
 	if pullRequestID != nil {
@@ -63,6 +88,9 @@ func (s *PullRequestStorage) Select(pullRequestID *string) ([]domain.PullRequest
 			&pullRequest.AuthorID,
 			&pullRequest.Status,
 			&pullRequest.AssignedReviewers,
+			&pullRequest.Reviews,
+			&pullRequest.ReviewersCount,
+			&pullRequest.ChangedPaths,
 			&pullRequest.CreatedAt,
 			&pullRequest.MergedAt,
 		)
@@ -80,11 +108,17 @@ func (s *PullRequestStorage) Select(pullRequestID *string) ([]domain.PullRequest
 }
 
 func (s *PullRequestStorage) Create(pullRequest domain.PullRequest) error {
+	changedPaths := pullRequest.ChangedPaths
+	if changedPaths == nil {
+		changedPaths = []string{}
+	}
 	commandTag, err := s.Transactor.Exec(s.ctx, s.createQuery,
 		pullRequest.ID,
 		pullRequest.Name,
 		pullRequest.AuthorID,
-		pullRequest.AssignedReviewers,
+		pullRequest.Status,
+		pullRequest.ReviewersCount,
+		changedPaths,
 	)
 	if err != nil {
 		return err
@@ -92,7 +126,7 @@ func (s *PullRequestStorage) Create(pullRequest domain.PullRequest) error {
 	if commandTag.RowsAffected() == 0 {
 		return errors.New("PR id already exists")
 	}
-	return nil
+	return s.assignReviewers(pullRequest, domain.AssignmentInitial)
 }
 
 func (s *PullRequestStorage) Merge(pullRequest domain.PullRequest) error {
@@ -105,10 +139,37 @@ func (s *PullRequestStorage) Merge(pullRequest domain.PullRequest) error {
 	return nil
 }
 
-func (s *PullRequestStorage) Reassign(pullRequest domain.PullRequest) error {
+func (s *PullRequestStorage) UpdateStatus(pullRequestID string, status domain.PullRequestStatus) error {
+	_, err := s.Transactor.Exec(s.ctx, s.updateStatusQuery,
+		pullRequestID,
+		status,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PullRequestStorage) Reassign(pullRequest domain.PullRequest, reason domain.AssignmentReason) error {
 	_, err := s.Transactor.Exec(s.ctx, s.reassignQuery,
 		pullRequest.ID,
 		pullRequest.AssignedReviewers,
+		reason,
+	)
+	if err != nil {
+		return err
+	}
+	return s.assignReviewers(pullRequest, reason)
+}
+
+func (s *PullRequestStorage) assignReviewers(pullRequest domain.PullRequest, reason domain.AssignmentReason) error {
+	if len(pullRequest.AssignedReviewers) == 0 {
+		return nil
+	}
+	_, err := s.Transactor.Exec(s.ctx, s.assignReviewersQuery,
+		pullRequest.ID,
+		pullRequest.AssignedReviewers,
+		reason,
 	)
 	if err != nil {
 		return err
@@ -116,6 +177,21 @@ func (s *PullRequestStorage) Reassign(pullRequest domain.PullRequest) error {
 	return nil
 }
 
+func (s *PullRequestStorage) SubmitReview(pullRequestID string, review domain.Review) error {
+	commandTag, err := s.Transactor.Exec(s.ctx, s.submitReviewQuery,
+		pullRequestID,
+		review.ReviewerID,
+		review.State,
+	)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() == 0 {
+		return domain.ErrNotAssigned
+	}
+	return nil
+}
+
 func (s *PullRequestStorage) SelectUserPullRequestsReviews(userID string) ([]domain.PullRequest, error) {
 	rows, err := s.Transactor.Query(s.ctx, s.userPullRequestsReviewsQuery, userID)
 	if err != nil {
@@ -132,6 +208,9 @@ func (s *PullRequestStorage) SelectUserPullRequestsReviews(userID string) ([]dom
 			&pr.AuthorID,
 			&pr.Status,
 			&pr.AssignedReviewers,
+			&pr.Reviews,
+			&pr.ReviewersCount,
+			&pr.ChangedPaths,
 			&pr.CreatedAt,
 			&pr.MergedAt,
 		)
@@ -147,3 +226,57 @@ func (s *PullRequestStorage) SelectUserPullRequestsReviews(userID string) ([]dom
 
 	return pullRequests, nil
 }
+
+func (s *PullRequestStorage) CountUserOpenReviews(userIDs []string) (map[string]int64, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.countUserOpenReviewsQuery, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64, len(userIDs))
+	for rows.Next() {
+		var userID string
+		var count int64
+		err = rows.Scan(&userID, &count)
+		if err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (s *PullRequestStorage) SelectHistory(pullRequestID string) ([]domain.AssignmentEvent, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.historyQuery, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []domain.AssignmentEvent
+	for rows.Next() {
+		var event domain.AssignmentEvent
+		err = rows.Scan(
+			&event.ReviewerID,
+			&event.Action,
+			&event.Reason,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
@@ -1,43 +1,6 @@
 package db
 
 const (
-	CreateUsersTable = `
-	CREATE TABLE IF NOT EXISTS users (
-		id TEXT NOT NULL,
-		username TEXT NOT NULL,
-		team_name TEXT NOT NULL,
-		is_active BOOLEAN NOT NULL DEFAULT TRUE,
-		team_deleted BOOLEAN NOT NULL DEFAULT FALSE,
-		PRIMARY KEY (id)
-	)
-	`
-	CreatePullRequestsStatusesTable = `
-	CREATE TABLE IF NOT EXISTS pull_requests_statuses (
-		id SERIAL,
-		status TEXT NOT NULL UNIQUE,
-		PRIMARY KEY (id)
-	)
-	`
-	CreatePullRequestsTable = `
-	CREATE TABLE IF NOT EXISTS pull_requests (
-		id TEXT NOT NULL,
-		name TEXT NOT NULL,
-		author_id TEXT NOT NULL,
-		status_id INT NOT NULL,
-		assigned_reviewers TEXT NOT NULL,
-		created_at TIMESTAMP,
-		merged_at TIMESTAMP,
-		PRIMARY KEY (id),
-		FOREIGN KEY (author_id) REFERENCES users (id),
-		FOREIGN KEY (status_id) REFERENCES pull_requests_statuses (id)
-	)
-	`
-
-	FillPullRequestsStatusesTable = `
-	INSERT INTO pull_requests_statuses (status) VALUES ('open'), ('merged')
-	ON CONFLICT (status) DO NOTHING
-	`
-
 	SelectTeam = `
 	SELECT 
 		team_name,
@@ -50,6 +13,7 @@ const (
 		) as members
 	FROM users 
 	WHERE ($1::text IS NULL OR team_name = $1)
+		AND team_name IS NOT NULL
 		AND team_deleted = FALSE
 	GROUP BY team_name
 	`
@@ -61,13 +25,26 @@ const (
 	INSERT INTO users (id, username, team_name, is_active) VALUES ($1, $2, $3, $4)
 	ON CONFLICT (id) DO NOTHING
 	`
+	UpsertTeamMember = `
+	INSERT INTO users (id, username, team_name, is_active) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (id) DO UPDATE
+	SET
+		username = EXCLUDED.username,
+		team_name = EXCLUDED.team_name,
+		is_active = EXCLUDED.is_active,
+		team_deleted = FALSE,
+		team_deleted_at = NULL
+	`
+	SetUserTeam = `
+	UPDATE users SET team_name = $2, team_deleted = FALSE, team_deleted_at = NULL WHERE id = $1
+	`
 
 	CreatePullRequest = `
 		INSERT INTO
 			pull_requests
-			(id, name, author_id, status_id, assigned_reviewers, created_at, merged_at)
+			(id, name, author_id, status_id, reviewers_count, changed_paths, created_at, merged_at)
 		VALUES
-			($1, $2, $3, (SELECT id FROM pull_requests_statuses WHERE status = 'open' LIMIT 1), $4, NOW(), NULL)
+			($1, $2, $3, (SELECT id FROM pull_requests_statuses WHERE status = $4 LIMIT 1), $5, $6, NOW(), NULL)
 		ON CONFLICT
 			(id) DO NOTHING
 	`
@@ -81,13 +58,64 @@ const (
 			id = $1
 			AND status_id != (SELECT id FROM pull_requests_statuses WHERE status = 'merged' LIMIT 1)
 	`
+	UpdatePullRequestStatus = `
+		UPDATE
+			pull_requests
+		SET
+			status_id = (SELECT id FROM pull_requests_statuses WHERE status = $2 LIMIT 1)
+		WHERE
+			id = $1
+	`
+	// Reviewer changes are recorded in the assignment history with the
+	// reason $3.
+	AssignPullRequestReviewers = `
+	WITH assigned AS (
+		INSERT INTO
+			pull_request_reviewers
+			(pr_id, user_id, assigned_at)
+		SELECT
+			$1, reviewer, NOW()
+		FROM
+			unnest($2::text[]) AS reviewer
+		ON CONFLICT
+			(pr_id, user_id) DO NOTHING
+		RETURNING pr_id, user_id
+	)
+	INSERT INTO
+		pull_request_assignments
+		(pr_id, user_id, action, reason, created_at)
+	SELECT
+		pr_id, user_id, 'assigned', $3, NOW()
+	FROM
+		assigned
+	`
 	ReassignPullRequest = `
-	UPDATE
-		pull_requests
-	SET
-		assigned_reviewers = $2
-	WHERE
-		id = $1
+	WITH unassigned AS (
+		DELETE FROM
+			pull_request_reviewers
+		WHERE
+			pr_id = $1
+			AND NOT (user_id = ANY(COALESCE($2::text[], '{}')))
+		RETURNING pr_id, user_id
+	)
+	INSERT INTO
+		pull_request_assignments
+		(pr_id, user_id, action, reason, created_at)
+	SELECT
+		pr_id, user_id, 'unassigned', $3, NOW()
+	FROM
+		unassigned
+	`
+	SelectPullRequestAssignments = `
+	SELECT
+		user_id,
+		action,
+		reason,
+		created_at
+	FROM
+		pull_request_assignments
+	WHERE pr_id = $1
+	ORDER BY id
 	`
 	UserPullRequestsReviews = `
 	SELECT 
@@ -102,16 +130,80 @@ const (
 			WHERE id = pull_requests.status_id
 			LIMIT 1
 		) as status,
-		assigned_reviewers,
+		COALESCE(
+			(
+				SELECT
+					array_agg(user_id ORDER BY assigned_at, user_id)
+				FROM
+					pull_request_reviewers
+				WHERE pr_id = pull_requests.id
+			),
+			'{}'
+		) as assigned_reviewers,
+		COALESCE(
+			(
+				SELECT
+					json_agg(
+						json_build_object(
+							'reviewer_id', user_id,
+							'state', state,
+							'submitted_at', submitted_at AT TIME ZONE 'UTC'
+						)
+						ORDER BY assigned_at, user_id
+					)
+				FROM
+					pull_request_reviewers
+				WHERE pr_id = pull_requests.id
+			),
+			'[]'
+		) as reviews,
+		reviewers_count,
+		changed_paths,
 		created_at,
 		merged_at
 	FROM
 		pull_requests
 	WHERE
-		assigned_reviewers LIKE '[' || $1 || ',%'
-		OR assigned_reviewers LIKE '%, ' || $1 || ',%'
-		OR assigned_reviewers LIKE '%, ' || $1 || ']'
-		OR assigned_reviewers = '[' || $1 || ']'
+		EXISTS (
+			SELECT
+				1
+			FROM
+				pull_request_reviewers
+			WHERE pr_id = pull_requests.id
+				AND user_id = $1
+		)
+	`
+	CountUserOpenReviews = `
+	SELECT
+		users.id,
+		COUNT(pull_requests.id)
+	FROM
+		unnest($1::text[]) AS users(id)
+		LEFT JOIN pull_request_reviewers ON pull_request_reviewers.user_id = users.id
+		LEFT JOIN pull_requests ON pull_requests.id = pull_request_reviewers.pr_id
+			AND pull_requests.status_id = (SELECT id FROM pull_requests_statuses WHERE status = 'open' LIMIT 1)
+	GROUP BY
+		users.id
+	`
+	SubmitPullRequestReview = `
+	WITH submitted AS (
+		UPDATE
+			pull_request_reviewers
+		SET
+			state = $3,
+			submitted_at = NOW()
+		WHERE
+			pr_id = $1
+			AND user_id = $2
+		RETURNING pr_id, user_id, state, submitted_at
+	)
+	INSERT INTO
+		pull_request_reviews
+		(pr_id, user_id, state, submitted_at)
+	SELECT
+		pr_id, user_id, state, submitted_at
+	FROM
+		submitted
 	`
 	SelectPullRequest = `
 	SELECT
@@ -126,7 +218,35 @@ const (
 			WHERE id = pull_requests.status_id
 			LIMIT 1
 		) as status,
-		assigned_reviewers,
+		COALESCE(
+			(
+				SELECT
+					array_agg(user_id ORDER BY assigned_at, user_id)
+				FROM
+					pull_request_reviewers
+				WHERE pr_id = pull_requests.id
+			),
+			'{}'
+		) as assigned_reviewers,
+		COALESCE(
+			(
+				SELECT
+					json_agg(
+						json_build_object(
+							'reviewer_id', user_id,
+							'state', state,
+							'submitted_at', submitted_at AT TIME ZONE 'UTC'
+						)
+						ORDER BY assigned_at, user_id
+					)
+				FROM
+					pull_request_reviewers
+				WHERE pr_id = pull_requests.id
+			),
+			'[]'
+		) as reviews,
+		reviewers_count,
+		changed_paths,
 		created_at,
 		merged_at
 	FROM
@@ -137,7 +257,7 @@ const (
 	SELECT
 		id as user_id,
 		username,
-		team_name,
+		COALESCE(team_name, '') as team_name,
 		is_active
 	FROM
 		users
@@ -145,6 +265,728 @@ const (
 		AND team_deleted = FALSE
 	`
 	DeleteTeam = `
-	UPDATE users SET team_deleted = TRUE WHERE team_name = $1
+	UPDATE users SET team_deleted = TRUE, team_deleted_at = NOW() WHERE team_name = $1 AND team_deleted = FALSE
+	`
+	SelectDeletedTeams = `
+	SELECT
+		team_name,
+		json_agg(
+			json_build_object(
+				'user_id', id,
+				'username', username,
+				'is_active', is_active
+			)
+		) as members,
+		MAX(team_deleted_at) as deleted_at
+	FROM users
+	WHERE ($1::text IS NULL OR team_name = $1)
+		AND team_name IS NOT NULL
+		AND team_deleted = TRUE
+	GROUP BY team_name
+	ORDER BY team_name
+	`
+	RestoreTeam = `
+	UPDATE users SET team_deleted = FALSE, team_deleted_at = NULL WHERE team_name = $1 AND team_deleted = TRUE
+	`
+	// The purge statements run in this order in one transaction. NOW() is the
+	// transaction start time, so every statement selects the same users.
+	//
+	// PurgeDeletedTeamReviewers unassigns the purged users from pull requests
+	// and records the reason $2 in the assignment history.
+	PurgeDeletedTeamReviewers = `
+	WITH unassigned AS (
+		DELETE FROM
+			pull_request_reviewers
+		WHERE user_id IN (
+			SELECT
+				id
+			FROM
+				users
+			WHERE team_deleted = TRUE
+				AND team_deleted_at <= NOW() - make_interval(secs => $1::float8)
+		)
+		RETURNING pr_id, user_id
+	)
+	INSERT INTO
+		pull_request_assignments
+		(pr_id, user_id, action, reason, created_at)
+	SELECT
+		pr_id, user_id, 'unassigned', $2, NOW()
+	FROM
+		unassigned
+	`
+	PurgeDeletedTeamPullRequests = `
+	DELETE FROM
+		pull_requests
+	WHERE author_id IN (
+		SELECT
+			id
+		FROM
+			users
+		WHERE team_deleted = TRUE
+			AND team_deleted_at <= NOW() - make_interval(secs => $1::float8)
+	)
+	`
+	// PurgeDeletedTeamData deletes the policy, settings and CODEOWNERS of teams
+	// left without members. The tables do not reference each other.
+	PurgeDeletedTeamData = `
+	WITH purged AS (
+		SELECT
+			id,
+			team_name
+		FROM
+			users
+		WHERE team_deleted = TRUE
+			AND team_deleted_at <= NOW() - make_interval(secs => $1::float8)
+	),
+	emptied AS (
+		SELECT DISTINCT
+			team_name
+		FROM
+			purged
+		WHERE NOT EXISTS (
+			SELECT
+				1
+			FROM
+				users
+			WHERE users.team_name = purged.team_name
+				AND users.id NOT IN (SELECT id FROM purged)
+		)
+	),
+	purged_policies AS (
+		DELETE FROM team_merge_policies WHERE team_name IN (SELECT team_name FROM emptied)
+	),
+	purged_codeowners AS (
+		DELETE FROM team_codeowners WHERE team_name IN (SELECT team_name FROM emptied)
+	)
+	DELETE FROM team_settings WHERE team_name IN (SELECT team_name FROM emptied)
+	`
+	PurgeDeletedTeams = `
+	DELETE FROM
+		users
+	WHERE team_deleted = TRUE
+		AND team_deleted_at <= NOW() - make_interval(secs => $1::float8)
+	RETURNING team_name
+	`
+	SelectMergePolicy = `
+	SELECT
+		team_name,
+		min_approvals,
+		block_on_changes_requested,
+		forbid_self_approval
+	FROM
+		team_merge_policies
+	WHERE team_name = $1
+	`
+	UpsertMergePolicy = `
+	INSERT INTO
+		team_merge_policies
+		(team_name, min_approvals, block_on_changes_requested, forbid_self_approval)
+	VALUES
+		($1, $2, $3, $4)
+	ON CONFLICT
+		(team_name) DO UPDATE
+	SET
+		min_approvals = EXCLUDED.min_approvals,
+		block_on_changes_requested = EXCLUDED.block_on_changes_requested,
+		forbid_self_approval = EXCLUDED.forbid_self_approval
+	`
+	SelectTeamSettings = `
+	SELECT
+		team_name,
+		reviewers_required,
+		reviewer_pool,
+		pool_reviewers_required,
+		review_sla_hours,
+		COALESCE(sla_action, ''),
+		team_lead
+	FROM
+		team_settings
+	WHERE team_name = $1
+	`
+	UpsertTeamSettings = `
+	INSERT INTO
+		team_settings
+		(team_name, reviewers_required, reviewer_pool, pool_reviewers_required, review_sla_hours, sla_action, team_lead)
+	VALUES
+		($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+	ON CONFLICT
+		(team_name) DO UPDATE
+	SET
+		reviewers_required = EXCLUDED.reviewers_required,
+		reviewer_pool = EXCLUDED.reviewer_pool,
+		pool_reviewers_required = EXCLUDED.pool_reviewers_required,
+		review_sla_hours = EXCLUDED.review_sla_hours,
+		sla_action = EXCLUDED.sla_action,
+		team_lead = EXCLUDED.team_lead
+	`
+	// Reviews escalated after they were assigned are not stale anymore.
+	SelectStaleReviews = `
+	SELECT
+		pull_request_reviewers.pr_id,
+		pull_request_reviewers.user_id,
+		team_settings.team_name,
+		pull_request_reviewers.assigned_at
+	FROM
+		pull_request_reviewers
+		JOIN pull_requests ON pull_requests.id = pull_request_reviewers.pr_id
+		JOIN users AS authors ON authors.id = pull_requests.author_id
+		JOIN team_settings ON team_settings.team_name = authors.team_name
+	WHERE pull_request_reviewers.state = 'pending'
+		AND pull_requests.status_id = (SELECT id FROM pull_requests_statuses WHERE status = 'open' LIMIT 1)
+		AND authors.team_deleted = FALSE
+		AND team_settings.review_sla_hours > 0
+		AND pull_request_reviewers.assigned_at <= NOW() - make_interval(hours => team_settings.review_sla_hours)
+		AND NOT EXISTS (
+			SELECT
+				1
+			FROM
+				review_escalations
+			WHERE review_escalations.pr_id = pull_request_reviewers.pr_id
+				AND review_escalations.reviewer_id = pull_request_reviewers.user_id
+				AND review_escalations.created_at >= pull_request_reviewers.assigned_at
+		)
+	ORDER BY pull_request_reviewers.assigned_at, pull_request_reviewers.pr_id, pull_request_reviewers.user_id
+	`
+	InsertReviewEscalation = `
+	INSERT INTO
+		review_escalations
+		(pr_id, reviewer_id, action, assignee_id, created_at)
+	VALUES
+		($1, $2, $3, $4, NOW())
+	`
+	// The lock is released when the transaction ends.
+	TryAdvisoryLock = `
+	SELECT pg_try_advisory_xact_lock($1)
+	`
+	CreateSavepoint = `
+	SAVEPOINT unit
+	`
+	RollbackToSavepoint = `
+	ROLLBACK TO SAVEPOINT unit
+	`
+	ReleaseSavepoint = `
+	RELEASE SAVEPOINT unit
+	`
+	SelectCodeowners = `
+	SELECT
+		team_name,
+		content,
+		required,
+		updated_at
+	FROM
+		team_codeowners
+	WHERE team_name = $1
+	`
+	UpsertCodeowners = `
+	INSERT INTO
+		team_codeowners
+		(team_name, content, required, updated_at)
+	VALUES
+		($1, $2, $3, NOW())
+	ON CONFLICT
+		(team_name) DO UPDATE
+	SET
+		content = EXCLUDED.content,
+		required = EXCLUDED.required,
+		updated_at = EXCLUDED.updated_at
+	`
+	SelectReviewerPool = `
+	SELECT
+		reviewer_pools.name,
+		COALESCE(
+			array_agg(reviewer_pool_members.user_id ORDER BY reviewer_pool_members.user_id)
+				FILTER (WHERE reviewer_pool_members.user_id IS NOT NULL),
+			'{}'
+		) as members
+	FROM
+		reviewer_pools
+		LEFT JOIN reviewer_pool_members ON reviewer_pool_members.pool_name = reviewer_pools.name
+	WHERE reviewer_pools.name = $1
+	GROUP BY reviewer_pools.name
+	`
+	UpsertReviewerPool = `
+	INSERT INTO
+		reviewer_pools
+		(name)
+	VALUES
+		($1)
+	ON CONFLICT
+		(name) DO NOTHING
+	`
+	ReplaceReviewerPoolMembers = `
+	WITH removed AS (
+		DELETE FROM reviewer_pool_members
+		WHERE pool_name = $1
+			AND user_id <> ALL($2::text[])
+	)
+	INSERT INTO
+		reviewer_pool_members
+		(pool_name, user_id)
+	SELECT
+		$1,
+		unnest($2::text[])
+	ON CONFLICT
+		(pool_name, user_id) DO NOTHING
+	`
+	InsertMergePolicyOverride = `
+	INSERT INTO
+		merge_policy_overrides
+		(pr_id, reason, unmet_rules, created_at)
+	VALUES
+		($1, $2, $3, NOW())
+	`
+
+	InsertAuditEvent = `
+	INSERT INTO
+		audit_events
+		(action, entity_type, entity_id, actor, details, created_at)
+	VALUES
+		($1, $2, $3, $4, $5, NOW())
+	`
+	SelectAuditEvents = `
+	SELECT
+		id,
+		action,
+		entity_type,
+		entity_id,
+		actor,
+		details,
+		created_at
+	FROM
+		audit_events
+	WHERE ($1::text IS NULL OR entity_type = $1)
+		AND ($2::text IS NULL OR entity_id = $2)
+		AND ($3::text IS NULL OR actor = $3)
+		AND ($4::timestamptz IS NULL OR created_at >= $4)
+		AND ($5::timestamptz IS NULL OR created_at < $5)
+	ORDER BY id DESC
+	LIMIT $6
+	`
+
+	InsertWebhookSubscription = `
+	INSERT INTO
+		webhook_subscriptions
+		(url, events, secret)
+	VALUES
+		($1, $2, $3)
+	RETURNING id, created_at
+	`
+	SelectWebhookSubscription = `
+	SELECT
+		id,
+		url,
+		events,
+		secret,
+		created_at
+	FROM
+		webhook_subscriptions
+	WHERE id = $1
+	`
+	SelectSubscribedWebhooks = `
+	SELECT
+		id,
+		url,
+		events,
+		secret,
+		created_at
+	FROM
+		webhook_subscriptions
+	WHERE $1 = ANY(events)
+	ORDER BY id
+	`
+	DeleteWebhookSubscription = `
+	DELETE FROM
+		webhook_subscriptions
+	WHERE id = $1
+	`
+	InsertWebhookDelivery = `
+	INSERT INTO
+		webhook_deliveries
+		(subscription_id, event_id, event_type, attempt, status_code, error, created_at)
+	VALUES
+		($1, $2, $3, $4, $5, $6, NOW())
+	`
+	SelectWebhookDeliveries = `
+	SELECT
+		id,
+		subscription_id,
+		event_id,
+		event_type,
+		attempt,
+		status_code,
+		error,
+		created_at
+	FROM
+		webhook_deliveries
+	WHERE subscription_id = $1
+	ORDER BY id DESC
+	LIMIT $2
+	`
+	SelectWebhookEventDeliveries = `
+	SELECT
+		id,
+		subscription_id,
+		event_id,
+		event_type,
+		attempt,
+		status_code,
+		error,
+		created_at
+	FROM
+		webhook_deliveries
+	WHERE event_id = $1
+	ORDER BY id
+	`
+
+	InsertOutboxMessage = `
+	INSERT INTO
+		outbox
+		(event_id, event_type, payload, created_at)
+	VALUES
+		($1, $2, $3, NOW())
+	`
+	ClaimOutboxMessages = `
+	UPDATE
+		outbox
+	SET
+		available_at = NOW() + make_interval(secs => $3::float8)
+	WHERE id IN (
+		SELECT
+			id
+		FROM
+			outbox
+		WHERE published_at IS NULL
+			AND attempts < $2
+			AND available_at <= NOW()
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, event_id, event_type, payload, attempts, created_at
+	`
+	MarkOutboxMessagePublished = `
+	UPDATE
+		outbox
+	SET
+		published_at = NOW()
+	WHERE id = $1
+	`
+	MarkOutboxMessageFailed = `
+	UPDATE
+		outbox
+	SET
+		attempts = attempts + 1,
+		last_error = $2,
+		available_at = NOW() + make_interval(secs => $3::float8)
+	WHERE id = $1
+	`
+	DeletePublishedOutboxMessages = `
+	DELETE FROM
+		outbox
+	WHERE published_at <= NOW() - make_interval(secs => $1::float8)
+	`
+
+	UpsertProviderAccount = `
+	INSERT INTO
+		provider_accounts
+		(provider, login, user_id, created_at)
+	VALUES
+		($1, $2, $3, NOW())
+	ON CONFLICT
+		(provider, login) DO UPDATE SET user_id = EXCLUDED.user_id
+	`
+	SelectProviderAccounts = `
+	SELECT
+		provider,
+		login,
+		user_id
+	FROM
+		provider_accounts
+	WHERE provider = $1
+		AND ($2::text IS NULL OR login = $2)
+	ORDER BY login
+	`
+
+	InsertUserAbsence = `
+	INSERT INTO
+		user_absences
+		(user_id, starts_at, ends_at)
+	VALUES
+		($1, $2, $3)
+	RETURNING id
+	`
+	SelectUserAbsences = `
+	SELECT
+		id,
+		user_id,
+		starts_at,
+		ends_at
+	FROM
+		user_absences
+	WHERE user_id = $1
+	ORDER BY starts_at, id
+	`
+	SelectAbsentUserIDs = `
+	SELECT DISTINCT
+		user_id
+	FROM
+		user_absences
+	WHERE starts_at <= NOW()
+		AND ends_at > NOW()
+	`
+
+	// The statistics queries take the filter as $1 from, $2 to, $3 team name
+	// and $4 user ID, each of them may be NULL. Every review metric comes from
+	// the review history in pull_request_reviews, so the metrics agree: a
+	// review is waiting while the reviewer has not submitted one since the
+	// assignment.
+	SelectUserActivity = `
+	WITH ` + statsScope + `,` + reviewLatencyScope + `,
+	authored AS (
+		SELECT
+			author_id,
+			COUNT(*) FILTER (WHERE created_in_window) as prs_created,
+			COUNT(*) FILTER (WHERE status = 'merged' AND merged_in_window) as prs_merged,
+			COUNT(*) FILTER (WHERE status = 'open' AND created_in_window) as prs_open,
+			AVG(merge_time_hours) FILTER (WHERE status = 'merged' AND merged_in_window) as average_merge_time_hours
+		FROM
+			scoped_pull_requests
+		GROUP BY author_id
+	),
+	reviewed AS (
+		SELECT
+			user_id,
+			COUNT(DISTINCT pr_id) as prs_reviewed
+		FROM
+			pull_request_reviews
+		WHERE ($1::timestamptz IS NULL OR submitted_at >= $1)
+			AND ($2::timestamptz IS NULL OR submitted_at < $2)
+		GROUP BY user_id
+	),
+	waiting AS (
+		SELECT
+			pull_request_reviewers.user_id,
+			COUNT(*) as prs_waiting_for_review
+		FROM
+			pull_request_reviewers
+			JOIN scoped_pull_requests ON scoped_pull_requests.id = pull_request_reviewers.pr_id
+		WHERE scoped_pull_requests.status = 'open'
+			AND ($1::timestamptz IS NULL OR pull_request_reviewers.assigned_at >= $1)
+			AND ($2::timestamptz IS NULL OR pull_request_reviewers.assigned_at < $2)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					pull_request_reviews
+				WHERE pull_request_reviews.pr_id = pull_request_reviewers.pr_id
+					AND pull_request_reviews.user_id = pull_request_reviewers.user_id
+					AND pull_request_reviews.submitted_at >= pull_request_reviewers.assigned_at
+			)
+		GROUP BY pull_request_reviewers.user_id
+	),
+	review_latency AS (
+		SELECT
+			user_id,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY hours) as time_to_first_review_p50_hours,
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY hours) as time_to_first_review_p90_hours
+		FROM
+			first_reviews
+		GROUP BY user_id
+	),
+	changes_requested AS (
+		SELECT
+			author_id,
+			AVG(hours) as average_changes_requested_wait_hours
+		FROM
+			changes_requested_waits
+		GROUP BY author_id
+	)
+	SELECT
+		selected_users.id,
+		selected_users.username,
+		COALESCE(selected_users.team_name, ''),
+		selected_users.is_active,
+		selected_users.is_absent,
+		COALESCE(authored.prs_created, 0),
+		COALESCE(reviewed.prs_reviewed, 0),
+		COALESCE(authored.prs_merged, 0),
+		COALESCE(authored.prs_open, 0),
+		COALESCE(waiting.prs_waiting_for_review, 0),
+		COALESCE(authored.average_merge_time_hours, 0)::float8,
+		COALESCE(review_latency.time_to_first_review_p50_hours, 0)::float8,
+		COALESCE(review_latency.time_to_first_review_p90_hours, 0)::float8,
+		COALESCE(changes_requested.average_changes_requested_wait_hours, 0)::float8
+	FROM
+		selected_users
+		LEFT JOIN authored ON authored.author_id = selected_users.id
+		LEFT JOIN reviewed ON reviewed.user_id = selected_users.id
+		LEFT JOIN waiting ON waiting.user_id = selected_users.id
+		LEFT JOIN review_latency ON review_latency.user_id = selected_users.id
+		LEFT JOIN changes_requested ON changes_requested.author_id = selected_users.id
+	ORDER BY selected_users.id
+	`
+	SelectTeamActivity = `
+	WITH ` + statsScope + `,` + reviewLatencyScope + `,
+	members AS (
+		SELECT
+			team_name,
+			COUNT(*) as total_members,
+			COUNT(*) FILTER (WHERE is_active) as active_members,
+			COUNT(*) FILTER (WHERE is_active AND is_absent) as absent_members
+		FROM
+			selected_users
+		WHERE team_name IS NOT NULL
+		GROUP BY team_name
+	),
+	merges AS (
+		SELECT
+			selected_users.team_name,
+			AVG(scoped_pull_requests.merge_time_hours) as average_merge_time_hours
+		FROM
+			scoped_pull_requests
+			JOIN selected_users ON selected_users.id = scoped_pull_requests.author_id
+		WHERE scoped_pull_requests.status = 'merged'
+			AND scoped_pull_requests.merged_in_window
+		GROUP BY selected_users.team_name
+	),
+	review_latency AS (
+		SELECT
+			selected_users.team_name,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY first_reviews.hours) as time_to_first_review_p50_hours,
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY first_reviews.hours) as time_to_first_review_p90_hours
+		FROM
+			first_reviews
+			JOIN selected_users ON selected_users.id = first_reviews.user_id
+		GROUP BY selected_users.team_name
+	),
+	changes_requested AS (
+		SELECT
+			selected_users.team_name,
+			AVG(changes_requested_waits.hours) as average_changes_requested_wait_hours
+		FROM
+			changes_requested_waits
+			JOIN selected_users ON selected_users.id = changes_requested_waits.author_id
+		GROUP BY selected_users.team_name
+	)
+	SELECT
+		members.team_name,
+		members.total_members,
+		members.active_members,
+		members.absent_members,
+		COALESCE(merges.average_merge_time_hours, 0)::float8,
+		COALESCE(review_latency.time_to_first_review_p50_hours, 0)::float8,
+		COALESCE(review_latency.time_to_first_review_p90_hours, 0)::float8,
+		COALESCE(changes_requested.average_changes_requested_wait_hours, 0)::float8
+	FROM
+		members
+		LEFT JOIN merges ON merges.team_name = members.team_name
+		LEFT JOIN review_latency ON review_latency.team_name = members.team_name
+		LEFT JOIN changes_requested ON changes_requested.team_name = members.team_name
+	ORDER BY members.team_name
+	`
+	// Without a team or user filter the totals include pull requests of
+	// deleted teams.
+	SelectPullRequestTotals = `
+	WITH ` + statsScope + `
+	SELECT
+		COUNT(*) FILTER (WHERE created_in_window),
+		COALESCE(AVG(merge_time_hours) FILTER (WHERE status = 'merged' AND merged_in_window), 0)::float8
+	FROM
+		scoped_pull_requests
+	WHERE ($3::text IS NULL AND $4::text IS NULL)
+		OR author_id IN (SELECT id FROM selected_users)
 	`
 )
+
+// statsScope selects the users matching the statistics filter and all pull
+// requests with their status and whether they fall into the time window.
+const statsScope = `
+	selected_users AS (
+		SELECT
+			id,
+			username,
+			team_name,
+			is_active,
+			EXISTS (
+				SELECT
+					1
+				FROM
+					user_absences
+				WHERE user_absences.user_id = users.id
+					AND user_absences.starts_at <= NOW()
+					AND user_absences.ends_at > NOW()
+			) as is_absent
+		FROM
+			users
+		WHERE team_deleted = FALSE
+			AND ($3::text IS NULL OR team_name = $3)
+			AND ($4::text IS NULL OR id = $4)
+	),
+	scoped_pull_requests AS (
+		SELECT
+			pull_requests.id,
+			pull_requests.author_id,
+			pull_requests_statuses.status,
+			EXTRACT(EPOCH FROM (pull_requests.merged_at - pull_requests.created_at)) / 3600 as merge_time_hours,
+			($1::timestamptz IS NULL OR pull_requests.created_at >= $1)
+				AND ($2::timestamptz IS NULL OR pull_requests.created_at < $2) as created_in_window,
+			($1::timestamptz IS NULL OR pull_requests.merged_at >= $1)
+				AND ($2::timestamptz IS NULL OR pull_requests.merged_at < $2) as merged_in_window
+		FROM
+			pull_requests
+			JOIN pull_requests_statuses ON pull_requests_statuses.id = pull_requests.status_id
+	)`
+
+// reviewLatencyScope selects the hours from assignment to the first review of
+// each reviewer and the hours from each requested change to the next review
+// of the same reviewer, both within the time window of the statistics filter.
+// Reviews of reviewers unassigned since count too: the assignment is taken
+// from the assignment history.
+const reviewLatencyScope = `
+	review_timeline AS (
+		SELECT
+			pr_id,
+			user_id,
+			state,
+			submitted_at,
+			ROW_NUMBER() OVER reviewer_reviews as review_number,
+			LEAD(submitted_at) OVER reviewer_reviews as next_submitted_at
+		FROM
+			pull_request_reviews
+		WINDOW reviewer_reviews AS (PARTITION BY pr_id, user_id ORDER BY submitted_at, id)
+	),
+	first_reviews AS (
+		SELECT
+			review_timeline.user_id,
+			(EXTRACT(EPOCH FROM (review_timeline.submitted_at - assignment.created_at)) / 3600)::float8 as hours
+		FROM
+			review_timeline
+			JOIN LATERAL (
+				SELECT
+					created_at
+				FROM
+					pull_request_assignments
+				WHERE pull_request_assignments.pr_id = review_timeline.pr_id
+					AND pull_request_assignments.user_id = review_timeline.user_id
+					AND pull_request_assignments.action = 'assigned'
+					AND pull_request_assignments.created_at <= review_timeline.submitted_at
+				ORDER BY pull_request_assignments.created_at DESC, pull_request_assignments.id DESC
+				LIMIT 1
+			) assignment ON TRUE
+		WHERE review_timeline.review_number = 1
+			AND ($1::timestamptz IS NULL OR review_timeline.submitted_at >= $1)
+			AND ($2::timestamptz IS NULL OR review_timeline.submitted_at < $2)
+	),
+	changes_requested_waits AS (
+		SELECT
+			pull_requests.author_id,
+			(EXTRACT(EPOCH FROM (review_timeline.next_submitted_at - review_timeline.submitted_at)) / 3600)::float8 as hours
+		FROM
+			review_timeline
+			JOIN pull_requests ON pull_requests.id = review_timeline.pr_id
+		WHERE review_timeline.state = 'changes_requested'
+			AND review_timeline.next_submitted_at IS NOT NULL
+			AND ($1::timestamptz IS NULL OR review_timeline.next_submitted_at >= $1)
+			AND ($2::timestamptz IS NULL OR review_timeline.next_submitted_at < $2)
+	)`
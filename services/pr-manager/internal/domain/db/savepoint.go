@@ -0,0 +1,41 @@
+package db
+
+type SavepointStorage struct {
+	Config
+	Transactor
+	createQuery   string
+	rollbackQuery string
+	releaseQuery  string
+}
+
+func NewSavepointStorage(config Config, transactor Transactor) *SavepointStorage {
+	return &SavepointStorage{Config: config, Transactor: transactor}
+}
+
+func (s *SavepointStorage) SetCreateQuery(createQuery string) {
+	s.createQuery = createQuery
+}
+
+func (s *SavepointStorage) SetRollbackQuery(rollbackQuery string) {
+	s.rollbackQuery = rollbackQuery
+}
+
+func (s *SavepointStorage) SetReleaseQuery(releaseQuery string) {
+	s.releaseQuery = releaseQuery
+}
+
+// Savepoint runs fn after a savepoint and rolls back to it if fn fails.
+// Savepoints may nest: Postgres rolls back to the latest one of the same name.
+func (s *SavepointStorage) Savepoint(fn func() error) error {
+	if _, err := s.Transactor.Exec(s.ctx, s.createQuery); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		if _, rollbackErr := s.Transactor.Exec(s.ctx, s.rollbackQuery); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+	_, err := s.Transactor.Exec(s.ctx, s.releaseQuery)
+	return err
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type SettingsStorage struct {
+	Config
+	Transactor
+	selectQuery string
+	upsertQuery string
+}
+
+func NewSettingsStorage(config Config, transactor Transactor) *SettingsStorage {
+	return &SettingsStorage{Config: config, Transactor: transactor}
+}
+
+func (s *SettingsStorage) SetSelectQuery(selectQuery string) {
+	s.selectQuery = selectQuery
+}
+
+func (s *SettingsStorage) SetUpsertQuery(upsertQuery string) {
+	s.upsertQuery = upsertQuery
+}
+
+func (s *SettingsStorage) Select(teamName string) ([]domain.TeamSettings, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectQuery, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settings []domain.TeamSettings
+	for rows.Next() {
+		var teamSettings domain.TeamSettings
+		err = rows.Scan(
+			&teamSettings.TeamName,
+			&teamSettings.ReviewersRequired,
+			&teamSettings.ReviewerPool,
+			&teamSettings.PoolReviewersRequired,
+			&teamSettings.ReviewSLAHours,
+			&teamSettings.SLAAction,
+			&teamSettings.TeamLead,
+		)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, teamSettings)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (s *SettingsStorage) Upsert(settings domain.TeamSettings) error {
+	_, err := s.Transactor.Exec(s.ctx, s.upsertQuery,
+		settings.TeamName,
+		settings.ReviewersRequired,
+		settings.ReviewerPool,
+		settings.PoolReviewersRequired,
+		settings.ReviewSLAHours,
+		settings.SLAAction,
+		settings.TeamLead,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type StatsStorage struct {
+	Config
+	Transactor
+	userActivityQuery      string
+	teamActivityQuery      string
+	pullRequestTotalsQuery string
+}
+
+func NewStatsStorage(config Config, transactor Transactor) *StatsStorage {
+	return &StatsStorage{Config: config, Transactor: transactor}
+}
+
+func (s *StatsStorage) SetUserActivityQuery(userActivityQuery string) {
+	s.userActivityQuery = userActivityQuery
+}
+
+func (s *StatsStorage) SetTeamActivityQuery(teamActivityQuery string) {
+	s.teamActivityQuery = teamActivityQuery
+}
+
+func (s *StatsStorage) SetPullRequestTotalsQuery(pullRequestTotalsQuery string) {
+	s.pullRequestTotalsQuery = pullRequestTotalsQuery
+}
+
+func (s *StatsStorage) SelectUserActivity(filter domain.StatsFilter) ([]domain.UserActivity, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.userActivityQuery, filterArgs(filter)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activity []domain.UserActivity
+	for rows.Next() {
+		var user domain.UserActivity
+		err = rows.Scan(
+			&user.UserID,
+			&user.Username,
+			&user.TeamName,
+			&user.IsActive,
+			&user.IsAbsent,
+			&user.PRsCreated,
+			&user.PRsReviewed,
+			&user.PRsMerged,
+			&user.PRsOpen,
+			&user.PRsWaitingForReview,
+			&user.AverageMergeTimeHours,
+			&user.TimeToFirstReviewP50Hours,
+			&user.TimeToFirstReviewP90Hours,
+			&user.AverageChangesRequestedWaitHours,
+		)
+		if err != nil {
+			return nil, err
+		}
+		activity = append(activity, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+func (s *StatsStorage) SelectTeamActivity(filter domain.StatsFilter) ([]domain.TeamActivity, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.teamActivityQuery, filterArgs(filter)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activity []domain.TeamActivity
+	for rows.Next() {
+		var team domain.TeamActivity
+		err = rows.Scan(
+			&team.TeamName,
+			&team.TotalMembers,
+			&team.ActiveMembers,
+			&team.AbsentMembers,
+			&team.AverageMergeTimeHours,
+			&team.TimeToFirstReviewP50Hours,
+			&team.TimeToFirstReviewP90Hours,
+			&team.AverageChangesRequestedWaitHours,
+		)
+		if err != nil {
+			return nil, err
+		}
+		activity = append(activity, team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+func (s *StatsStorage) SelectPullRequestTotals(filter domain.StatsFilter) (domain.PullRequestTotals, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.pullRequestTotalsQuery, filterArgs(filter)...)
+	if err != nil {
+		return domain.PullRequestTotals{}, err
+	}
+	defer rows.Close()
+
+	var totals domain.PullRequestTotals
+	for rows.Next() {
+		err = rows.Scan(&totals.Total, &totals.AverageMergeTimeHours)
+		if err != nil {
+			return domain.PullRequestTotals{}, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return domain.PullRequestTotals{}, err
+	}
+
+	return totals, nil
+}
+
+func filterArgs(filter domain.StatsFilter) []any {
+	return []any{filter.From, filter.To, filter.TeamName, filter.UserID}
+}
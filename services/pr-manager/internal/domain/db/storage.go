@@ -8,6 +8,19 @@ type Storage struct {
 	UserStorage        storager.UserStorager
 	TeamStorage        storager.TeamStorager
 	PullRequestStorage storager.PullRequestStorager
+	PolicyStorage      storager.MergePolicyStorager
+	StatsStorage       storager.StatsStorager
+	AuditStorage       storager.AuditStorager
+	AbsenceStorage     storager.AbsenceStorager
+	SettingsStorage    storager.TeamSettingsStorager
+	PoolStorage        storager.ReviewerPoolStorager
+	CodeownersStorage  storager.CodeownersStorager
+	EscalationStorage  storager.EscalationStorager
+	LockStorage        storager.Locker
+	SavepointStorage   storager.Savepointer
+	WebhookStorage     storager.WebhookStorager
+	OutboxStorage      storager.OutboxStorager
+	AccountStorage     storager.AccountStorager
 }
 
 func NewStorage(config Config, transactor Transactor) *Storage {
@@ -17,5 +30,18 @@ func NewStorage(config Config, transactor Transactor) *Storage {
 		UserStorage:        NewUserStorage(config, transactor),
 		TeamStorage:        NewTeamStorage(config, transactor),
 		PullRequestStorage: NewPullRequestStorage(config, transactor),
+		PolicyStorage:      NewPolicyStorage(config, transactor),
+		StatsStorage:       NewStatsStorage(config, transactor),
+		AuditStorage:       NewAuditStorage(config, transactor),
+		AbsenceStorage:     NewAbsenceStorage(config, transactor),
+		SettingsStorage:    NewSettingsStorage(config, transactor),
+		PoolStorage:        NewPoolStorage(config, transactor),
+		CodeownersStorage:  NewCodeownersStorage(config, transactor),
+		EscalationStorage:  NewEscalationStorage(config, transactor),
+		LockStorage:        NewLockStorage(config, transactor),
+		SavepointStorage:   NewSavepointStorage(config, transactor),
+		WebhookStorage:     NewWebhookStorage(config, transactor),
+		OutboxStorage:      NewOutboxStorage(config, transactor),
+		AccountStorage:     NewAccountStorage(config, transactor),
 	}
 }
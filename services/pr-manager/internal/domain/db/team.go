@@ -1,16 +1,26 @@
 package db
 
 import (
+	"slices"
+	"time"
+
 	"github.com/zemld/pr-manager/pr-manager/internal/domain"
 )
 
 type TeamStorage struct {
 	Config
 	Transactor
-	selectQuery     string
-	insertQuery     string
-	selectUserQuery string
-	deleteQuery     string
+	selectQuery            string
+	insertQuery            string
+	selectUserQuery        string
+	deleteQuery            string
+	setTeamQuery           string
+	deletedQuery           string
+	restoreQuery           string
+	purgeReviewersQuery    string
+	purgePullRequestsQuery string
+	purgeTeamDataQuery     string
+	purgeQuery             string
 }
 
 func NewTeamStorage(config Config, transactor Transactor) *TeamStorage {
@@ -33,6 +43,34 @@ func (s *TeamStorage) SetDeleteQuery(deleteQuery string) {
 	s.deleteQuery = deleteQuery
 }
 
+func (s *TeamStorage) SetSetTeamQuery(setTeamQuery string) {
+	s.setTeamQuery = setTeamQuery
+}
+
+func (s *TeamStorage) SetSelectDeletedQuery(deletedQuery string) {
+	s.deletedQuery = deletedQuery
+}
+
+func (s *TeamStorage) SetRestoreQuery(restoreQuery string) {
+	s.restoreQuery = restoreQuery
+}
+
+func (s *TeamStorage) SetPurgeReviewersQuery(purgeReviewersQuery string) {
+	s.purgeReviewersQuery = purgeReviewersQuery
+}
+
+func (s *TeamStorage) SetPurgePullRequestsQuery(purgePullRequestsQuery string) {
+	s.purgePullRequestsQuery = purgePullRequestsQuery
+}
+
+func (s *TeamStorage) SetPurgeTeamDataQuery(purgeTeamDataQuery string) {
+	s.purgeTeamDataQuery = purgeTeamDataQuery
+}
+
+func (s *TeamStorage) SetPurgeQuery(purgeQuery string) {
+	s.purgeQuery = purgeQuery
+}
+
 func (s *TeamStorage) Select(teamName *string) ([]domain.Team, error) {
 	var filter any
 	if teamName != nil {
@@ -65,25 +103,19 @@ func (s *TeamStorage) Select(teamName *string) ([]domain.Team, error) {
 }
 
 func (s *TeamStorage) Insert(team domain.Team) error {
-	userInserter := NewUserStorage(s.Config, s.Transactor)
-	userInserter.SetInsertQuery(s.insertQuery)
-	userInserter.SetSelectQuery(SelectUser)
+	userSelector := NewUserStorage(s.Config, s.Transactor)
+	userSelector.SetSelectQuery(s.selectUserQuery)
 
 	for _, member := range team.Members {
 		userID := member.UserID
-		existingUsers, err := userInserter.Select(&userID)
-		if err == nil && len(existingUsers) > 0 && existingUsers[0].UserID != "" {
+		existingUsers, err := userSelector.Select(&userID)
+		if err == nil && len(existingUsers) > 0 && existingUsers[0].TeamName != "" {
 			return domain.ErrUserInAnotherTeam
 		}
 	}
 
 	for _, member := range team.Members {
-		err := userInserter.Insert(domain.User{
-			UserID:   member.UserID,
-			Username: member.Username,
-			TeamName: team.TeamName,
-			IsActive: member.IsActive,
-		})
+		err := s.AddMember(team.TeamName, member)
 		if err != nil {
 			return err
 		}
@@ -91,6 +123,25 @@ func (s *TeamStorage) Insert(team domain.Team) error {
 	return nil
 }
 
+// AddMember inserts the user into the team. Users without a team or from a
+// deleted team are moved into it.
+func (s *TeamStorage) AddMember(teamName string, member domain.TeamMember) error {
+	_, err := s.Transactor.Exec(s.ctx, s.insertQuery, member.UserID, member.Username, teamName, member.IsActive)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetMemberTeam moves the user to teamName, or out of any team if it is nil.
+func (s *TeamStorage) SetMemberTeam(userID string, teamName *string) error {
+	_, err := s.Transactor.Exec(s.ctx, s.setTeamQuery, userID, teamName)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (s *TeamStorage) Delete(teamName string) error {
 	_, err := s.Transactor.Exec(s.ctx, s.deleteQuery, teamName)
 	if err != nil {
@@ -98,3 +149,77 @@ func (s *TeamStorage) Delete(teamName string) error {
 	}
 	return nil
 }
+
+func (s *TeamStorage) SelectDeleted(teamName *string) ([]domain.DeletedTeam, error) {
+	var filter any
+	if teamName != nil {
+		filter = *teamName
+	}
+
+	rows, err := s.Transactor.Query(s.ctx, s.deletedQuery, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []domain.DeletedTeam
+	for rows.Next() {
+		var team domain.DeletedTeam
+		err = rows.Scan(&team.TeamName, &team.Members, &team.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+func (s *TeamStorage) Restore(teamName string) error {
+	_, err := s.Transactor.Exec(s.ctx, s.restoreQuery, teamName)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *TeamStorage) Purge(retention time.Duration) ([]string, error) {
+	_, err := s.Transactor.Exec(s.ctx, s.purgeReviewersQuery, retention.Seconds(), domain.AssignmentTeamPurged)
+	if err != nil {
+		return nil, err
+	}
+	for _, query := range []string{s.purgePullRequestsQuery, s.purgeTeamDataQuery} {
+		if _, err = s.Transactor.Exec(s.ctx, query, retention.Seconds()); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := s.Transactor.Query(s.ctx, s.purgeQuery, retention.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teamNames []string
+	for rows.Next() {
+		var teamName string
+		err = rows.Scan(&teamName)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(teamNames, teamName) {
+			teamNames = append(teamNames, teamName)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(teamNames)
+	return teamNames, nil
+}
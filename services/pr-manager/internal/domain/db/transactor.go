@@ -11,6 +11,7 @@ import (
 type Transactor struct {
 	pool       *pgxpool.Pool
 	isReadOnly bool
+	snapshot   bool
 	conn       *pgxpool.Conn
 	tx         pgx.Tx
 	ctx        context.Context
@@ -20,8 +21,15 @@ func NewTransactor(pool *pgxpool.Pool, ctx context.Context, isReadOnly bool) *Tr
 	return &Transactor{pool: pool, ctx: ctx, isReadOnly: isReadOnly}
 }
 
+// NewSnapshotTransactor returns a read-only transactor whose queries all see
+// the same snapshot of the database. Plain read-only transactors run each
+// query on its own.
+func NewSnapshotTransactor(pool *pgxpool.Pool, ctx context.Context) *Transactor {
+	return &Transactor{pool: pool, ctx: ctx, isReadOnly: true, snapshot: true}
+}
+
 func (t *Transactor) Begin(ctx context.Context) error {
-	if t.isReadOnly {
+	if t.isReadOnly && !t.snapshot {
 		return nil
 	}
 
@@ -30,7 +38,11 @@ func (t *Transactor) Begin(ctx context.Context) error {
 		return err
 	}
 	t.conn = conn
-	tx, err := conn.Begin(ctx)
+	options := pgx.TxOptions{}
+	if t.snapshot {
+		options = pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}
+	}
+	tx, err := conn.BeginTx(ctx, options)
 	if err != nil {
 		conn.Release()
 		return err
@@ -40,13 +52,6 @@ func (t *Transactor) Begin(ctx context.Context) error {
 }
 
 func (t *Transactor) Commit() error {
-	if t.isReadOnly {
-		if t.conn != nil {
-			t.conn.Release()
-			t.conn = nil
-		}
-		return nil
-	}
 	if t.tx != nil {
 		if err := t.tx.Commit(t.ctx); err != nil {
 			return err
@@ -61,13 +66,6 @@ func (t *Transactor) Commit() error {
 }
 
 func (t *Transactor) Rollback() error {
-	if t.isReadOnly {
-		if t.conn != nil {
-			t.conn.Release()
-			t.conn = nil
-		}
-		return nil
-	}
 	if t.tx != nil {
 		if err := t.tx.Rollback(t.ctx); err != nil {
 			return err
@@ -82,14 +80,14 @@ func (t *Transactor) Rollback() error {
 }
 
 func (t *Transactor) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
-	if t.isReadOnly {
+	if t.tx == nil {
 		return t.pool.Query(ctx, sql, args...)
 	}
 	return t.tx.Query(ctx, sql, args...)
 }
 
 func (t *Transactor) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
-	if t.isReadOnly {
+	if t.tx == nil {
 		return t.pool.Exec(ctx, sql, args...)
 	}
 	return t.tx.Exec(ctx, sql, args...)
@@ -0,0 +1,193 @@
+package db
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type WebhookStorage struct {
+	Config
+	Transactor
+	insertQuery           string
+	selectQuery           string
+	selectSubscribedQuery string
+	deleteQuery           string
+	insertDeliveryQuery   string
+	selectDeliveriesQuery string
+	selectEventQuery      string
+}
+
+func NewWebhookStorage(config Config, transactor Transactor) *WebhookStorage {
+	return &WebhookStorage{Config: config, Transactor: transactor}
+}
+
+func (s *WebhookStorage) SetInsertQuery(insertQuery string) {
+	s.insertQuery = insertQuery
+}
+
+func (s *WebhookStorage) SetSelectQuery(selectQuery string) {
+	s.selectQuery = selectQuery
+}
+
+func (s *WebhookStorage) SetSelectSubscribedQuery(selectSubscribedQuery string) {
+	s.selectSubscribedQuery = selectSubscribedQuery
+}
+
+func (s *WebhookStorage) SetDeleteQuery(deleteQuery string) {
+	s.deleteQuery = deleteQuery
+}
+
+func (s *WebhookStorage) SetInsertDeliveryQuery(insertDeliveryQuery string) {
+	s.insertDeliveryQuery = insertDeliveryQuery
+}
+
+func (s *WebhookStorage) SetSelectDeliveriesQuery(selectDeliveriesQuery string) {
+	s.selectDeliveriesQuery = selectDeliveriesQuery
+}
+
+func (s *WebhookStorage) SetSelectEventDeliveriesQuery(selectEventQuery string) {
+	s.selectEventQuery = selectEventQuery
+}
+
+func (s *WebhookStorage) Insert(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	events := make([]string, 0, len(subscription.Events))
+	for _, event := range subscription.Events {
+		events = append(events, string(event))
+	}
+
+	rows, err := s.Transactor.Query(s.ctx, s.insertQuery,
+		subscription.URL,
+		events,
+		subscription.Secret,
+	)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = rows.Scan(&subscription.ID, &subscription.CreatedAt); err != nil {
+			return domain.WebhookSubscription{}, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	return subscription, nil
+}
+
+func (s *WebhookStorage) Select(id int64) ([]domain.WebhookSubscription, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookSubscriptions(rows)
+}
+
+func (s *WebhookStorage) SelectSubscribed(eventType domain.WebhookEventType) ([]domain.WebhookSubscription, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectSubscribedQuery, string(eventType))
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookSubscriptions(rows)
+}
+
+func (s *WebhookStorage) Delete(id int64) error {
+	_, err := s.Transactor.Exec(s.ctx, s.deleteQuery, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookStorage) InsertDelivery(delivery domain.WebhookDelivery) error {
+	_, err := s.Transactor.Exec(s.ctx, s.insertDeliveryQuery,
+		delivery.SubscriptionID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Error,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookStorage) SelectDeliveries(subscriptionID int64, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectDeliveriesQuery, subscriptionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookDeliveries(rows)
+}
+
+func (s *WebhookStorage) SelectEventDeliveries(eventID string) ([]domain.WebhookDelivery, error) {
+	rows, err := s.Transactor.Query(s.ctx, s.selectEventQuery, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return scanWebhookDeliveries(rows)
+}
+
+func scanWebhookDeliveries(rows pgx.Rows) ([]domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Attempt,
+			&delivery.StatusCode,
+			&delivery.Error,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhookSubscriptions(rows pgx.Rows) ([]domain.WebhookSubscription, error) {
+	defer rows.Close()
+
+	var subscriptions []domain.WebhookSubscription
+	for rows.Next() {
+		var subscription domain.WebhookSubscription
+		var events []string
+		err := rows.Scan(
+			&subscription.ID,
+			&subscription.URL,
+			&events,
+			&subscription.Secret,
+			&subscription.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			subscription.Events = append(subscription.Events, domain.WebhookEventType(event))
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
@@ -6,6 +6,8 @@ var (
 	ErrNotFound            = errors.New("resource not found")
 	ErrPRExists            = errors.New("PR id already exists")
 	ErrPRMerged            = errors.New("pull request is already merged")
+	ErrPRNotOpen           = errors.New("pull request is not open")
+	ErrInvalidTransition   = errors.New("pull request status transition is not allowed")
 	ErrTeamExists          = errors.New("team_name already exists")
 	ErrUserNotFound        = errors.New("user not found")
 	ErrTeamNotFound        = errors.New("team not found")
@@ -13,7 +15,24 @@ var (
 	ErrNotAssigned         = errors.New("reviewer is not assigned to this PR")
 	ErrNoCandidate         = errors.New("no active replacement candidate in team")
 	ErrUserInAnotherTeam   = errors.New("user with id is in another team")
+	ErrNotTeamMember       = errors.New("user is not a member of this team")
+	ErrInvalidRetention    = errors.New("retention period must not be negative")
 	ErrNoPossibleAssigners = errors.New("no possible assigners")
+	ErrUnknownStrategy     = errors.New("unknown reviewer selection strategy")
+	ErrInvalidReviewState  = errors.New("review state must be APPROVED, CHANGES_REQUESTED or COMMENTED")
+	ErrPolicyNotSatisfied  = errors.New("merge policy not satisfied")
+	ErrInvalidPolicy       = errors.New("min_approvals must not be negative")
+	ErrInvalidAbsence      = errors.New("absence must end after it starts")
+	ErrInvalidReviewers    = errors.New("reviewers count must be between 1 and 10")
+	ErrPoolNotFound        = errors.New("reviewer pool not found")
+	ErrInvalidPool         = errors.New("reviewer pool name must not be empty")
+	ErrInvalidCodeowners   = errors.New("invalid CODEOWNERS file")
+	ErrInvalidSLA          = errors.New("invalid review SLA")
+	ErrWebhookNotFound     = errors.New("webhook subscription not found")
+	ErrInvalidWebhook      = errors.New("webhook needs an http(s) URL, known event types and a secret")
+	ErrInvalidAccount      = errors.New("account needs a known provider, a login and a user_id")
+	ErrAccountNotLinked    = errors.New("provider login is not linked to a user")
+	ErrInvalidSignature    = errors.New("invalid webhook signature")
 )
 
 type ErrorWithCode struct {
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// SLAAction is what is done about a review pending for longer than the review
+// SLA of the author's team.
+type SLAAction string
+
+const (
+	// SLAReassign hands the review over as a manual reassignment would. If
+	// nobody can take it over, the review is escalated instead.
+	SLAReassign SLAAction = "reassign"
+	// SLAEscalate assigns the team lead as an additional reviewer.
+	SLAEscalate SLAAction = "escalate"
+)
+
+// StaleReview is a review of an open pull request that is still pending after
+// the review SLA of the author's team.
+type StaleReview struct {
+	PullRequestID string
+	ReviewerID    string
+	TeamName      string
+	AssignedAt    time.Time
+}
+
+// Escalation records what was done about a stale review. AssigneeID is the
+// reviewer who took the review over or the team lead it was escalated to, nil
+// if there was nobody.
+type Escalation struct {
+	ID            int64      `json:"id"`
+	PullRequestID string     `json:"pull_request_id"`
+	ReviewerID    string     `json:"reviewer_id"`
+	Action        SLAAction  `json:"action"`
+	AssigneeID    *string    `json:"assignee_id"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+}
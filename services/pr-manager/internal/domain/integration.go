@@ -0,0 +1,46 @@
+package domain
+
+// Provider is a code hosting service pull requests are ingested from.
+type Provider string
+
+const (
+	ProviderGitHub Provider = "github"
+	ProviderGitLab Provider = "gitlab"
+)
+
+func ValidProvider(provider Provider) bool {
+	return provider == ProviderGitHub || provider == ProviderGitLab
+}
+
+// ProviderAccount links a login on a provider to a user. Logins are case
+// insensitive and kept in lower case.
+type ProviderAccount struct {
+	Provider Provider `json:"provider"`
+	Login    string   `json:"login"`
+	UserID   string   `json:"user_id"`
+}
+
+// ProviderAction is what happened to a pull request on the provider.
+type ProviderAction string
+
+const (
+	ProviderOpened         ProviderAction = "opened"
+	ProviderReadyForReview ProviderAction = "ready_for_review"
+	ProviderClosed         ProviderAction = "closed"
+	ProviderMerged         ProviderAction = "merged"
+	ProviderReopened       ProviderAction = "reopened"
+)
+
+// ProviderPullRequestEvent is a pull request event received from a provider.
+// PullRequestID identifies the pull request across providers and
+// repositories. AuthorLogin is the provider login of the author, SenderLogin
+// of whoever caused the event.
+type ProviderPullRequestEvent struct {
+	Provider      Provider
+	Action        ProviderAction
+	PullRequestID string
+	Title         string
+	Draft         bool
+	AuthorLogin   string
+	SenderLogin   string
+}
@@ -0,0 +1,54 @@
+package manager
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/storager"
+)
+
+// AbsenceManager schedules absences of users. Absent users stay active but
+// are skipped when reviewers are picked; their current reviews are kept.
+type AbsenceManager struct {
+	UserStorage    storager.UserStorager
+	AbsenceStorage storager.AbsenceStorager
+}
+
+func NewAbsenceManager(userStorage storager.UserStorager, absenceStorage storager.AbsenceStorager) *AbsenceManager {
+	return &AbsenceManager{UserStorage: userStorage, AbsenceStorage: absenceStorage}
+}
+
+func (m *AbsenceManager) AddAbsence(absence domain.Absence) (domain.Absence, error) {
+	if !absence.EndsAt.After(absence.StartsAt) {
+		return domain.Absence{}, domain.ErrInvalidAbsence
+	}
+	if err := m.requireUser(absence.UserID); err != nil {
+		return domain.Absence{}, err
+	}
+	return m.AbsenceStorage.Insert(absence)
+}
+
+// GetAbsences returns all absences of the user, including past ones, ordered
+// by start.
+func (m *AbsenceManager) GetAbsences(userID string) ([]domain.Absence, error) {
+	if err := m.requireUser(userID); err != nil {
+		return nil, err
+	}
+	absences, err := m.AbsenceStorage.Select(userID)
+	if err != nil {
+		return nil, err
+	}
+	if absences == nil {
+		absences = []domain.Absence{}
+	}
+	return absences, nil
+}
+
+func (m *AbsenceManager) requireUser(userID string) error {
+	users, err := m.UserStorage.Select(&userID)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+func TestAbsenceManager_AddAbsence(t *testing.T) {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		absence domain.Absence
+		wantErr error
+	}{
+		{
+			name:    "valid absence",
+			absence: domain.Absence{UserID: "user2", StartsAt: start, EndsAt: start.Add(14 * 24 * time.Hour)},
+		},
+		{
+			name:    "ends before it starts",
+			absence: domain.Absence{UserID: "user2", StartsAt: start, EndsAt: start.Add(-time.Hour)},
+			wantErr: domain.ErrInvalidAbsence,
+		},
+		{
+			name:    "empty period",
+			absence: domain.Absence{UserID: "user2", StartsAt: start, EndsAt: start},
+			wantErr: domain.ErrInvalidAbsence,
+		},
+		{
+			name:    "unknown user",
+			absence: domain.Absence{UserID: "missing", StartsAt: start, EndsAt: start.Add(time.Hour)},
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			setupMembershipStorage(storage)
+
+			manager := NewAbsenceManager(storage.UserStorage, storage.AbsenceStorage)
+			result, err := manager.AddAbsence(tt.absence)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.ID == 0 || result.UserID != tt.absence.UserID {
+				t.Errorf("expected stored absence, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestPullRequestManager_SkipsAbsentReviewers(t *testing.T) {
+	storage := createMockStorage()
+	setupMembershipStorage(storage)
+	absences := storage.AbsenceStorage.(*mockAbsenceStorage)
+	absences.Insert(domain.Absence{UserID: "user2", StartsAt: absences.now.Add(-time.Hour), EndsAt: absences.now.Add(time.Hour)})
+	absences.Insert(domain.Absence{UserID: "user3", StartsAt: absences.now.Add(time.Hour), EndsAt: absences.now.Add(2 * time.Hour)})
+
+	pr, err := NewPullRequestManager(storage).CreatePullRequest(createTestPR("pr1", "Test PR", "user1", domain.Open, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(pr.AssignedReviewers, []string{"user3"}) {
+		t.Errorf("expected only user3 to be assigned while user2 is absent, got %v", pr.AssignedReviewers)
+	}
+}
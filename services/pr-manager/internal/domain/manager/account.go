@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"strings"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/storager"
+)
+
+type AccountManager struct {
+	AccountStorage storager.AccountStorager
+	UserStorage    storager.UserStorager
+}
+
+func NewAccountManager(accountStorage storager.AccountStorager, userStorage storager.UserStorager) *AccountManager {
+	return &AccountManager{AccountStorage: accountStorage, UserStorage: userStorage}
+}
+
+// Link links the provider login to an existing user, replacing the user it
+// was linked to before.
+func (m *AccountManager) Link(account domain.ProviderAccount) (domain.ProviderAccount, error) {
+	account.Login = normalizeLogin(account.Login)
+	if !domain.ValidProvider(account.Provider) || account.Login == "" || account.UserID == "" {
+		return domain.ProviderAccount{}, domain.ErrInvalidAccount
+	}
+	users, err := m.UserStorage.Select(&account.UserID)
+	if err != nil {
+		return domain.ProviderAccount{}, err
+	}
+	if len(users) == 0 {
+		return domain.ProviderAccount{}, domain.ErrUserNotFound
+	}
+
+	if err := m.AccountStorage.Upsert(account); err != nil {
+		return domain.ProviderAccount{}, err
+	}
+	return account, nil
+}
+
+func (m *AccountManager) GetAccounts(provider domain.Provider) ([]domain.ProviderAccount, error) {
+	if !domain.ValidProvider(provider) {
+		return nil, domain.ErrInvalidAccount
+	}
+	return m.AccountStorage.Select(provider, nil)
+}
+
+// Resolve returns the ID of the user the provider login is linked to.
+func (m *AccountManager) Resolve(provider domain.Provider, login string) (string, error) {
+	login = normalizeLogin(login)
+	accounts, err := m.AccountStorage.Select(provider, &login)
+	if err != nil {
+		return "", err
+	}
+	if len(accounts) == 0 {
+		return "", domain.ErrAccountNotLinked
+	}
+	return accounts[0].UserID, nil
+}
+
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
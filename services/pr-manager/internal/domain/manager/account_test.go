@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+func TestAccountManager_Link(t *testing.T) {
+	tests := []struct {
+		name    string
+		account domain.ProviderAccount
+		want    domain.ProviderAccount
+		wantErr error
+	}{
+		{
+			name:    "login is normalized",
+			account: domain.ProviderAccount{Provider: domain.ProviderGitHub, Login: " Octocat ", UserID: "u1"},
+			want:    domain.ProviderAccount{Provider: domain.ProviderGitHub, Login: "octocat", UserID: "u1"},
+		},
+		{
+			name:    "unknown provider",
+			account: domain.ProviderAccount{Provider: "bitbucket", Login: "octocat", UserID: "u1"},
+			wantErr: domain.ErrInvalidAccount,
+		},
+		{
+			name:    "no login",
+			account: domain.ProviderAccount{Provider: domain.ProviderGitLab, Login: " ", UserID: "u1"},
+			wantErr: domain.ErrInvalidAccount,
+		},
+		{
+			name:    "unknown user",
+			account: domain.ProviderAccount{Provider: domain.ProviderGitLab, Login: "octocat", UserID: "u9"},
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			storage.UserStorage.(*mockUserStorage).users["u1"] = domain.User{UserID: "u1", IsActive: true}
+			accountManager := NewAccountManager(storage.AccountStorage, storage.UserStorage)
+
+			got, err := accountManager.Link(tt.account)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAccountManager_Resolve(t *testing.T) {
+	storage := createMockStorage()
+	storage.UserStorage.(*mockUserStorage).users["u1"] = domain.User{UserID: "u1", IsActive: true}
+	storage.UserStorage.(*mockUserStorage).users["u2"] = domain.User{UserID: "u2", IsActive: true}
+	accountManager := NewAccountManager(storage.AccountStorage, storage.UserStorage)
+
+	for _, account := range []domain.ProviderAccount{
+		{Provider: domain.ProviderGitHub, Login: "octocat", UserID: "u1"},
+		{Provider: domain.ProviderGitHub, Login: "octocat", UserID: "u2"},
+		{Provider: domain.ProviderGitLab, Login: "octocat", UserID: "u1"},
+	} {
+		if _, err := accountManager.Link(account); err != nil {
+			t.Fatalf("failed to link %+v: %v", account, err)
+		}
+	}
+
+	userID, err := accountManager.Resolve(domain.ProviderGitHub, "OctoCat")
+	if err != nil || userID != "u2" {
+		t.Errorf("expected the latest link to u2, got %q, %v", userID, err)
+	}
+	userID, err = accountManager.Resolve(domain.ProviderGitLab, "octocat")
+	if err != nil || userID != "u1" {
+		t.Errorf("expected u1 on gitlab, got %q, %v", userID, err)
+	}
+	if _, err := accountManager.Resolve(domain.ProviderGitHub, "hubot"); !errors.Is(err, domain.ErrAccountNotLinked) {
+		t.Errorf("expected ErrAccountNotLinked, got %v", err)
+	}
+}
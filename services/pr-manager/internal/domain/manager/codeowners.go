@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/codeowners"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/storager"
+)
+
+type CodeownersManager struct {
+	TeamStorage       storager.TeamStorager
+	CodeownersStorage storager.CodeownersStorager
+}
+
+func NewCodeownersManager(teamStorage storager.TeamStorager, codeownersStorage storager.CodeownersStorager) *CodeownersManager {
+	return &CodeownersManager{TeamStorage: teamStorage, CodeownersStorage: codeownersStorage}
+}
+
+// SetCodeowners replaces the ownership file of the team. The file must parse.
+func (m *CodeownersManager) SetCodeowners(file domain.Codeowners) (domain.Codeowners, error) {
+	if _, err := codeowners.Parse(file.Content); err != nil {
+		return domain.Codeowners{}, fmt.Errorf("%w: %v", domain.ErrInvalidCodeowners, err)
+	}
+	if err := ensureTeamExists(m.TeamStorage, file.TeamName); err != nil {
+		return domain.Codeowners{}, err
+	}
+
+	err := m.CodeownersStorage.Upsert(file)
+	if err != nil {
+		return domain.Codeowners{}, err
+	}
+	return m.GetCodeowners(file.TeamName)
+}
+
+// GetCodeowners returns the ownership file of the team, or an empty one if it
+// was never uploaded.
+func (m *CodeownersManager) GetCodeowners(teamName string) (domain.Codeowners, error) {
+	if err := ensureTeamExists(m.TeamStorage, teamName); err != nil {
+		return domain.Codeowners{}, err
+	}
+
+	files, err := m.CodeownersStorage.Select(teamName)
+	if err != nil {
+		return domain.Codeowners{}, err
+	}
+	if len(files) == 0 {
+		return domain.Codeowners{TeamName: teamName}, nil
+	}
+	return files[0], nil
+}
+
+// ownership lists the owners of the paths a pull request changes. If required
+// is set, only owners may review it.
+type ownership struct {
+	owners   []string
+	required bool
+}
+
+// pathOwners finds the owners of the changed paths of the pull request in the
+// ownership file of teamName, the author's team.
+func (m *PullRequestManager) pathOwners(teamName string, pullRequest domain.PullRequest) (ownership, error) {
+	if len(pullRequest.ChangedPaths) == 0 || teamName == "" {
+		return ownership{}, nil
+	}
+	files, err := m.Storage.CodeownersStorage.Select(teamName)
+	if err != nil {
+		return ownership{}, err
+	}
+	if len(files) == 0 {
+		return ownership{}, nil
+	}
+	rules, err := codeowners.Parse(files[0].Content)
+	if err != nil {
+		return ownership{}, err
+	}
+	return ownership{owners: rules.Owners(pullRequest.ChangedPaths), required: files[0].Required}, nil
+}
+
+// authorPathOwners is pathOwners for the team the author is currently in.
+func (m *PullRequestManager) authorPathOwners(pullRequest domain.PullRequest) (ownership, error) {
+	if len(pullRequest.ChangedPaths) == 0 {
+		return ownership{}, nil
+	}
+	users, err := m.Storage.UserStorage.Select(&pullRequest.AuthorID)
+	if err != nil {
+		return ownership{}, err
+	}
+	if len(users) == 0 {
+		return ownership{}, nil
+	}
+	return m.pathOwners(users[0].TeamName, pullRequest)
+}
+
+// pickReviewers selects up to count reviewers out of candidates with the
+// selector of key, taking owners of the changed paths first. Unless owners are
+// required, the remaining places are filled from the other candidates. If the
+// paths have no owners, any candidate can be picked.
+func (m *PullRequestManager) pickReviewers(key string, candidates []string, count int, owned ownership) ([]string, error) {
+	if count <= 0 {
+		return []string{}, nil
+	}
+	selector := m.Selection.ForTeam(key)
+	if len(owned.owners) == 0 {
+		return selector.SelectReviewers(key, candidates, count)
+	}
+
+	owners := slices.DeleteFunc(slices.Clone(candidates), func(userID string) bool {
+		return !slices.Contains(owned.owners, userID)
+	})
+	picked, err := selector.SelectReviewers(key, owners, count)
+	if err != nil {
+		return nil, err
+	}
+	if owned.required || len(picked) == count {
+		return picked, nil
+	}
+	rest, err := selector.SelectReviewers(key, m.filterReviewers(candidates, picked...), count-len(picked))
+	if err != nil {
+		return nil, err
+	}
+	return append(picked, rest...), nil
+}
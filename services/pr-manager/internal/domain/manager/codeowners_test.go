@@ -0,0 +1,142 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+const testCodeowners = `
+*       @user2
+/api/   @user3 @user4
+/db/    @user5
+`
+
+func TestCodeownersManager_SetCodeowners(t *testing.T) {
+	storage := createMockStorage()
+	setupLargeTeamStorage(storage)
+	codeownersManager := NewCodeownersManager(storage.TeamStorage, storage.CodeownersStorage)
+
+	file, err := codeownersManager.GetCodeowners("backend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if file.Content != "" || file.Required {
+		t.Errorf("expected empty file by default, got %+v", file)
+	}
+
+	_, err = codeownersManager.SetCodeowners(domain.Codeowners{TeamName: "backend", Content: "!*.go @user2"})
+	if !errors.Is(err, domain.ErrInvalidCodeowners) {
+		t.Errorf("expected ErrInvalidCodeowners, got %v", err)
+	}
+	_, err = codeownersManager.SetCodeowners(domain.Codeowners{TeamName: "unknown", Content: testCodeowners})
+	if !errors.Is(err, domain.ErrTeamNotFound) {
+		t.Errorf("expected ErrTeamNotFound, got %v", err)
+	}
+
+	file, err = codeownersManager.SetCodeowners(domain.Codeowners{TeamName: "backend", Content: testCodeowners, Required: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if file.Content != testCodeowners || !file.Required {
+		t.Errorf("expected stored file, got %+v", file)
+	}
+}
+
+func TestPullRequestManager_CreatePullRequestPrefersOwners(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		required bool
+		validate func(*testing.T, []string)
+	}{
+		{
+			name:  "owners fill all places",
+			paths: []string{"api/handlers/team.go"},
+			validate: func(t *testing.T, reviewers []string) {
+				slices.Sort(reviewers)
+				if !slices.Equal(reviewers, []string{"user3", "user4"}) {
+					t.Errorf("expected api owners, got %v", reviewers)
+				}
+			},
+		},
+		{
+			name:  "preferred owner and another member",
+			paths: []string{"db/queries.go"},
+			validate: func(t *testing.T, reviewers []string) {
+				if len(reviewers) != 2 || reviewers[0] != "user5" {
+					t.Errorf("expected user5 first and another reviewer, got %v", reviewers)
+				}
+			},
+		},
+		{
+			name:     "required owners only",
+			paths:    []string{"db/queries.go"},
+			required: true,
+			validate: func(t *testing.T, reviewers []string) {
+				if !slices.Equal(reviewers, []string{"user5"}) {
+					t.Errorf("expected only user5, got %v", reviewers)
+				}
+			},
+		},
+		{
+			name:     "owners of several paths",
+			paths:    []string{"db/queries.go", "README.md"},
+			required: true,
+			validate: func(t *testing.T, reviewers []string) {
+				slices.Sort(reviewers)
+				if !slices.Equal(reviewers, []string{"user2", "user5"}) {
+					t.Errorf("expected user2 and user5, got %v", reviewers)
+				}
+			},
+		},
+		{
+			name:     "no paths",
+			required: true,
+			validate: func(t *testing.T, reviewers []string) {
+				if len(reviewers) != 2 {
+					t.Errorf("expected 2 reviewers, got %v", reviewers)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 10 {
+				storage := createMockStorage()
+				setupLargeTeamStorage(storage)
+				storage.CodeownersStorage.Upsert(domain.Codeowners{TeamName: "backend", Content: testCodeowners, Required: tt.required})
+				m := NewPullRequestManager(storage)
+
+				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, nil)
+				pr.ChangedPaths = tt.paths
+				result, err := m.CreatePullRequest(pr)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				tt.validate(t, result.AssignedReviewers)
+			}
+		})
+	}
+}
+
+func TestPullRequestManager_ReassignRequiredOwner(t *testing.T) {
+	storage := createMockStorage()
+	setupLargeTeamStorage(storage)
+	storage.CodeownersStorage.Upsert(domain.Codeowners{TeamName: "backend", Content: testCodeowners, Required: true})
+	pr := createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user3", "user4"})
+	pr.ChangedPaths = []string{"api/dto.go"}
+	storage.PullRequestStorage.Create(pr)
+	m := NewPullRequestManager(storage)
+
+	result, replacedBy, err := m.ReassignPullRequest("pr1", "user3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replacedBy != "" || !slices.Equal(result.AssignedReviewers, []string{"user4"}) {
+		t.Errorf("expected no replacement outside of owners, got %q and %v", replacedBy, result.AssignedReviewers)
+	}
+}
@@ -0,0 +1,66 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+// EscalateStaleReview acts on the stale review as the review SLA of the
+// author's team says and records what was done. A review that cannot be
+// reassigned because nobody can take it over is escalated instead.
+func (m *PullRequestManager) EscalateStaleReview(review domain.StaleReview) (domain.Escalation, error) {
+	settings, err := m.teamSettings(review.TeamName)
+	if err != nil {
+		return domain.Escalation{}, err
+	}
+
+	escalation := domain.Escalation{
+		PullRequestID: review.PullRequestID,
+		ReviewerID:    review.ReviewerID,
+		Action:        domain.SLAEscalate,
+	}
+	if settings.SLAAction == domain.SLAReassign {
+		_, newReviewer, err := m.reassign(review.PullRequestID, review.ReviewerID, domain.AssignmentEscalated)
+		if err != nil && !errors.Is(err, domain.ErrNoCandidate) {
+			return domain.Escalation{}, err
+		}
+		if err == nil {
+			escalation.Action = domain.SLAReassign
+			if newReviewer != "" {
+				escalation.AssigneeID = &newReviewer
+			}
+		}
+	}
+	if escalation.Action == domain.SLAEscalate && settings.TeamLead != nil {
+		if err := m.assignTeamLead(review.PullRequestID, *settings.TeamLead); err != nil {
+			return domain.Escalation{}, err
+		}
+		escalation.AssigneeID = settings.TeamLead
+	}
+
+	if err := m.Storage.EscalationStorage.Insert(escalation); err != nil {
+		return domain.Escalation{}, err
+	}
+	return escalation, nil
+}
+
+// assignTeamLead adds the team lead to the reviewers of the pull request
+// unless the lead authored it or already reviews it.
+func (m *PullRequestManager) assignTeamLead(pullRequestID string, leadID string) error {
+	prs, err := m.Storage.PullRequestStorage.Select(&pullRequestID)
+	if err != nil {
+		return err
+	}
+	if len(prs) == 0 {
+		return domain.ErrNotFound
+	}
+	pullRequest := prs[0]
+	if pullRequest.AuthorID == leadID || slices.Contains(pullRequest.AssignedReviewers, leadID) {
+		return nil
+	}
+
+	pullRequest.AssignedReviewers = append(pullRequest.AssignedReviewers, leadID)
+	return m.Storage.PullRequestStorage.Reassign(pullRequest, domain.AssignmentEscalated)
+}
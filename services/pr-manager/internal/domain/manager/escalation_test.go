@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+)
+
+func TestPullRequestManager_EscalateStaleReview(t *testing.T) {
+	lead := "user5"
+	author := "user1"
+
+	tests := []struct {
+		name          string
+		setup         func(*db.Storage)
+		settings      domain.TeamSettings
+		reviewers     []string
+		wantAction    domain.SLAAction
+		wantReviewers func(*testing.T, []string)
+		wantAssignee  func(*testing.T, *string)
+		wantReasons   []domain.AssignmentReason
+	}{
+		{
+			name:       "reassign to another team member",
+			setup:      setupLargeTeamStorage,
+			settings:   domain.TeamSettings{ReviewSLAHours: 24, SLAAction: domain.SLAReassign, TeamLead: &lead},
+			reviewers:  []string{"user2", "user3"},
+			wantAction: domain.SLAReassign,
+			wantReviewers: func(t *testing.T, reviewers []string) {
+				if len(reviewers) != 2 || slices.Contains(reviewers, "user2") || !slices.Contains(reviewers, "user3") {
+					t.Errorf("expected user2 replaced, got %v", reviewers)
+				}
+			},
+			wantAssignee: func(t *testing.T, assignee *string) {
+				if assignee == nil || *assignee == "user2" || *assignee == "user3" {
+					t.Errorf("expected a new reviewer as assignee, got %v", assignee)
+				}
+			},
+			wantReasons: []domain.AssignmentReason{domain.AssignmentEscalated},
+		},
+		{
+			name: "reassign without candidate escalates",
+			setup: func(storage *db.Storage) {
+				members := []domain.TeamMember{
+					{UserID: "user1", Username: "author", IsActive: true},
+					{UserID: "user2", Username: "reviewer", IsActive: true},
+				}
+				storage.TeamStorage.Insert(createTestTeam("backend", members))
+				for _, member := range members {
+					storage.UserStorage.Insert(createTestUser(member.UserID, member.Username, "backend", member.IsActive))
+				}
+				storage.TeamStorage.Insert(createTestTeam("leads", []domain.TeamMember{{UserID: lead, Username: "lead", IsActive: true}}))
+				storage.UserStorage.Insert(createTestUser(lead, "lead", "leads", true))
+			},
+			settings:   domain.TeamSettings{ReviewSLAHours: 24, SLAAction: domain.SLAReassign, TeamLead: &lead},
+			reviewers:  []string{"user2"},
+			wantAction: domain.SLAEscalate,
+			wantReviewers: func(t *testing.T, reviewers []string) {
+				if !slices.Equal(reviewers, []string{"user2", lead}) {
+					t.Errorf("expected lead added, got %v", reviewers)
+				}
+			},
+			wantAssignee: func(t *testing.T, assignee *string) {
+				if assignee == nil || *assignee != lead {
+					t.Errorf("expected lead as assignee, got %v", assignee)
+				}
+			},
+			wantReasons: []domain.AssignmentReason{domain.AssignmentEscalated},
+		},
+		{
+			name:       "escalate to team lead",
+			setup:      setupLargeTeamStorage,
+			settings:   domain.TeamSettings{ReviewSLAHours: 24, SLAAction: domain.SLAEscalate, TeamLead: &lead},
+			reviewers:  []string{"user2", "user3"},
+			wantAction: domain.SLAEscalate,
+			wantReviewers: func(t *testing.T, reviewers []string) {
+				if !slices.Equal(reviewers, []string{"user2", "user3", lead}) {
+					t.Errorf("expected lead added, got %v", reviewers)
+				}
+			},
+			wantAssignee: func(t *testing.T, assignee *string) {
+				if assignee == nil || *assignee != lead {
+					t.Errorf("expected lead as assignee, got %v", assignee)
+				}
+			},
+			wantReasons: []domain.AssignmentReason{domain.AssignmentEscalated},
+		},
+		{
+			name:       "team lead authored the pull request",
+			setup:      setupLargeTeamStorage,
+			settings:   domain.TeamSettings{ReviewSLAHours: 24, SLAAction: domain.SLAEscalate, TeamLead: &author},
+			reviewers:  []string{"user2", "user3"},
+			wantAction: domain.SLAEscalate,
+			wantReviewers: func(t *testing.T, reviewers []string) {
+				if !slices.Equal(reviewers, []string{"user2", "user3"}) {
+					t.Errorf("expected reviewers unchanged, got %v", reviewers)
+				}
+			},
+			wantAssignee: func(t *testing.T, assignee *string) {
+				if assignee == nil || *assignee != author {
+					t.Errorf("expected lead as assignee, got %v", assignee)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			tt.setup(storage)
+			tt.settings.TeamName = "backend"
+			tt.settings.ReviewersRequired = len(tt.reviewers)
+			storage.SettingsStorage.Upsert(tt.settings)
+			storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", author, domain.Open, tt.reviewers))
+			m := NewPullRequestManager(storage)
+
+			escalation, err := m.EscalateStaleReview(domain.StaleReview{PullRequestID: "pr1", ReviewerID: "user2", TeamName: "backend"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if escalation.Action != tt.wantAction || escalation.PullRequestID != "pr1" || escalation.ReviewerID != "user2" {
+				t.Errorf("unexpected escalation %+v", escalation)
+			}
+			tt.wantAssignee(t, escalation.AssigneeID)
+
+			pullRequestID := "pr1"
+			pr, err := m.GetPullRequest(&pullRequestID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.wantReviewers(t, pr.AssignedReviewers)
+
+			if reasons := storage.PullRequestStorage.(*mockPullRequestStorage).reasons["pr1"]; !slices.Equal(reasons, tt.wantReasons) {
+				t.Errorf("expected reasons %v, got %v", tt.wantReasons, reasons)
+			}
+			if escalations := storage.EscalationStorage.(*mockEscalationStorage).escalations; len(escalations) != 1 || escalations[0].Action != tt.wantAction {
+				t.Errorf("expected the escalation recorded, got %+v", escalations)
+			}
+		})
+	}
+}
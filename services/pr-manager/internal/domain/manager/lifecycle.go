@@ -0,0 +1,103 @@
+package manager
+
+import (
+	"slices"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+// transitions lists the statuses a pull request can move to from each status.
+// Merged is final.
+var transitions = map[domain.PullRequestStatus][]domain.PullRequestStatus{
+	domain.Draft:  {domain.Open, domain.Closed},
+	domain.Open:   {domain.Merged, domain.Closed},
+	domain.Closed: {domain.Open},
+}
+
+func canTransition(from domain.PullRequestStatus, to domain.PullRequestStatus) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+func requireOpen(pullRequest domain.PullRequest) error {
+	switch pullRequest.Status {
+	case domain.Open:
+		return nil
+	case domain.Merged:
+		return domain.ErrPRMerged
+	default:
+		return domain.ErrPRNotOpen
+	}
+}
+
+// MarkReady moves a draft to open and assigns reviewers.
+func (m *PullRequestManager) MarkReady(pullRequestID string) (domain.PullRequest, error) {
+	return m.transition(pullRequestID, domain.Draft, domain.Open)
+}
+
+// ClosePullRequest abandons a draft or open pull request and releases its reviewers.
+func (m *PullRequestManager) ClosePullRequest(pullRequestID string) (domain.PullRequest, error) {
+	return m.transition(pullRequestID, "", domain.Closed)
+}
+
+// ReopenPullRequest moves a closed pull request back to open and assigns new reviewers.
+func (m *PullRequestManager) ReopenPullRequest(pullRequestID string) (domain.PullRequest, error) {
+	return m.transition(pullRequestID, domain.Closed, domain.Open)
+}
+
+// transition moves the pull request to status to. If from is set, the pull
+// request must currently be in it. Repeating a transition is a no-op.
+func (m *PullRequestManager) transition(pullRequestID string, from domain.PullRequestStatus, to domain.PullRequestStatus) (domain.PullRequest, error) {
+	pullRequest, err := m.GetPullRequest(&pullRequestID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if pullRequest.Status == to {
+		return pullRequest, nil
+	}
+	if pullRequest.Status == domain.Merged {
+		return domain.PullRequest{}, domain.ErrPRMerged
+	}
+	if (from != "" && pullRequest.Status != from) || !canTransition(pullRequest.Status, to) {
+		return domain.PullRequest{}, domain.ErrInvalidTransition
+	}
+
+	reason := domain.AssignmentInitial
+	switch {
+	case to == domain.Closed:
+		reason = domain.AssignmentClosed
+	case pullRequest.Status == domain.Closed:
+		reason = domain.AssignmentReopened
+	}
+
+	pullRequest.Status = to
+	pullRequest.AssignedReviewers = nil
+	if to == domain.Open {
+		authorTeam, err := m.getReviewerTeam(pullRequest.AuthorID)
+		if err != nil {
+			return domain.PullRequest{}, err
+		}
+		pullRequest.AssignedReviewers, err = m.selectReviewers(authorTeam, pullRequest)
+		if err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+
+	err = m.Storage.PullRequestStorage.UpdateStatus(pullRequestID, to)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	err = m.Storage.PullRequestStorage.Reassign(pullRequest, reason)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	return m.GetPullRequest(&pullRequestID)
+}
+
+func (m *PullRequestManager) selectReviewers(authorTeam domain.Team, pullRequest domain.PullRequest) ([]string, error) {
+	newReviewers, err := m.missingReviewers(authorTeam, pullRequest)
+	if err != nil {
+		return nil, err
+	}
+	return append(slices.Clone(pullRequest.AssignedReviewers), newReviewers...), nil
+}
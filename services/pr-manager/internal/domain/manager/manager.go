@@ -29,7 +29,7 @@ type PullRequestCreator interface {
 }
 
 type PullRequestMerger interface {
-	MergePullRequest(pullRequest domain.PullRequest) (domain.PullRequest, error)
+	MergePullRequest(pullRequest domain.PullRequest, mergedBy *string) (domain.PullRequest, error)
 }
 
 type PullRequestReassigner interface {
@@ -0,0 +1,249 @@
+package manager
+
+import (
+	"slices"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+// MembershipManager changes team membership of existing teams. Reviews the
+// user leaves behind are handed over within the old team.
+type MembershipManager struct {
+	PullRequests *PullRequestManager
+}
+
+func NewMembershipManager(pullRequests *PullRequestManager) *MembershipManager {
+	return &MembershipManager{PullRequests: pullRequests}
+}
+
+// AddMember adds a new or teamless user to the team. Users of another team
+// have to be moved with MoveMember.
+func (m *MembershipManager) AddMember(teamName string, member domain.TeamMember) (domain.Team, error) {
+	if _, err := m.getTeam(teamName); err != nil {
+		return domain.Team{}, err
+	}
+
+	user, err := m.findUser(member.UserID)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	if user.TeamName != "" {
+		return domain.Team{}, domain.ErrUserInAnotherTeam
+	}
+
+	err = m.PullRequests.Storage.TeamStorage.AddMember(teamName, member)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	return m.getTeam(teamName)
+}
+
+// RemoveMember takes the user out of the team, leaving them without one.
+func (m *MembershipManager) RemoveMember(teamName string, userID string) (domain.Team, []domain.Reassignment, error) {
+	team, err := m.getTeam(teamName)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	if !slices.ContainsFunc(team.Members, func(member domain.TeamMember) bool {
+		return member.UserID == userID
+	}) {
+		return domain.Team{}, nil, domain.ErrNotTeamMember
+	}
+
+	reassignments, err := m.PullRequests.ReleaseReviewer(userID, team, domain.AssignmentMemberLeft)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	err = m.PullRequests.Storage.TeamStorage.SetMemberTeam(userID, nil)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	team.Members = slices.DeleteFunc(team.Members, func(member domain.TeamMember) bool {
+		return member.UserID == userID
+	})
+	return team, reassignments, nil
+}
+
+// MoveMember moves the user into another team. Moving a teamless user is the
+// same as adding them.
+func (m *MembershipManager) MoveMember(userID string, teamName string) (domain.Team, []domain.Reassignment, error) {
+	if _, err := m.getTeam(teamName); err != nil {
+		return domain.Team{}, nil, err
+	}
+
+	user, err := m.findUser(userID)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	if user.UserID == "" {
+		return domain.Team{}, nil, domain.ErrUserNotFound
+	}
+
+	var reassignments []domain.Reassignment
+	if user.TeamName != teamName {
+		if user.TeamName != "" {
+			oldTeam, err := m.getTeam(user.TeamName)
+			if err != nil {
+				return domain.Team{}, nil, err
+			}
+			reassignments, err = m.PullRequests.ReleaseReviewer(userID, oldTeam, domain.AssignmentMemberLeft)
+			if err != nil {
+				return domain.Team{}, nil, err
+			}
+		}
+		err = m.PullRequests.Storage.TeamStorage.SetMemberTeam(userID, &teamName)
+		if err != nil {
+			return domain.Team{}, nil, err
+		}
+	}
+
+	team, err := m.getTeam(teamName)
+	if err != nil {
+		return domain.Team{}, nil, err
+	}
+	return team, reassignments, nil
+}
+
+func (m *MembershipManager) getTeam(teamName string) (domain.Team, error) {
+	teams, err := m.PullRequests.Storage.TeamStorage.Select(&teamName)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	if len(teams) == 0 {
+		return domain.Team{}, domain.ErrTeamNotFound
+	}
+	return teams[0], nil
+}
+
+// findUser returns the user, or a zero user if there is no such user or their
+// team was deleted.
+func (m *MembershipManager) findUser(userID string) (domain.User, error) {
+	users, err := m.PullRequests.Storage.UserStorage.Select(&userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if len(users) == 0 {
+		return domain.User{}, nil
+	}
+	return users[0], nil
+}
+
+// ReleaseReviewer hands the reviewer's open reviews over to other active
+// members of team, or of the reviewer pool the reviewer was drawn from.
+// Reviews nobody can take over are dropped. The changes are
+// recorded in the assignment history with the reason.
+func (m *PullRequestManager) ReleaseReviewer(reviewerID string, team domain.Team, reason domain.AssignmentReason) ([]domain.Reassignment, error) {
+	prs, err := m.Storage.PullRequestStorage.SelectUserPullRequestsReviews(reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	teamUserIDs, err := m.getActiveUserIDsFromTeam(team.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	reassignments := make([]domain.Reassignment, 0, len(prs))
+	for _, pr := range prs {
+		if pr.Status != domain.Open || !slices.Contains(pr.AssignedReviewers, reviewerID) {
+			continue
+		}
+
+		selectionKey, activeUserIDs, fromPool, err := m.poolReplacementCandidates(pr, reviewerID)
+		if err != nil {
+			return nil, err
+		}
+		if !fromPool {
+			selectionKey, activeUserIDs = team.TeamName, teamUserIDs
+		}
+
+		otherReviewers := slices.DeleteFunc(slices.Clone(pr.AssignedReviewers), func(reviewer string) bool {
+			return reviewer == reviewerID
+		})
+		owned, err := m.authorPathOwners(pr)
+		if err != nil {
+			return nil, err
+		}
+		excluded := append([]string{reviewerID, pr.AuthorID}, otherReviewers...)
+		candidates := m.filterReviewers(activeUserIDs, excluded...)
+		newReviewers, err := m.pickReviewers(selectionKey, candidates, 1, owned)
+		if err != nil {
+			return nil, err
+		}
+
+		reassignment := domain.Reassignment{PullRequestID: pr.ID}
+		pr.AssignedReviewers = otherReviewers
+		if len(newReviewers) > 0 {
+			reassignment.ReplacedBy = newReviewers[0]
+			pr.AssignedReviewers = append(pr.AssignedReviewers, newReviewers[0])
+		}
+
+		err = m.Storage.PullRequestStorage.Reassign(pr, reason)
+		if err != nil {
+			return nil, err
+		}
+		reassignments = append(reassignments, reassignment)
+	}
+	return reassignments, nil
+}
+
+// ReleaseDeactivatedReviewer hands the open reviews of a deactivated user over
+// to their teammates the same way ReleaseReviewer does. Teamless users keep
+// their reviews, since there is nobody to hand them to.
+func (m *PullRequestManager) ReleaseDeactivatedReviewer(user domain.User) ([]domain.Reassignment, error) {
+	if user.TeamName == "" {
+		return []domain.Reassignment{}, nil
+	}
+	teams, err := m.Storage.TeamStorage.Select(&user.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return nil, domain.ErrTeamNotFound
+	}
+	return m.ReleaseReviewer(user.UserID, teams[0], domain.AssignmentUserDeactivated)
+}
+
+// FillReviewers assigns reviewers from team and its reviewer pool to open
+// pull requests of its members that have fewer reviewers than required, e.g.
+// after the team was restored. It returns the pull requests that got new
+// reviewers.
+func (m *PullRequestManager) FillReviewers(team domain.Team) ([]domain.PullRequest, error) {
+	prs, err := m.Storage.PullRequestStorage.Select(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make([]domain.PullRequest, 0)
+	for _, pr := range prs {
+		if pr.Status != domain.Open {
+			continue
+		}
+		if !slices.ContainsFunc(team.Members, func(member domain.TeamMember) bool {
+			return member.UserID == pr.AuthorID
+		}) {
+			continue
+		}
+
+		newReviewers, err := m.missingReviewers(team, pr)
+		if err != nil {
+			return nil, err
+		}
+		if len(newReviewers) == 0 {
+			continue
+		}
+
+		pr.AssignedReviewers = append(slices.Clone(pr.AssignedReviewers), newReviewers...)
+		err = m.Storage.PullRequestStorage.Reassign(pr, domain.AssignmentTeamRestored)
+		if err != nil {
+			return nil, err
+		}
+		pr, err = m.GetPullRequest(&pr.ID)
+		if err != nil {
+			return nil, err
+		}
+		updated = append(updated, pr)
+	}
+	return updated, nil
+}
@@ -0,0 +1,320 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+)
+
+func setupMembershipStorage(storage *db.Storage) {
+	backend := []domain.TeamMember{
+		{UserID: "user1", Username: "author", IsActive: true},
+		{UserID: "user2", Username: "reviewer1", IsActive: true},
+		{UserID: "user3", Username: "reviewer2", IsActive: true},
+	}
+	storage.TeamStorage.Insert(createTestTeam("backend", backend))
+	storage.TeamStorage.Insert(createTestTeam("frontend", []domain.TeamMember{
+		{UserID: "user5", Username: "frontender", IsActive: true},
+	}))
+	for _, member := range backend {
+		storage.UserStorage.Insert(createTestUser(member.UserID, member.Username, "backend", member.IsActive))
+	}
+	storage.UserStorage.Insert(createTestUser("user5", "frontender", "frontend", true))
+}
+
+func memberIDs(team domain.Team) []string {
+	ids := make([]string, 0, len(team.Members))
+	for _, member := range team.Members {
+		ids = append(ids, member.UserID)
+	}
+	return ids
+}
+
+func TestMembershipManager_AddMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		teamName string
+		member   domain.TeamMember
+		wantErr  error
+	}{
+		{
+			name:     "new user",
+			teamName: "backend",
+			member:   domain.TeamMember{UserID: "user9", Username: "newcomer", IsActive: true},
+		},
+		{
+			name:     "user of another team",
+			teamName: "backend",
+			member:   domain.TeamMember{UserID: "user5", Username: "frontender", IsActive: true},
+			wantErr:  domain.ErrUserInAnotherTeam,
+		},
+		{
+			name:     "team not found",
+			teamName: "missing",
+			member:   domain.TeamMember{UserID: "user9", Username: "newcomer", IsActive: true},
+			wantErr:  domain.ErrTeamNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			setupMembershipStorage(storage)
+
+			manager := NewMembershipManager(NewPullRequestManager(storage))
+			team, err := manager.AddMember(tt.teamName, tt.member)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Contains(memberIDs(team), tt.member.UserID) {
+				t.Errorf("expected %s in team, got %v", tt.member.UserID, memberIDs(team))
+			}
+		})
+	}
+}
+
+func TestMembershipManager_RemoveMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*db.Storage)
+		teamName string
+		userID   string
+		wantErr  error
+		validate func(*testing.T, domain.Team, []domain.Reassignment, *db.Storage)
+	}{
+		{
+			name: "review is handed over within the team",
+			setup: func(storage *db.Storage) {
+				storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2"}))
+			},
+			teamName: "backend",
+			userID:   "user2",
+			validate: func(t *testing.T, team domain.Team, reassignments []domain.Reassignment, storage *db.Storage) {
+				if slices.Contains(memberIDs(team), "user2") {
+					t.Errorf("expected user2 to leave the team, got %v", memberIDs(team))
+				}
+				if len(reassignments) != 1 || reassignments[0].ReplacedBy != "user3" {
+					t.Errorf("expected pr1 to go to user3, got %+v", reassignments)
+				}
+				prs, _ := storage.PullRequestStorage.Select(nil)
+				if !slices.Equal(prs[0].AssignedReviewers, []string{"user3"}) {
+					t.Errorf("expected reviewers [user3], got %v", prs[0].AssignedReviewers)
+				}
+			},
+		},
+		{
+			name: "review is dropped without candidates",
+			setup: func(storage *db.Storage) {
+				storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user3"}))
+			},
+			teamName: "backend",
+			userID:   "user2",
+			validate: func(t *testing.T, team domain.Team, reassignments []domain.Reassignment, storage *db.Storage) {
+				if len(reassignments) != 1 || reassignments[0].ReplacedBy != "" {
+					t.Errorf("expected review to be dropped, got %+v", reassignments)
+				}
+				prs, _ := storage.PullRequestStorage.Select(nil)
+				if !slices.Equal(prs[0].AssignedReviewers, []string{"user3"}) {
+					t.Errorf("expected reviewers [user3], got %v", prs[0].AssignedReviewers)
+				}
+			},
+		},
+		{
+			name: "merged pull requests are left alone",
+			setup: func(storage *db.Storage) {
+				storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Merged, []string{"user2"}))
+			},
+			teamName: "backend",
+			userID:   "user2",
+			validate: func(t *testing.T, team domain.Team, reassignments []domain.Reassignment, storage *db.Storage) {
+				if len(reassignments) != 0 {
+					t.Errorf("expected no reassignments, got %+v", reassignments)
+				}
+				prs, _ := storage.PullRequestStorage.Select(nil)
+				if !slices.Equal(prs[0].AssignedReviewers, []string{"user2"}) {
+					t.Errorf("expected reviewers [user2], got %v", prs[0].AssignedReviewers)
+				}
+			},
+		},
+		{
+			name:     "user is not a member",
+			setup:    func(storage *db.Storage) {},
+			teamName: "backend",
+			userID:   "user5",
+			wantErr:  domain.ErrNotTeamMember,
+		},
+		{
+			name:     "team not found",
+			setup:    func(storage *db.Storage) {},
+			teamName: "missing",
+			userID:   "user2",
+			wantErr:  domain.ErrTeamNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			setupMembershipStorage(storage)
+			tt.setup(storage)
+
+			manager := NewMembershipManager(NewPullRequestManager(storage))
+			team, reassignments, err := manager.RemoveMember(tt.teamName, tt.userID)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.validate != nil {
+				tt.validate(t, team, reassignments, storage)
+			}
+		})
+	}
+}
+
+func TestMembershipManager_MoveMember(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		teamName string
+		wantErr  error
+		validate func(*testing.T, domain.Team, []domain.Reassignment, *db.Storage)
+	}{
+		{
+			name:     "move hands reviews over to the old team",
+			userID:   "user2",
+			teamName: "frontend",
+			validate: func(t *testing.T, team domain.Team, reassignments []domain.Reassignment, storage *db.Storage) {
+				if !slices.Equal(memberIDs(team), []string{"user5", "user2"}) {
+					t.Errorf("expected frontend to be [user5 user2], got %v", memberIDs(team))
+				}
+				if len(reassignments) != 1 || reassignments[0].ReplacedBy != "user3" {
+					t.Errorf("expected pr1 to go to user3, got %+v", reassignments)
+				}
+			},
+		},
+		{
+			name:     "move into the same team is a no-op",
+			userID:   "user2",
+			teamName: "backend",
+			validate: func(t *testing.T, team domain.Team, reassignments []domain.Reassignment, storage *db.Storage) {
+				if len(reassignments) != 0 {
+					t.Errorf("expected no reassignments, got %+v", reassignments)
+				}
+				prs, _ := storage.PullRequestStorage.Select(nil)
+				if !slices.Equal(prs[0].AssignedReviewers, []string{"user2"}) {
+					t.Errorf("expected reviewers [user2], got %v", prs[0].AssignedReviewers)
+				}
+			},
+		},
+		{
+			name:     "user not found",
+			userID:   "missing",
+			teamName: "frontend",
+			wantErr:  domain.ErrUserNotFound,
+		},
+		{
+			name:     "team not found",
+			userID:   "user2",
+			teamName: "missing",
+			wantErr:  domain.ErrTeamNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			setupMembershipStorage(storage)
+			storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2"}))
+
+			manager := NewMembershipManager(NewPullRequestManager(storage))
+			team, reassignments, err := manager.MoveMember(tt.userID, tt.teamName)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.validate != nil {
+				tt.validate(t, team, reassignments, storage)
+			}
+		})
+	}
+}
+
+func TestPullRequestManager_FillReviewers(t *testing.T) {
+	storage := createMockStorage()
+	setupMembershipStorage(storage)
+	storage.PullRequestStorage.Create(createTestPR("pr1", "Lost reviewers", "user1", domain.Open, []string{}))
+	storage.PullRequestStorage.Create(createTestPR("pr2", "Lost one reviewer", "user1", domain.Open, []string{"user2"}))
+	storage.PullRequestStorage.Create(createTestPR("pr3", "Merged", "user1", domain.Merged, []string{}))
+	storage.PullRequestStorage.Create(createTestPR("pr4", "Other team", "user5", domain.Open, []string{}))
+
+	teamName := "backend"
+	teams, _ := storage.TeamStorage.Select(&teamName)
+	updated, err := NewPullRequestManager(storage).FillReviewers(teams[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reviewers := make(map[string][]string)
+	for _, pr := range updated {
+		reviewers[pr.ID] = pr.AssignedReviewers
+		slices.Sort(reviewers[pr.ID])
+	}
+	if len(reviewers) != 2 {
+		t.Fatalf("expected pr1 and pr2 to be updated, got %v", reviewers)
+	}
+	if !slices.Equal(reviewers["pr1"], []string{"user2", "user3"}) {
+		t.Errorf("expected pr1 reviewers [user2 user3], got %v", reviewers["pr1"])
+	}
+	if !slices.Equal(reviewers["pr2"], []string{"user2", "user3"}) {
+		t.Errorf("expected pr2 reviewers [user2 user3], got %v", reviewers["pr2"])
+	}
+}
+
+func TestPullRequestManager_ReleaseDeactivatedReviewer(t *testing.T) {
+	storage := createMockStorage()
+	setupMembershipStorage(storage)
+	storage.PullRequestStorage.Create(createTestPR("pr1", "Open", "user1", domain.Open, []string{"user2"}))
+	storage.PullRequestStorage.Create(createTestPR("pr2", "Merged", "user1", domain.Merged, []string{"user2"}))
+
+	reassignments, err := NewPullRequestManager(storage).ReleaseDeactivatedReviewer(createTestUser("user2", "reviewer1", "backend", false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(reassignments, []domain.Reassignment{{PullRequestID: "pr1", ReplacedBy: "user3"}}) {
+		t.Errorf("expected pr1 to go to user3, got %+v", reassignments)
+	}
+	prStorage := storage.PullRequestStorage.(*mockPullRequestStorage)
+	if reasons := prStorage.reasons["pr1"]; !slices.Equal(reasons, []domain.AssignmentReason{domain.AssignmentUserDeactivated}) {
+		t.Errorf("expected reassignment as user_deactivated, got %v", reasons)
+	}
+	if _, ok := prStorage.reasons["pr2"]; ok {
+		t.Error("expected merged pull request to be left alone")
+	}
+
+	reassignments, err = NewPullRequestManager(storage).ReleaseDeactivatedReviewer(domain.User{UserID: "user9", IsActive: false})
+	if err != nil || len(reassignments) != 0 {
+		t.Errorf("expected nothing to hand over for a teamless user, got %+v, %v", reassignments, err)
+	}
+}
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"errors"
+	"slices"
 	"strings"
 	"time"
 
@@ -54,12 +55,14 @@ func (m *mockUserStorage) Insert(user domain.User) error {
 
 // mockTeamStorage is a mock implementation of storager.TeamStorager
 type mockTeamStorage struct {
-	teams map[string]domain.Team
+	teams   map[string]domain.Team
+	deleted map[string]domain.DeletedTeam
 }
 
 func newMockTeamStorage() *mockTeamStorage {
 	return &mockTeamStorage{
-		teams: make(map[string]domain.Team),
+		teams:   make(map[string]domain.Team),
+		deleted: make(map[string]domain.DeletedTeam),
 	}
 }
 
@@ -89,18 +92,86 @@ func (m *mockTeamStorage) Delete(teamName string) error {
 	if _, ok := m.teams[teamName]; !ok {
 		return errNotFound
 	}
+	deletedAt := time.Now()
+	m.deleted[teamName] = domain.DeletedTeam{Team: m.teams[teamName], DeletedAt: &deletedAt}
 	delete(m.teams, teamName)
 	return nil
 }
 
+func (m *mockTeamStorage) SelectDeleted(teamName *string) ([]domain.DeletedTeam, error) {
+	teams := make([]domain.DeletedTeam, 0, len(m.deleted))
+	for name, team := range m.deleted {
+		if teamName == nil || name == *teamName {
+			teams = append(teams, team)
+		}
+	}
+	return teams, nil
+}
+
+func (m *mockTeamStorage) Restore(teamName string) error {
+	team, ok := m.deleted[teamName]
+	if !ok {
+		return errNotFound
+	}
+	m.teams[teamName] = team.Team
+	delete(m.deleted, teamName)
+	return nil
+}
+
+func (m *mockTeamStorage) Purge(retention time.Duration) ([]string, error) {
+	var purged []string
+	for name, team := range m.deleted {
+		if time.Since(*team.DeletedAt) >= retention {
+			purged = append(purged, name)
+			delete(m.deleted, name)
+		}
+	}
+	slices.Sort(purged)
+	return purged, nil
+}
+
+func (m *mockTeamStorage) AddMember(teamName string, member domain.TeamMember) error {
+	team := m.teams[teamName]
+	team.TeamName = teamName
+	team.Members = append(team.Members, member)
+	m.teams[teamName] = team
+	return nil
+}
+
+func (m *mockTeamStorage) SetMemberTeam(userID string, teamName *string) error {
+	var moved *domain.TeamMember
+	for name, team := range m.teams {
+		index := slices.IndexFunc(team.Members, func(member domain.TeamMember) bool {
+			return member.UserID == userID
+		})
+		if index < 0 {
+			continue
+		}
+		member := team.Members[index]
+		moved = &member
+		team.Members = slices.Delete(slices.Clone(team.Members), index, index+1)
+		m.teams[name] = team
+	}
+	if moved == nil {
+		return errNotFound
+	}
+	if teamName != nil {
+		return m.AddMember(*teamName, *moved)
+	}
+	return nil
+}
+
 // mockPullRequestStorage is a mock implementation of storager.PullRequestStorager
 type mockPullRequestStorage struct {
 	prs map[string]domain.PullRequest
+	// reasons records the reason of every Reassign call by pull request ID.
+	reasons map[string][]domain.AssignmentReason
 }
 
 func newMockPullRequestStorage() *mockPullRequestStorage {
 	return &mockPullRequestStorage{
-		prs: make(map[string]domain.PullRequest),
+		prs:     make(map[string]domain.PullRequest),
+		reasons: make(map[string][]domain.AssignmentReason),
 	}
 }
 
@@ -146,30 +217,322 @@ func (m *mockPullRequestStorage) Merge(pullRequest domain.PullRequest) error {
 	return nil
 }
 
-func (m *mockPullRequestStorage) Reassign(pullRequest domain.PullRequest) error {
+func (m *mockPullRequestStorage) Reassign(pullRequest domain.PullRequest, reason domain.AssignmentReason) error {
 	if _, ok := m.prs[pullRequest.ID]; !ok {
 		return errNotFound
 	}
 	m.prs[pullRequest.ID] = pullRequest
+	m.reasons[pullRequest.ID] = append(m.reasons[pullRequest.ID], reason)
 	return nil
 }
 
+func (m *mockPullRequestStorage) SelectHistory(pullRequestID string) ([]domain.AssignmentEvent, error) {
+	return nil, nil
+}
+
 func (m *mockPullRequestStorage) SelectUserPullRequestsReviews(userID string) ([]domain.PullRequest, error) {
 	var result []domain.PullRequest
 	for _, pr := range m.prs {
-		// Check if userID is in assigned_reviewers
-		// Format is "[id1, id2]" or "[id1]"
-		reviewers := strings.Trim(pr.AssignedReviewers, "[]")
-		if reviewers != "" {
-			parts := strings.Split(reviewers, ",")
-			for _, part := range parts {
-				if strings.TrimSpace(part) == userID {
-					result = append(result, pr)
-					break
-				}
+		if slices.Contains(pr.AssignedReviewers, userID) {
+			result = append(result, pr)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockPullRequestStorage) CountUserOpenReviews(userIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(userIDs))
+	for _, userID := range userIDs {
+		prs, err := m.SelectUserPullRequestsReviews(userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			if pr.Status == domain.Open {
+				counts[userID]++
 			}
 		}
 	}
+	return counts, nil
+}
+
+func (m *mockPullRequestStorage) UpdateStatus(pullRequestID string, status domain.PullRequestStatus) error {
+	pr, ok := m.prs[pullRequestID]
+	if !ok {
+		return errNotFound
+	}
+	pr.Status = status
+	m.prs[pullRequestID] = pr
+	return nil
+}
+
+func (m *mockPullRequestStorage) SubmitReview(pullRequestID string, review domain.Review) error {
+	pr, ok := m.prs[pullRequestID]
+	if !ok || !slices.Contains(pr.AssignedReviewers, review.ReviewerID) {
+		return domain.ErrNotAssigned
+	}
+	now := time.Now()
+	review.SubmittedAt = &now
+	pr.Reviews = slices.DeleteFunc(slices.Clone(pr.Reviews), func(r domain.Review) bool {
+		return r.ReviewerID == review.ReviewerID
+	})
+	pr.Reviews = append(pr.Reviews, review)
+	m.prs[pullRequestID] = pr
+	return nil
+}
+
+// mockPolicyStorage is a mock implementation of storager.MergePolicyStorager
+type mockPolicyStorage struct {
+	policies  map[string]domain.MergePolicy
+	overrides []domain.MergePolicyOverride
+}
+
+func newMockPolicyStorage() *mockPolicyStorage {
+	return &mockPolicyStorage{
+		policies: make(map[string]domain.MergePolicy),
+	}
+}
+
+func (m *mockPolicyStorage) Select(teamName string) ([]domain.MergePolicy, error) {
+	policy, ok := m.policies[teamName]
+	if !ok {
+		return []domain.MergePolicy{}, nil
+	}
+	return []domain.MergePolicy{policy}, nil
+}
+
+func (m *mockPolicyStorage) Upsert(policy domain.MergePolicy) error {
+	m.policies[policy.TeamName] = policy
+	return nil
+}
+
+func (m *mockPolicyStorage) InsertOverride(override domain.MergePolicyOverride) error {
+	m.overrides = append(m.overrides, override)
+	return nil
+}
+
+// mockAbsenceStorage is a mock implementation of storager.AbsenceStorager.
+// Users are absent if one of their absences covers now.
+type mockAbsenceStorage struct {
+	absences []domain.Absence
+	now      time.Time
+}
+
+func newMockAbsenceStorage() *mockAbsenceStorage {
+	return &mockAbsenceStorage{now: time.Now()}
+}
+
+func (m *mockAbsenceStorage) Insert(absence domain.Absence) (domain.Absence, error) {
+	absence.ID = int64(len(m.absences) + 1)
+	m.absences = append(m.absences, absence)
+	return absence, nil
+}
+
+func (m *mockAbsenceStorage) Select(userID string) ([]domain.Absence, error) {
+	var absences []domain.Absence
+	for _, absence := range m.absences {
+		if absence.UserID == userID {
+			absences = append(absences, absence)
+		}
+	}
+	return absences, nil
+}
+
+func (m *mockAbsenceStorage) SelectAbsentUserIDs() ([]string, error) {
+	var userIDs []string
+	for _, absence := range m.absences {
+		if absence.Covers(m.now) {
+			userIDs = append(userIDs, absence.UserID)
+		}
+	}
+	return userIDs, nil
+}
+
+// mockSettingsStorage is a mock implementation of storager.TeamSettingsStorager
+type mockSettingsStorage struct {
+	settings map[string]domain.TeamSettings
+}
+
+func newMockSettingsStorage() *mockSettingsStorage {
+	return &mockSettingsStorage{
+		settings: make(map[string]domain.TeamSettings),
+	}
+}
+
+func (m *mockSettingsStorage) Select(teamName string) ([]domain.TeamSettings, error) {
+	settings, ok := m.settings[teamName]
+	if !ok {
+		return []domain.TeamSettings{}, nil
+	}
+	return []domain.TeamSettings{settings}, nil
+}
+
+func (m *mockSettingsStorage) Upsert(settings domain.TeamSettings) error {
+	m.settings[settings.TeamName] = settings
+	return nil
+}
+
+// mockPoolStorage is a mock implementation of storager.ReviewerPoolStorager
+type mockPoolStorage struct {
+	pools map[string][]string
+}
+
+func newMockPoolStorage() *mockPoolStorage {
+	return &mockPoolStorage{
+		pools: make(map[string][]string),
+	}
+}
+
+func (m *mockPoolStorage) Select(name string) ([]domain.ReviewerPool, error) {
+	members, ok := m.pools[name]
+	if !ok {
+		return []domain.ReviewerPool{}, nil
+	}
+	return []domain.ReviewerPool{{Name: name, Members: members}}, nil
+}
+
+func (m *mockPoolStorage) Upsert(pool domain.ReviewerPool) error {
+	m.pools[pool.Name] = pool.Members
+	return nil
+}
+
+// mockCodeownersStorage is a mock implementation of storager.CodeownersStorager
+type mockCodeownersStorage struct {
+	files map[string]domain.Codeowners
+}
+
+func newMockCodeownersStorage() *mockCodeownersStorage {
+	return &mockCodeownersStorage{
+		files: make(map[string]domain.Codeowners),
+	}
+}
+
+func (m *mockCodeownersStorage) Select(teamName string) ([]domain.Codeowners, error) {
+	file, ok := m.files[teamName]
+	if !ok {
+		return []domain.Codeowners{}, nil
+	}
+	return []domain.Codeowners{file}, nil
+}
+
+func (m *mockCodeownersStorage) Upsert(codeowners domain.Codeowners) error {
+	m.files[codeowners.TeamName] = codeowners
+	return nil
+}
+
+// mockEscalationStorage is a mock implementation of storager.EscalationStorager
+type mockEscalationStorage struct {
+	stale       []domain.StaleReview
+	escalations []domain.Escalation
+}
+
+func newMockEscalationStorage() *mockEscalationStorage {
+	return &mockEscalationStorage{}
+}
+
+func (m *mockEscalationStorage) SelectStaleReviews() ([]domain.StaleReview, error) {
+	return m.stale, nil
+}
+
+func (m *mockEscalationStorage) Insert(escalation domain.Escalation) error {
+	m.escalations = append(m.escalations, escalation)
+	return nil
+}
+
+// mockWebhookStorage is a mock implementation of storager.WebhookStorager
+type mockWebhookStorage struct {
+	subscriptions []domain.WebhookSubscription
+	deliveries    []domain.WebhookDelivery
+}
+
+func newMockWebhookStorage() *mockWebhookStorage {
+	return &mockWebhookStorage{}
+}
+
+func (m *mockWebhookStorage) Insert(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	subscription.ID = int64(len(m.subscriptions) + 1)
+	m.subscriptions = append(m.subscriptions, subscription)
+	return subscription, nil
+}
+
+func (m *mockWebhookStorage) Select(id int64) ([]domain.WebhookSubscription, error) {
+	for _, subscription := range m.subscriptions {
+		if subscription.ID == id {
+			return []domain.WebhookSubscription{subscription}, nil
+		}
+	}
+	return []domain.WebhookSubscription{}, nil
+}
+
+func (m *mockWebhookStorage) SelectSubscribed(eventType domain.WebhookEventType) ([]domain.WebhookSubscription, error) {
+	var result []domain.WebhookSubscription
+	for _, subscription := range m.subscriptions {
+		if subscription.Subscribed(eventType) {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebhookStorage) Delete(id int64) error {
+	m.subscriptions = slices.DeleteFunc(m.subscriptions, func(subscription domain.WebhookSubscription) bool {
+		return subscription.ID == id
+	})
+	return nil
+}
+
+func (m *mockWebhookStorage) InsertDelivery(delivery domain.WebhookDelivery) error {
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *mockWebhookStorage) SelectDeliveries(subscriptionID int64, limit int) ([]domain.WebhookDelivery, error) {
+	var result []domain.WebhookDelivery
+	for i := len(m.deliveries) - 1; i >= 0 && len(result) < limit; i-- {
+		if m.deliveries[i].SubscriptionID == subscriptionID {
+			result = append(result, m.deliveries[i])
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebhookStorage) SelectEventDeliveries(eventID string) ([]domain.WebhookDelivery, error) {
+	var result []domain.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.EventID == eventID {
+			result = append(result, delivery)
+		}
+	}
+	return result, nil
+}
+
+// mockAccountStorage is a mock implementation of storager.AccountStorager
+type mockAccountStorage struct {
+	accounts map[domain.Provider]map[string]string
+}
+
+func newMockAccountStorage() *mockAccountStorage {
+	return &mockAccountStorage{accounts: make(map[domain.Provider]map[string]string)}
+}
+
+func (m *mockAccountStorage) Upsert(account domain.ProviderAccount) error {
+	if m.accounts[account.Provider] == nil {
+		m.accounts[account.Provider] = make(map[string]string)
+	}
+	m.accounts[account.Provider][account.Login] = account.UserID
+	return nil
+}
+
+func (m *mockAccountStorage) Select(provider domain.Provider, login *string) ([]domain.ProviderAccount, error) {
+	var result []domain.ProviderAccount
+	for accountLogin, userID := range m.accounts[provider] {
+		if login == nil || accountLogin == *login {
+			result = append(result, domain.ProviderAccount{Provider: provider, Login: accountLogin, UserID: userID})
+		}
+	}
+	slices.SortFunc(result, func(a, b domain.ProviderAccount) int {
+		return strings.Compare(a.Login, b.Login)
+	})
 	return result, nil
 }
 
@@ -178,11 +541,27 @@ func createMockStorage() *db.Storage {
 	userStorage := newMockUserStorage()
 	teamStorage := newMockTeamStorage()
 	prStorage := newMockPullRequestStorage()
+	policyStorage := newMockPolicyStorage()
+	absenceStorage := newMockAbsenceStorage()
+	settingsStorage := newMockSettingsStorage()
+	poolStorage := newMockPoolStorage()
+	codeownersStorage := newMockCodeownersStorage()
+	escalationStorage := newMockEscalationStorage()
+	webhookStorage := newMockWebhookStorage()
+	accountStorage := newMockAccountStorage()
 
 	return &db.Storage{
 		UserStorage:        userStorage,
 		TeamStorage:        teamStorage,
 		PullRequestStorage: prStorage,
+		PolicyStorage:      policyStorage,
+		AbsenceStorage:     absenceStorage,
+		SettingsStorage:    settingsStorage,
+		PoolStorage:        poolStorage,
+		CodeownersStorage:  codeownersStorage,
+		EscalationStorage:  escalationStorage,
+		WebhookStorage:     webhookStorage,
+		AccountStorage:     accountStorage,
 	}
 }
 
@@ -205,7 +584,7 @@ func createTestTeam(teamName string, members []domain.TeamMember) domain.Team {
 }
 
 // Helper function to create test PR
-func createTestPR(id, name, authorID string, status domain.PullRequestStatus, reviewers string) domain.PullRequest {
+func createTestPR(id, name, authorID string, status domain.PullRequestStatus, reviewers []string) domain.PullRequest {
 	return domain.PullRequest{
 		PullRequestShort: domain.PullRequestShort{
 			ID:       id,
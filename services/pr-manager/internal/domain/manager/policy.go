@@ -0,0 +1,57 @@
+package manager
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/storager"
+)
+
+type PolicyManager struct {
+	TeamStorage   storager.TeamStorager
+	PolicyStorage storager.MergePolicyStorager
+}
+
+func NewPolicyManager(teamStorage storager.TeamStorager, policyStorage storager.MergePolicyStorager) *PolicyManager {
+	return &PolicyManager{TeamStorage: teamStorage, PolicyStorage: policyStorage}
+}
+
+func (m *PolicyManager) SetMergePolicy(policy domain.MergePolicy) (domain.MergePolicy, error) {
+	if policy.MinApprovals < 0 {
+		return domain.MergePolicy{}, domain.ErrInvalidPolicy
+	}
+	if err := ensureTeamExists(m.TeamStorage, policy.TeamName); err != nil {
+		return domain.MergePolicy{}, err
+	}
+
+	err := m.PolicyStorage.Upsert(policy)
+	if err != nil {
+		return domain.MergePolicy{}, err
+	}
+	return policy, nil
+}
+
+// GetMergePolicy returns the team policy, or the default one if it was never set.
+func (m *PolicyManager) GetMergePolicy(teamName string) (domain.MergePolicy, error) {
+	if err := ensureTeamExists(m.TeamStorage, teamName); err != nil {
+		return domain.MergePolicy{}, err
+	}
+
+	policies, err := m.PolicyStorage.Select(teamName)
+	if err != nil {
+		return domain.MergePolicy{}, err
+	}
+	if len(policies) == 0 {
+		return domain.MergePolicy{TeamName: teamName}, nil
+	}
+	return policies[0], nil
+}
+
+func ensureTeamExists(teamStorage storager.TeamStorager, teamName string) error {
+	teams, err := teamStorage.Select(&teamName)
+	if err != nil {
+		return err
+	}
+	if len(teams) == 0 {
+		return domain.ErrTeamNotFound
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package manager
+
+import (
+	"slices"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/storager"
+)
+
+type PoolManager struct {
+	UserStorage storager.UserStorager
+	PoolStorage storager.ReviewerPoolStorager
+}
+
+func NewPoolManager(userStorage storager.UserStorager, poolStorage storager.ReviewerPoolStorager) *PoolManager {
+	return &PoolManager{UserStorage: userStorage, PoolStorage: poolStorage}
+}
+
+// SetPool creates the pool or replaces its members. Every member must be an
+// existing user.
+func (m *PoolManager) SetPool(pool domain.ReviewerPool) (domain.ReviewerPool, error) {
+	if pool.Name == "" {
+		return domain.ReviewerPool{}, domain.ErrInvalidPool
+	}
+	for _, userID := range pool.Members {
+		users, err := m.UserStorage.Select(&userID)
+		if err != nil {
+			return domain.ReviewerPool{}, err
+		}
+		if len(users) == 0 {
+			return domain.ReviewerPool{}, domain.ErrUserNotFound
+		}
+	}
+
+	err := m.PoolStorage.Upsert(pool)
+	if err != nil {
+		return domain.ReviewerPool{}, err
+	}
+	return m.GetPool(pool.Name)
+}
+
+func (m *PoolManager) GetPool(name string) (domain.ReviewerPool, error) {
+	pools, err := m.PoolStorage.Select(name)
+	if err != nil {
+		return domain.ReviewerPool{}, err
+	}
+	if len(pools) == 0 {
+		return domain.ReviewerPool{}, domain.ErrPoolNotFound
+	}
+	return pools[0], nil
+}
+
+// missingReviewers picks the reviewers the pull request lacks: members of the
+// author team up to the required count and, if the team draws reviewers from a
+// pool, pool members from other teams up to the pool count. Owners of the
+// changed paths are picked first.
+func (m *PullRequestManager) missingReviewers(authorTeam domain.Team, pullRequest domain.PullRequest) ([]string, error) {
+	settings, err := m.teamSettings(authorTeam.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	required := settings.ReviewersRequired
+	if pullRequest.ReviewersCount != nil {
+		required = *pullRequest.ReviewersCount
+	}
+	owned, err := m.pathOwners(authorTeam.TeamName, pullRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	teamUserIDs := make([]string, 0, len(authorTeam.Members))
+	for _, member := range authorTeam.Members {
+		teamUserIDs = append(teamUserIDs, member.UserID)
+	}
+	excluded := append([]string{pullRequest.AuthorID}, pullRequest.AssignedReviewers...)
+
+	newReviewers := []string{}
+	if missing := required - countReviewers(pullRequest.AssignedReviewers, teamUserIDs); missing > 0 {
+		activeUserIDs, err := m.getActiveUserIDsFromTeam(authorTeam.Members)
+		if err != nil {
+			return nil, err
+		}
+		candidates := m.filterReviewers(activeUserIDs, excluded...)
+		newReviewers, err = m.pickReviewers(authorTeam.TeamName, candidates, missing, owned)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if settings.ReviewerPool == nil {
+		return newReviewers, nil
+	}
+	pool, activeUserIDs, err := m.activePoolMembers(*settings.ReviewerPool, authorTeam.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	poolReviewers := slices.DeleteFunc(slices.Clone(pool.Members), func(userID string) bool {
+		return slices.Contains(teamUserIDs, userID)
+	})
+	missing := settings.PoolReviewersRequired - countReviewers(pullRequest.AssignedReviewers, poolReviewers)
+	if missing <= 0 {
+		return newReviewers, nil
+	}
+	candidates := m.filterReviewers(activeUserIDs, append(excluded, newReviewers...)...)
+	fromPool, err := m.pickReviewers(pool.Name, candidates, missing, owned)
+	if err != nil {
+		return nil, err
+	}
+	return append(newReviewers, fromPool...), nil
+}
+
+// poolReplacementCandidates reports whether reviewerID was drawn from the
+// reviewer pool of the pull request author's team. If so, it returns the pool
+// name and the active pool members who can take the review over.
+func (m *PullRequestManager) poolReplacementCandidates(pullRequest domain.PullRequest, reviewerID string) (string, []string, bool, error) {
+	users, err := m.Storage.UserStorage.Select(&pullRequest.AuthorID)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if len(users) == 0 || users[0].TeamName == "" {
+		return "", nil, false, nil
+	}
+	authorTeamName := users[0].TeamName
+
+	settings, err := m.teamSettings(authorTeamName)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if settings.ReviewerPool == nil {
+		return "", nil, false, nil
+	}
+	reviewers, err := m.Storage.UserStorage.Select(&reviewerID)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if len(reviewers) > 0 && reviewers[0].TeamName == authorTeamName {
+		return "", nil, false, nil
+	}
+
+	pool, activeUserIDs, err := m.activePoolMembers(*settings.ReviewerPool, authorTeamName)
+	if err != nil {
+		return "", nil, false, err
+	}
+	if !slices.Contains(pool.Members, reviewerID) {
+		return "", nil, false, nil
+	}
+	return pool.Name, activeUserIDs, true, nil
+}
+
+// activePoolMembers returns the pool and its active, present members who are
+// not in excludedTeam.
+func (m *PullRequestManager) activePoolMembers(poolName string, excludedTeam string) (domain.ReviewerPool, []string, error) {
+	pools, err := m.Storage.PoolStorage.Select(poolName)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, err
+	}
+	if len(pools) == 0 {
+		return domain.ReviewerPool{Name: poolName}, nil, nil
+	}
+	pool := pools[0]
+
+	users, err := m.Storage.UserStorage.Select(nil)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, err
+	}
+	members := make([]domain.TeamMember, 0, len(pool.Members))
+	for _, user := range users {
+		if user.TeamName != excludedTeam && slices.Contains(pool.Members, user.UserID) {
+			members = append(members, domain.TeamMember{UserID: user.UserID, Username: user.Username, IsActive: user.IsActive})
+		}
+	}
+	activeUserIDs, err := m.getActiveUserIDsFromTeam(members)
+	if err != nil {
+		return domain.ReviewerPool{}, nil, err
+	}
+	return pool, activeUserIDs, nil
+}
+
+// countReviewers returns how many of the reviewers are among userIDs.
+func countReviewers(reviewers []string, userIDs []string) int {
+	count := 0
+	for _, reviewer := range reviewers {
+		if slices.Contains(userIDs, reviewer) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+)
+
+// setupPoolStorage adds a security team and a security pool spanning teams to
+// the membership storage. Backend takes one reviewer from its own team and one
+// from the pool.
+func setupPoolStorage(storage *db.Storage) {
+	setupMembershipStorage(storage)
+	security := []domain.TeamMember{
+		{UserID: "user6", Username: "security1", IsActive: true},
+		{UserID: "user7", Username: "security2", IsActive: false},
+		{UserID: "user9", Username: "security3", IsActive: true},
+	}
+	storage.TeamStorage.Insert(createTestTeam("security", security))
+	for _, member := range security {
+		storage.UserStorage.Insert(createTestUser(member.UserID, member.Username, "security", member.IsActive))
+	}
+	storage.PoolStorage.Upsert(domain.ReviewerPool{Name: "security", Members: []string{"user2", "user5", "user6", "user7"}})
+
+	pool := "security"
+	storage.SettingsStorage.Upsert(domain.TeamSettings{TeamName: "backend", ReviewersRequired: 1, ReviewerPool: &pool, PoolReviewersRequired: 1})
+}
+
+func TestPoolManager_SetPool(t *testing.T) {
+	storage := createMockStorage()
+	setupMembershipStorage(storage)
+	poolManager := NewPoolManager(storage.UserStorage, storage.PoolStorage)
+
+	if _, err := poolManager.SetPool(domain.ReviewerPool{Members: []string{"user2"}}); !errors.Is(err, domain.ErrInvalidPool) {
+		t.Errorf("expected ErrInvalidPool, got %v", err)
+	}
+	if _, err := poolManager.SetPool(domain.ReviewerPool{Name: "security", Members: []string{"ghost"}}); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+	if _, err := poolManager.GetPool("security"); !errors.Is(err, domain.ErrPoolNotFound) {
+		t.Errorf("expected ErrPoolNotFound, got %v", err)
+	}
+
+	pool, err := poolManager.SetPool(domain.ReviewerPool{Name: "security", Members: []string{"user2", "user5"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(pool.Members, []string{"user2", "user5"}) {
+		t.Errorf("unexpected members %v", pool.Members)
+	}
+}
+
+func TestSettingsManager_ReviewerPool(t *testing.T) {
+	storage := createMockStorage()
+	setupPoolStorage(storage)
+	settingsManager := NewSettingsManager(storage.TeamStorage, storage.SettingsStorage, storage.PoolStorage, storage.UserStorage)
+
+	missing := "missing"
+	_, err := settingsManager.SetTeamSettings(domain.TeamSettings{TeamName: "backend", ReviewersRequired: 1, ReviewerPool: &missing})
+	if !errors.Is(err, domain.ErrPoolNotFound) {
+		t.Errorf("expected ErrPoolNotFound, got %v", err)
+	}
+
+	pool := "security"
+	settings, err := settingsManager.SetTeamSettings(domain.TeamSettings{TeamName: "frontend", ReviewersRequired: 1, ReviewerPool: &pool})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.PoolReviewersRequired != 1 {
+		t.Errorf("expected one pool reviewer by default, got %d", settings.PoolReviewersRequired)
+	}
+
+	settings, err = settingsManager.SetTeamSettings(domain.TeamSettings{TeamName: "frontend", ReviewersRequired: 1, PoolReviewersRequired: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.PoolReviewersRequired != 0 {
+		t.Errorf("expected pool count to be ignored without a pool, got %d", settings.PoolReviewersRequired)
+	}
+}
+
+func TestPullRequestManager_CreatePullRequestFromPool(t *testing.T) {
+	for range 10 {
+		storage := createMockStorage()
+		setupPoolStorage(storage)
+		m := NewPullRequestManager(storage)
+
+		pr, err := m.CreatePullRequest(createTestPR("pr1", "Test PR", "user1", domain.Open, nil))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pr.AssignedReviewers) != 2 {
+			t.Fatalf("expected 2 reviewers, got %v", pr.AssignedReviewers)
+		}
+		if !slices.Contains([]string{"user2", "user3"}, pr.AssignedReviewers[0]) {
+			t.Errorf("expected a backend reviewer first, got %v", pr.AssignedReviewers)
+		}
+		// user2 is in the pool but already in the author's team, user7 is inactive.
+		if !slices.Contains([]string{"user5", "user6"}, pr.AssignedReviewers[1]) {
+			t.Errorf("expected a pool reviewer from another team, got %v", pr.AssignedReviewers)
+		}
+	}
+}
+
+func TestPullRequestManager_ReassignPoolReviewer(t *testing.T) {
+	storage := createMockStorage()
+	setupPoolStorage(storage)
+	storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user6"}))
+	m := NewPullRequestManager(storage)
+
+	pr, replacedBy, err := m.ReassignPullRequest("pr1", "user6")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replacedBy != "user5" {
+		t.Errorf("expected pool member user5 to replace user6, got %q", replacedBy)
+	}
+	if !slices.Contains(pr.AssignedReviewers, "user2") {
+		t.Errorf("expected user2 to stay, got %v", pr.AssignedReviewers)
+	}
+
+	_, replacedBy, err = m.ReassignPullRequest("pr1", "user2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replacedBy != "user3" {
+		t.Errorf("expected teammate user3 to replace user2, got %q", replacedBy)
+	}
+}
+
+func TestPullRequestManager_ReleasePoolReviewer(t *testing.T) {
+	storage := createMockStorage()
+	setupPoolStorage(storage)
+	storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user6"}))
+	m := NewPullRequestManager(storage)
+
+	user := createTestUser("user6", "security1", "security", false)
+	storage.UserStorage.Update(user)
+	reassignments, err := m.ReleaseDeactivatedReviewer(user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reassignments) != 1 || reassignments[0].ReplacedBy != "user5" {
+		t.Errorf("expected the review to go to pool member user5, got %+v", reassignments)
+	}
+}
+
+func TestPullRequestManager_FillReviewersFromPool(t *testing.T) {
+	storage := createMockStorage()
+	setupPoolStorage(storage)
+	storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2"}))
+	teamName := "backend"
+	backend, _ := storage.TeamStorage.Select(&teamName)
+	m := NewPullRequestManager(storage)
+
+	updated, err := m.FillReviewers(backend[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updated) != 1 || len(updated[0].AssignedReviewers) != 2 {
+		t.Fatalf("expected a pool reviewer to be added, got %+v", updated)
+	}
+	if !slices.Contains([]string{"user5", "user6"}, updated[0].AssignedReviewers[1]) {
+		t.Errorf("expected a pool reviewer, got %v", updated[0].AssignedReviewers)
+	}
+}
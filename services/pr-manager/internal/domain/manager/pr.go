@@ -1,8 +1,6 @@
 package manager
 
 import (
-	"fmt"
-	"math/rand/v2"
 	"slices"
 	"strings"
 
@@ -10,34 +8,38 @@ import (
 	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
 )
 
-const maxAssigners = 2
-
 type PullRequestManager struct {
-	Storage *db.Storage
+	Storage   *db.Storage
+	Selection ReviewerSelection
 }
 
 func NewPullRequestManager(storage *db.Storage) *PullRequestManager {
-	return &PullRequestManager{Storage: storage}
+	return &PullRequestManager{
+		Storage:   storage,
+		Selection: ReviewerSelection{Default: NewRandomSelector()},
+	}
 }
 
+// CreatePullRequest creates an open pull request with reviewers assigned, or a
+// draft without reviewers if pullRequest.Status is domain.Draft.
 func (m *PullRequestManager) CreatePullRequest(pullRequest domain.PullRequest) (domain.PullRequest, error) {
-	authorTeamMembers, err := m.getReviewerTeamMembers(pullRequest.AuthorID)
+	if pullRequest.ReviewersCount != nil && !domain.ValidReviewersCount(*pullRequest.ReviewersCount) {
+		return domain.PullRequest{}, domain.ErrInvalidReviewers
+	}
+	if pullRequest.Status != domain.Draft {
+		pullRequest.Status = domain.Open
+	}
+
+	authorTeam, err := m.getReviewerTeam(pullRequest.AuthorID)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
-	possibleAssigners := m.filterReviewers(m.getActiveUserIDsFromTeam(authorTeamMembers), pullRequest.AuthorID)
-	assigners := make([]string, 0, maxAssigners)
-	for range maxAssigners {
-		if len(possibleAssigners) == 0 {
-			break
+	pullRequest.AssignedReviewers = nil
+	if pullRequest.Status == domain.Open {
+		pullRequest.AssignedReviewers, err = m.selectReviewers(authorTeam, pullRequest)
+		if err != nil {
+			return domain.PullRequest{}, err
 		}
-		assigners = append(assigners, m.getRandomUserID(possibleAssigners))
-		possibleAssigners = m.filterReviewers(possibleAssigners, assigners...)
-	}
-	if len(assigners) == 0 {
-		pullRequest.AssignedReviewers = "[]"
-	} else {
-		pullRequest.AssignedReviewers = fmt.Sprintf("[%s]", strings.Join(assigners, ", "))
 	}
 
 	err = m.Storage.PullRequestStorage.Create(pullRequest)
@@ -58,23 +60,84 @@ func (m *PullRequestManager) CreatePullRequest(pullRequest domain.PullRequest) (
 	return prs[0], nil
 }
 
-func (m *PullRequestManager) MergePullRequest(pullRequest domain.PullRequest) (domain.PullRequest, error) {
-	err := m.Storage.PullRequestStorage.Merge(pullRequest)
+// MergePullRequest merges the pull request on behalf of mergedBy, nil if the
+// merging user is unknown, if the team merge policy allows it.
+func (m *PullRequestManager) MergePullRequest(pullRequest domain.PullRequest, mergedBy *string) (domain.PullRequest, error) {
+	return m.merge(pullRequest, mergedBy, nil)
+}
+
+// ForceMergePullRequest merges regardless of the team merge policy. Unmet
+// rules are recorded together with the reason.
+func (m *PullRequestManager) ForceMergePullRequest(pullRequest domain.PullRequest, mergedBy *string, reason string) (domain.PullRequest, error) {
+	return m.merge(pullRequest, mergedBy, &reason)
+}
+
+func (m *PullRequestManager) merge(pullRequest domain.PullRequest, mergedBy *string, overrideReason *string) (domain.PullRequest, error) {
+	current, err := m.GetPullRequest(&pullRequest.ID)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
+	if current.Status == domain.Merged {
+		return current, nil
+	}
+	if !canTransition(current.Status, domain.Merged) {
+		return domain.PullRequest{}, domain.ErrInvalidTransition
+	}
 
-	prs, err := m.Storage.PullRequestStorage.Select(&pullRequest.ID)
+	policy, err := m.mergePolicy(current.AuthorID)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
-	if len(prs) == 0 {
-		return domain.PullRequest{}, domain.ErrNotFound
+	if unmetRules := policy.UnmetRules(current, mergedBy); len(unmetRules) > 0 {
+		if overrideReason == nil {
+			return domain.PullRequest{}, &domain.PolicyError{UnmetRules: unmetRules}
+		}
+		err = m.Storage.PolicyStorage.InsertOverride(domain.MergePolicyOverride{
+			PullRequestID: current.ID,
+			Reason:        *overrideReason,
+			UnmetRules:    unmetRules,
+		})
+		if err != nil {
+			return domain.PullRequest{}, err
+		}
 	}
-	return prs[0], nil
+
+	err = m.Storage.PullRequestStorage.Merge(pullRequest)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	return m.GetPullRequest(&pullRequest.ID)
+}
+
+// mergePolicy returns the policy of the author's team. Authors whose team was
+// deleted get the default policy.
+func (m *PullRequestManager) mergePolicy(authorID string) (domain.MergePolicy, error) {
+	users, err := m.Storage.UserStorage.Select(&authorID)
+	if err != nil {
+		return domain.MergePolicy{}, err
+	}
+	if len(users) == 0 {
+		return domain.MergePolicy{}, nil
+	}
+
+	policies, err := m.Storage.PolicyStorage.Select(users[0].TeamName)
+	if err != nil {
+		return domain.MergePolicy{}, err
+	}
+	if len(policies) == 0 {
+		return domain.MergePolicy{TeamName: users[0].TeamName}, nil
+	}
+	return policies[0], nil
 }
 
 func (m *PullRequestManager) ReassignPullRequest(pullRequestID string, oldReviewerID string) (domain.PullRequest, string, error) {
+	return m.reassign(pullRequestID, oldReviewerID, domain.AssignmentReassign)
+}
+
+// reassign replaces the reviewer and records the change in the history with
+// the reason.
+func (m *PullRequestManager) reassign(pullRequestID string, oldReviewerID string, reason domain.AssignmentReason) (domain.PullRequest, string, error) {
 	prs, err := m.Storage.PullRequestStorage.Select(&pullRequestID)
 	if err != nil {
 		return domain.PullRequest{}, "", err
@@ -84,47 +147,58 @@ func (m *PullRequestManager) ReassignPullRequest(pullRequestID string, oldReview
 	}
 	pullRequest := prs[0]
 
-	if pullRequest.Status == domain.Merged {
-		return domain.PullRequest{}, "", domain.ErrPRMerged
+	if err := requireOpen(pullRequest); err != nil {
+		return domain.PullRequest{}, "", err
 	}
 
-	oldReviewerTeamMembers, err := m.getReviewerTeamMembers(oldReviewerID)
+	// Reviewers drawn from the author team's pool are replaced from the pool,
+	// others from their own team.
+	selectionKey, activeUserIDs, fromPool, err := m.poolReplacementCandidates(pullRequest, oldReviewerID)
 	if err != nil {
 		return domain.PullRequest{}, "", err
 	}
-
-	oldReviewers := strings.Split(strings.Trim(pullRequest.AssignedReviewers, "[]"), ",")
-	var anotherReviewer string
-	foundOldReviewer := false
-	for _, reviewer := range oldReviewers {
-		reviewer = strings.Trim(reviewer, " ")
-		if reviewer == oldReviewerID {
-			foundOldReviewer = true
-		} else if reviewer != "" {
-			anotherReviewer = reviewer
+	if !fromPool {
+		oldReviewerTeam, err := m.getReviewerTeam(oldReviewerID)
+		if err != nil {
+			return domain.PullRequest{}, "", err
+		}
+		selectionKey = oldReviewerTeam.TeamName
+		activeUserIDs, err = m.getActiveUserIDsFromTeam(oldReviewerTeam.Members)
+		if err != nil {
+			return domain.PullRequest{}, "", err
 		}
 	}
-	if !foundOldReviewer {
+
+	otherReviewers := slices.DeleteFunc(slices.Clone(pullRequest.AssignedReviewers), func(reviewer string) bool {
+		return reviewer == oldReviewerID
+	})
+	if len(otherReviewers) == len(pullRequest.AssignedReviewers) {
 		return domain.PullRequest{}, "", domain.ErrNotAssigned
 	}
 
-	newPossibleReviewers := m.filterReviewers(m.getActiveUserIDsFromTeam(oldReviewerTeamMembers), oldReviewerID, pullRequest.AuthorID, anotherReviewer)
+	owned, err := m.authorPathOwners(pullRequest)
+	if err != nil {
+		return domain.PullRequest{}, "", err
+	}
+	excluded := append([]string{oldReviewerID, pullRequest.AuthorID}, otherReviewers...)
+	newPossibleReviewers := m.filterReviewers(activeUserIDs, excluded...)
 
-	updatedReviewers := make([]string, 0, maxAssigners)
-	if anotherReviewer != "" {
-		updatedReviewers = append(updatedReviewers, anotherReviewer)
+	updatedReviewers := otherReviewers
+	newReviewers, err := m.pickReviewers(selectionKey, newPossibleReviewers, 1, owned)
+	if err != nil {
+		return domain.PullRequest{}, "", err
 	}
 	newReviewer := ""
-	if len(newPossibleReviewers) > 0 {
-		newReviewer = m.getRandomUserID(newPossibleReviewers)
+	if len(newReviewers) > 0 {
+		newReviewer = newReviewers[0]
 		updatedReviewers = append(updatedReviewers, newReviewer)
 	} else if len(updatedReviewers) == 0 {
-		// No replacement candidate available and no other reviewer
+		// No replacement candidate available and no other reviewers
 		return domain.PullRequest{}, "", domain.ErrNoCandidate
 	}
-	pullRequest.AssignedReviewers = fmt.Sprintf("[%s]", strings.Join(updatedReviewers, ", "))
+	pullRequest.AssignedReviewers = updatedReviewers
 
-	err = m.Storage.PullRequestStorage.Reassign(pullRequest)
+	err = m.Storage.PullRequestStorage.Reassign(pullRequest, reason)
 	if err != nil {
 		return domain.PullRequest{}, "", err
 	}
@@ -141,40 +215,83 @@ func (m *PullRequestManager) ReassignPullRequest(pullRequestID string, oldReview
 	return updatedPullRequest, newReviewer, nil
 }
 
-func (m *PullRequestManager) getReviewerTeamMembers(reviewerID string) ([]domain.TeamMember, error) {
+func (m *PullRequestManager) SubmitReview(pullRequestID string, review domain.Review) (domain.PullRequest, error) {
+	if review.State == domain.ReviewPending {
+		return domain.PullRequest{}, domain.ErrInvalidReviewState
+	}
+
+	pullRequest, err := m.GetPullRequest(&pullRequestID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	if err := requireOpen(pullRequest); err != nil {
+		return domain.PullRequest{}, err
+	}
+	if !slices.Contains(pullRequest.AssignedReviewers, review.ReviewerID) {
+		return domain.PullRequest{}, domain.ErrNotAssigned
+	}
+
+	err = m.Storage.PullRequestStorage.SubmitReview(pullRequestID, review)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	return m.GetPullRequest(&pullRequestID)
+}
+
+// teamSettings returns the settings of the team, or the default ones if they
+// were never set.
+func (m *PullRequestManager) teamSettings(teamName string) (domain.TeamSettings, error) {
+	settings, err := m.Storage.SettingsStorage.Select(teamName)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+	if len(settings) == 0 {
+		return domain.TeamSettings{TeamName: teamName, ReviewersRequired: domain.DefaultReviewersRequired}, nil
+	}
+	return settings[0], nil
+}
+
+func (m *PullRequestManager) getReviewerTeam(reviewerID string) (domain.Team, error) {
 	users, err := m.Storage.UserStorage.Select(&reviewerID)
 	if err != nil {
-		return nil, err
+		return domain.Team{}, err
 	}
 	if len(users) == 0 {
-		return nil, domain.ErrReviewerNotFound
+		return domain.Team{}, domain.ErrReviewerNotFound
 	}
 	reviewer := users[0]
 
 	reviewerTeamName := reviewer.TeamName
 	teams, err := m.Storage.TeamStorage.Select(&reviewerTeamName)
 	if err != nil {
-		return nil, err
+		return domain.Team{}, err
 	}
 	if len(teams) == 0 {
-		return nil, domain.ErrTeamNotFound
+		return domain.Team{}, domain.ErrTeamNotFound
 	}
-	reviewerTeam := teams[0]
 
-	return reviewerTeam.Members, nil
+	return teams[0], nil
 }
 
-func (m *PullRequestManager) getActiveUserIDsFromTeam(teamMembers []domain.TeamMember) []string {
+// getActiveUserIDsFromTeam returns the active members who are not absent at
+// the moment.
+func (m *PullRequestManager) getActiveUserIDsFromTeam(teamMembers []domain.TeamMember) ([]string, error) {
+	absentUserIDs, err := m.Storage.AbsenceStorage.SelectAbsentUserIDs()
+	if err != nil {
+		return nil, err
+	}
+
 	activeUserIDsFromTeam := make([]string, 0, len(teamMembers))
 	for _, member := range teamMembers {
-		if member.IsActive {
+		if member.IsActive && !slices.Contains(absentUserIDs, member.UserID) {
 			activeUserIDsFromTeam = append(
 				activeUserIDsFromTeam,
 				member.UserID,
 			)
 		}
 	}
-	return activeUserIDsFromTeam
+	return activeUserIDsFromTeam, nil
 }
 
 func (m *PullRequestManager) filterReviewers(userIDs []string, destrictedUserIDs ...string) []string {
@@ -187,14 +304,22 @@ func (m *PullRequestManager) filterReviewers(userIDs []string, destrictedUserIDs
 	return filteredUserIDs
 }
 
-func (m *PullRequestManager) getRandomUserID(userIDs []string) string {
-	return userIDs[rand.IntN(len(userIDs))]
-}
-
 func (m *PullRequestManager) UserPullRequestsReviews(userID string) ([]domain.PullRequest, error) {
 	return m.Storage.PullRequestStorage.SelectUserPullRequestsReviews(userID)
 }
 
+// UserPendingReviews returns open pull requests the user still has to review.
+func (m *PullRequestManager) UserPendingReviews(userID string) ([]domain.PullRequest, error) {
+	prs, err := m.Storage.PullRequestStorage.SelectUserPullRequestsReviews(userID)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(prs, func(pr domain.PullRequest) bool {
+		review, _ := pr.ReviewOf(userID)
+		return pr.Status != domain.Open || review.State != domain.ReviewPending
+	}), nil
+}
+
 func (m *PullRequestManager) GetPullRequest(pullRequestID *string) (domain.PullRequest, error) {
 	prs, err := m.Storage.PullRequestStorage.Select(pullRequestID)
 	if err != nil {
@@ -206,6 +331,15 @@ func (m *PullRequestManager) GetPullRequest(pullRequestID *string) (domain.PullR
 	return prs[0], nil
 }
 
+// GetHistory returns every assignment and unassignment of reviewers of the
+// pull request, oldest first.
+func (m *PullRequestManager) GetHistory(pullRequestID string) ([]domain.AssignmentEvent, error) {
+	if _, err := m.GetPullRequest(&pullRequestID); err != nil {
+		return nil, err
+	}
+	return m.Storage.PullRequestStorage.SelectHistory(pullRequestID)
+}
+
 func (m *PullRequestManager) GetPullRequests(pullRequestID *string) ([]domain.PullRequest, error) {
 	return m.Storage.PullRequestStorage.Select(pullRequestID)
 }
@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"errors"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -34,27 +36,21 @@ func TestPullRequestManager_CreatePullRequest(t *testing.T) {
 				storage.UserStorage.Insert(createTestUser("user2", "reviewer1", "team1", true))
 				storage.UserStorage.Insert(createTestUser("user3", "reviewer2", "team1", true))
 			},
-			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, ""),
+			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, nil),
 			validate: func(t *testing.T, pr domain.PullRequest, storage *db.Storage) {
 				if pr.Status != domain.Open {
 					t.Errorf("expected status Open, got %v", pr.Status)
 				}
-				if pr.AssignedReviewers == "" {
+				if len(pr.AssignedReviewers) == 0 {
 					t.Error("expected reviewers to be assigned")
 				}
-				// Check that reviewers are in the format [user2, user3] or [user3, user2]
-				if !strings.HasPrefix(pr.AssignedReviewers, "[") || !strings.HasSuffix(pr.AssignedReviewers, "]") {
-					t.Errorf("expected reviewers in format [id1, id2], got %s", pr.AssignedReviewers)
-				}
 				// Check that author is not in reviewers
-				if strings.Contains(pr.AssignedReviewers, "user1") {
+				if slices.Contains(pr.AssignedReviewers, "user1") {
 					t.Error("author should not be in reviewers")
 				}
 				// Check that we have 2 reviewers
-				reviewers := strings.Trim(pr.AssignedReviewers, "[]")
-				reviewerList := strings.Split(reviewers, ",")
-				if len(reviewerList) != 2 {
-					t.Errorf("expected 2 reviewers, got %d", len(reviewerList))
+				if len(pr.AssignedReviewers) != 2 {
+					t.Errorf("expected 2 reviewers, got %d", len(pr.AssignedReviewers))
 				}
 			},
 		},
@@ -71,17 +67,15 @@ func TestPullRequestManager_CreatePullRequest(t *testing.T) {
 				storage.UserStorage.Insert(createTestUser("user1", "author", "team1", true))
 				storage.UserStorage.Insert(createTestUser("user2", "reviewer1", "team1", true))
 			},
-			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, ""),
+			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, nil),
 			validate: func(t *testing.T, pr domain.PullRequest, storage *db.Storage) {
-				if pr.AssignedReviewers == "" {
+				if len(pr.AssignedReviewers) == 0 {
 					t.Error("expected at least one reviewer to be assigned")
 				}
-				reviewers := strings.Trim(pr.AssignedReviewers, "[]")
-				reviewerList := strings.Split(reviewers, ",")
-				if len(reviewerList) != 1 {
-					t.Errorf("expected 1 reviewer, got %d", len(reviewerList))
+				if len(pr.AssignedReviewers) != 1 {
+					t.Errorf("expected 1 reviewer, got %d", len(pr.AssignedReviewers))
 				}
-				if strings.Contains(pr.AssignedReviewers, "user1") {
+				if slices.Contains(pr.AssignedReviewers, "user1") {
 					t.Error("author should not be in reviewers")
 				}
 			},
@@ -96,11 +90,11 @@ func TestPullRequestManager_CreatePullRequest(t *testing.T) {
 				storage.TeamStorage.Insert(team)
 				storage.UserStorage.Insert(createTestUser("user1", "author", "team1", true))
 			},
-			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, ""),
+			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, nil),
 			validate: func(t *testing.T, pr domain.PullRequest, storage *db.Storage) {
 				// Should create PR with empty reviewers list
-				if pr.AssignedReviewers != "[]" {
-					t.Errorf("expected empty reviewers list [], got %s", pr.AssignedReviewers)
+				if len(pr.AssignedReviewers) != 0 {
+					t.Errorf("expected empty reviewers list, got %v", pr.AssignedReviewers)
 				}
 			},
 		},
@@ -119,11 +113,11 @@ func TestPullRequestManager_CreatePullRequest(t *testing.T) {
 				storage.UserStorage.Insert(createTestUser("user2", "reviewer1", "team1", false))
 				storage.UserStorage.Insert(createTestUser("user3", "reviewer2", "team1", false))
 			},
-			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, ""),
+			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, nil),
 			validate: func(t *testing.T, pr domain.PullRequest, storage *db.Storage) {
 				// Should create PR with empty reviewers list (no active reviewers available)
-				if pr.AssignedReviewers != "[]" {
-					t.Errorf("expected empty reviewers list [], got %s", pr.AssignedReviewers)
+				if len(pr.AssignedReviewers) != 0 {
+					t.Errorf("expected empty reviewers list, got %v", pr.AssignedReviewers)
 				}
 			},
 		},
@@ -142,16 +136,16 @@ func TestPullRequestManager_CreatePullRequest(t *testing.T) {
 				storage.UserStorage.Insert(createTestUser("user2", "reviewer1", "team1", true))
 				storage.UserStorage.Insert(createTestUser("user3", "reviewer2", "team1", false))
 			},
-			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, ""),
+			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, nil),
 			validate: func(t *testing.T, pr domain.PullRequest, storage *db.Storage) {
-				if pr.AssignedReviewers == "" {
+				if len(pr.AssignedReviewers) == 0 {
 					t.Error("expected at least one reviewer to be assigned")
 				}
 				// Should only assign active reviewer
-				if !strings.Contains(pr.AssignedReviewers, "user2") {
+				if !slices.Contains(pr.AssignedReviewers, "user2") {
 					t.Error("expected user2 to be assigned")
 				}
-				if strings.Contains(pr.AssignedReviewers, "user3") {
+				if slices.Contains(pr.AssignedReviewers, "user3") {
 					t.Error("inactive user3 should not be assigned")
 				}
 			},
@@ -172,10 +166,10 @@ func TestPullRequestManager_CreatePullRequest(t *testing.T) {
 				storage.UserStorage.Insert(createTestUser("user3", "reviewer2", "team1", true))
 
 				// Create existing PR
-				existingPR := createTestPR("pr1", "Existing PR", "user1", domain.Open, "[user2, user3]")
+				existingPR := createTestPR("pr1", "Existing PR", "user1", domain.Open, []string{"user2", "user3"})
 				storage.PullRequestStorage.Create(existingPR)
 			},
-			pr:      createTestPR("pr1", "New PR", "user1", domain.Open, ""),
+			pr:      createTestPR("pr1", "New PR", "user1", domain.Open, nil),
 			wantErr: true,
 			errMsg:  "PR id already exists",
 		},
@@ -225,10 +219,10 @@ func TestPullRequestManager_MergePullRequest(t *testing.T) {
 		{
 			name: "successfully merge open PR",
 			setup: func(storage *db.Storage) {
-				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, "[user2, user3]")
+				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user3"})
 				storage.PullRequestStorage.Create(pr)
 			},
-			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, "[user2, user3]"),
+			pr: createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user3"}),
 			validate: func(t *testing.T, pr domain.PullRequest) {
 				if pr.Status != domain.Merged {
 					t.Errorf("expected status Merged, got %v", pr.Status)
@@ -238,13 +232,13 @@ func TestPullRequestManager_MergePullRequest(t *testing.T) {
 		{
 			name: "merge already merged PR (idempotent)",
 			setup: func(storage *db.Storage) {
-				pr := createTestPR("pr1", "Test PR", "user1", domain.Merged, "[user2, user3]")
+				pr := createTestPR("pr1", "Test PR", "user1", domain.Merged, []string{"user2", "user3"})
 				// Set merged_at to a specific time to verify it doesn't change
 				mergedTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 				pr.MergedAt = &mergedTime
 				storage.PullRequestStorage.Create(pr)
 			},
-			pr: createTestPR("pr1", "Test PR", "user1", domain.Merged, "[user2, user3]"),
+			pr: createTestPR("pr1", "Test PR", "user1", domain.Merged, []string{"user2", "user3"}),
 			validate: func(t *testing.T, pr domain.PullRequest) {
 				if pr.Status != domain.Merged {
 					t.Errorf("expected status Merged, got %v", pr.Status)
@@ -266,7 +260,7 @@ func TestPullRequestManager_MergePullRequest(t *testing.T) {
 			tt.setup(storage)
 
 			manager := NewPullRequestManager(storage)
-			result, err := manager.MergePullRequest(tt.pr)
+			result, err := manager.MergePullRequest(tt.pr, nil)
 
 			if tt.wantErr {
 				if err == nil {
@@ -287,6 +281,378 @@ func TestPullRequestManager_MergePullRequest(t *testing.T) {
 	}
 }
 
+func TestPullRequestManager_SubmitReview(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*db.Storage)
+		prID     string
+		review   domain.Review
+		wantErr  error
+		validate func(*testing.T, domain.PullRequest)
+	}{
+		{
+			name: "assigned reviewer approves",
+			setup: func(storage *db.Storage) {
+				storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user3"}))
+			},
+			prID:   "pr1",
+			review: domain.Review{ReviewerID: "user2", State: domain.ReviewApproved},
+			validate: func(t *testing.T, pr domain.PullRequest) {
+				review, ok := pr.ReviewOf("user2")
+				if !ok || review.State != domain.ReviewApproved || review.SubmittedAt == nil {
+					t.Errorf("expected approved review for user2, got %+v", review)
+				}
+				if review, _ := pr.ReviewOf("user3"); review.State != domain.ReviewPending {
+					t.Errorf("expected user3 to stay pending, got %v", review.State)
+				}
+			},
+		},
+		{
+			name: "later submission replaces earlier one",
+			setup: func(storage *db.Storage) {
+				storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2"}))
+				storage.PullRequestStorage.SubmitReview("pr1", domain.Review{ReviewerID: "user2", State: domain.ReviewChangesRequested})
+			},
+			prID:   "pr1",
+			review: domain.Review{ReviewerID: "user2", State: domain.ReviewApproved},
+			validate: func(t *testing.T, pr domain.PullRequest) {
+				if len(pr.Reviews) != 1 || pr.Reviews[0].State != domain.ReviewApproved {
+					t.Errorf("expected single approved review, got %+v", pr.Reviews)
+				}
+			},
+		},
+		{
+			name: "reviewer not assigned",
+			setup: func(storage *db.Storage) {
+				storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2"}))
+			},
+			prID:    "pr1",
+			review:  domain.Review{ReviewerID: "user3", State: domain.ReviewCommented},
+			wantErr: domain.ErrNotAssigned,
+		},
+		{
+			name: "merged PR",
+			setup: func(storage *db.Storage) {
+				storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Merged, []string{"user2"}))
+			},
+			prID:    "pr1",
+			review:  domain.Review{ReviewerID: "user2", State: domain.ReviewApproved},
+			wantErr: domain.ErrPRMerged,
+		},
+		{
+			name:    "PR not found",
+			setup:   func(storage *db.Storage) {},
+			prID:    "missing",
+			review:  domain.Review{ReviewerID: "user2", State: domain.ReviewApproved},
+			wantErr: domain.ErrNotFound,
+		},
+		{
+			name: "pending cannot be submitted",
+			setup: func(storage *db.Storage) {
+				storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2"}))
+			},
+			prID:    "pr1",
+			review:  domain.Review{ReviewerID: "user2", State: domain.ReviewPending},
+			wantErr: domain.ErrInvalidReviewState,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			tt.setup(storage)
+
+			manager := NewPullRequestManager(storage)
+			result, err := manager.SubmitReview(tt.prID, tt.review)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if tt.validate != nil {
+				tt.validate(t, result)
+			}
+		})
+	}
+}
+
+func TestPullRequestManager_UserPendingReviews(t *testing.T) {
+	storage := createMockStorage()
+	storage.PullRequestStorage.Create(createTestPR("pr1", "Pending", "user1", domain.Open, []string{"user2"}))
+	storage.PullRequestStorage.Create(createTestPR("pr2", "Approved", "user1", domain.Open, []string{"user2"}))
+	storage.PullRequestStorage.Create(createTestPR("pr3", "Merged", "user1", domain.Merged, []string{"user2"}))
+	storage.PullRequestStorage.SubmitReview("pr2", domain.Review{ReviewerID: "user2", State: domain.ReviewApproved})
+
+	manager := NewPullRequestManager(storage)
+	prs, err := manager.UserPendingReviews("user2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].ID != "pr1" {
+		t.Errorf("expected only pr1 to be pending, got %+v", prs)
+	}
+}
+
+func TestPullRequestManager_MergePolicy(t *testing.T) {
+	approved := func(reviewerID string) domain.Review {
+		return domain.Review{ReviewerID: reviewerID, State: domain.ReviewApproved}
+	}
+
+	tests := []struct {
+		name       string
+		policy     domain.MergePolicy
+		reviews    []domain.Review
+		mergedBy   string
+		override   *string
+		wantUnmet  []string
+		wantMerged bool
+	}{
+		{
+			name:       "no policy merges without reviews",
+			wantMerged: true,
+		},
+		{
+			name:      "not enough approvals",
+			policy:    domain.MergePolicy{MinApprovals: 2},
+			reviews:   []domain.Review{approved("user2")},
+			wantUnmet: []string{"requires 2 approvals, got 1"},
+		},
+		{
+			name:       "enough approvals",
+			policy:     domain.MergePolicy{MinApprovals: 2},
+			reviews:    []domain.Review{approved("user2"), approved("user3")},
+			wantMerged: true,
+		},
+		{
+			name:   "outstanding changes requested",
+			policy: domain.MergePolicy{MinApprovals: 1, BlockOnChangesRequested: true},
+			reviews: []domain.Review{
+				approved("user2"),
+				{ReviewerID: "user3", State: domain.ReviewChangesRequested},
+			},
+			wantUnmet: []string{"changes requested by user3"},
+		},
+		{
+			name:      "author cannot merge own PR",
+			policy:    domain.MergePolicy{MinApprovals: 1, ForbidSelfApproval: true},
+			reviews:   []domain.Review{approved("user2")},
+			mergedBy:  "user1",
+			wantUnmet: []string{"author cannot merge own pull request"},
+		},
+		{
+			name:      "unknown user merges PR with self approval forbidden",
+			policy:    domain.MergePolicy{MinApprovals: 1, ForbidSelfApproval: true},
+			reviews:   []domain.Review{approved("user2")},
+			wantUnmet: []string{"merging user must be known to rule out self-approval"},
+		},
+		{
+			name:      "author approval does not count",
+			policy:    domain.MergePolicy{MinApprovals: 1, ForbidSelfApproval: true},
+			reviews:   []domain.Review{approved("user1")},
+			mergedBy:  "user2",
+			wantUnmet: []string{"requires 1 approvals, got 0", "author cannot approve own pull request"},
+		},
+		{
+			name:       "reviewer merges PR with self approval forbidden",
+			policy:     domain.MergePolicy{MinApprovals: 1, ForbidSelfApproval: true},
+			reviews:    []domain.Review{approved("user2")},
+			mergedBy:   "user2",
+			wantMerged: true,
+		},
+		{
+			name:       "override merges and records unmet rules",
+			policy:     domain.MergePolicy{MinApprovals: 1},
+			override:   func() *string { reason := "hotfix"; return &reason }(),
+			wantMerged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			storage.UserStorage.Insert(createTestUser("user1", "Alice", "backend", true))
+			tt.policy.TeamName = "backend"
+			storage.PolicyStorage.Upsert(tt.policy)
+			pr := createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user3"})
+			pr.Reviews = tt.reviews
+			storage.PullRequestStorage.Create(pr)
+
+			manager := NewPullRequestManager(storage)
+			var mergedBy *string
+			if tt.mergedBy != "" {
+				mergedBy = &tt.mergedBy
+			}
+			var result domain.PullRequest
+			var err error
+			if tt.override != nil {
+				result, err = manager.ForceMergePullRequest(pr, mergedBy, *tt.override)
+			} else {
+				result, err = manager.MergePullRequest(pr, mergedBy)
+			}
+
+			if tt.wantUnmet != nil {
+				var policyErr *domain.PolicyError
+				if !errors.As(err, &policyErr) {
+					t.Fatalf("expected policy error, got %v", err)
+				}
+				if !errors.Is(err, domain.ErrPolicyNotSatisfied) {
+					t.Errorf("expected error to wrap ErrPolicyNotSatisfied")
+				}
+				if !slices.Equal(policyErr.UnmetRules, tt.wantUnmet) {
+					t.Errorf("expected unmet rules %v, got %v", tt.wantUnmet, policyErr.UnmetRules)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantMerged && result.Status != domain.Merged {
+				t.Errorf("expected status Merged, got %v", result.Status)
+			}
+
+			overrides := storage.PolicyStorage.(*mockPolicyStorage).overrides
+			if tt.override != nil {
+				if len(overrides) != 1 || overrides[0].Reason != *tt.override || len(overrides[0].UnmetRules) != 1 {
+					t.Errorf("expected override to be recorded, got %+v", overrides)
+				}
+			} else if len(overrides) != 0 {
+				t.Errorf("expected no overrides, got %+v", overrides)
+			}
+		})
+	}
+}
+
+func TestPullRequestManager_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   domain.PullRequestStatus
+		action   func(*PullRequestManager) (domain.PullRequest, error)
+		wantErr  error
+		validate func(*testing.T, domain.PullRequest)
+	}{
+		{
+			name:   "ready assigns reviewers to draft",
+			status: domain.Draft,
+			action: func(m *PullRequestManager) (domain.PullRequest, error) { return m.MarkReady("pr1") },
+			validate: func(t *testing.T, pr domain.PullRequest) {
+				if pr.Status != domain.Open || len(pr.AssignedReviewers) != 2 {
+					t.Errorf("expected open PR with 2 reviewers, got %v %v", pr.Status, pr.AssignedReviewers)
+				}
+			},
+		},
+		{
+			name:    "ready on closed PR",
+			status:  domain.Closed,
+			action:  func(m *PullRequestManager) (domain.PullRequest, error) { return m.MarkReady("pr1") },
+			wantErr: domain.ErrInvalidTransition,
+		},
+		{
+			name:   "close releases reviewers",
+			status: domain.Open,
+			action: func(m *PullRequestManager) (domain.PullRequest, error) { return m.ClosePullRequest("pr1") },
+			validate: func(t *testing.T, pr domain.PullRequest) {
+				if pr.Status != domain.Closed || len(pr.AssignedReviewers) != 0 {
+					t.Errorf("expected closed PR without reviewers, got %v %v", pr.Status, pr.AssignedReviewers)
+				}
+			},
+		},
+		{
+			name:   "close is idempotent",
+			status: domain.Closed,
+			action: func(m *PullRequestManager) (domain.PullRequest, error) { return m.ClosePullRequest("pr1") },
+			validate: func(t *testing.T, pr domain.PullRequest) {
+				if pr.Status != domain.Closed {
+					t.Errorf("expected status Closed, got %v", pr.Status)
+				}
+			},
+		},
+		{
+			name:    "close merged PR",
+			status:  domain.Merged,
+			action:  func(m *PullRequestManager) (domain.PullRequest, error) { return m.ClosePullRequest("pr1") },
+			wantErr: domain.ErrPRMerged,
+		},
+		{
+			name:   "reopen assigns new reviewers",
+			status: domain.Closed,
+			action: func(m *PullRequestManager) (domain.PullRequest, error) { return m.ReopenPullRequest("pr1") },
+			validate: func(t *testing.T, pr domain.PullRequest) {
+				if pr.Status != domain.Open || len(pr.AssignedReviewers) != 2 {
+					t.Errorf("expected open PR with 2 reviewers, got %v %v", pr.Status, pr.AssignedReviewers)
+				}
+			},
+		},
+		{
+			name:    "reopen draft",
+			status:  domain.Draft,
+			action:  func(m *PullRequestManager) (domain.PullRequest, error) { return m.ReopenPullRequest("pr1") },
+			wantErr: domain.ErrInvalidTransition,
+		},
+		{
+			name:   "merge draft",
+			status: domain.Draft,
+			action: func(m *PullRequestManager) (domain.PullRequest, error) {
+				return m.MergePullRequest(createTestPR("pr1", "Test PR", "user1", domain.Draft, nil), nil)
+			},
+			wantErr: domain.ErrInvalidTransition,
+		},
+		{
+			name:   "reassign on closed PR",
+			status: domain.Closed,
+			action: func(m *PullRequestManager) (domain.PullRequest, error) {
+				pr, _, err := m.ReassignPullRequest("pr1", "user2")
+				return pr, err
+			},
+			wantErr: domain.ErrPRNotOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			for _, userID := range []string{"user1", "user2", "user3"} {
+				storage.UserStorage.Insert(createTestUser(userID, userID, "team1", true))
+			}
+			storage.TeamStorage.Insert(createTestTeam("team1", []domain.TeamMember{
+				{UserID: "user1", Username: "user1", IsActive: true},
+				{UserID: "user2", Username: "user2", IsActive: true},
+				{UserID: "user3", Username: "user3", IsActive: true},
+			}))
+			var reviewers []string
+			if tt.status == domain.Open || tt.status == domain.Merged {
+				reviewers = []string{"user2", "user3"}
+			}
+			storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", tt.status, reviewers))
+
+			result, err := tt.action(NewPullRequestManager(storage))
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.validate != nil {
+				tt.validate(t, result)
+			}
+		})
+	}
+}
+
 func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -301,7 +667,7 @@ func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 			name: "successfully reassign reviewer",
 			setup: func(storage *db.Storage) {
 				// Create PR with 2 reviewers
-				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, "[user2, user3]")
+				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user3"})
 				storage.PullRequestStorage.Create(pr)
 
 				// Create team for old reviewer (user2)
@@ -325,18 +691,16 @@ func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 					t.Errorf("expected status Open, got %v", pr.Status)
 				}
 				// Should not contain old reviewer
-				if strings.Contains(pr.AssignedReviewers, "user2") {
+				if slices.Contains(pr.AssignedReviewers, "user2") {
 					t.Error("old reviewer user2 should not be in reviewers")
 				}
 				// Should contain the other reviewer (user3)
-				if !strings.Contains(pr.AssignedReviewers, "user3") {
+				if !slices.Contains(pr.AssignedReviewers, "user3") {
 					t.Error("expected user3 to remain in reviewers")
 				}
 				// Should have a new reviewer from user2's team
-				reviewers := strings.Trim(pr.AssignedReviewers, "[]")
-				reviewerList := strings.Split(reviewers, ",")
-				if len(reviewerList) != 2 {
-					t.Errorf("expected 2 reviewers, got %d", len(reviewerList))
+				if len(pr.AssignedReviewers) != 2 {
+					t.Errorf("expected 2 reviewers, got %d", len(pr.AssignedReviewers))
 				}
 			},
 		},
@@ -344,7 +708,7 @@ func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 			name: "reassign when only one reviewer exists",
 			setup: func(storage *db.Storage) {
 				// Create PR with 1 reviewer
-				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, "[user2]")
+				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2"})
 				storage.PullRequestStorage.Create(pr)
 
 				// Create team for old reviewer (user2)
@@ -363,14 +727,12 @@ func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 			oldReviewerID: "user2",
 			validate: func(t *testing.T, pr domain.PullRequest, storage *db.Storage) {
 				// Should not contain old reviewer
-				if strings.Contains(pr.AssignedReviewers, "user2") {
+				if slices.Contains(pr.AssignedReviewers, "user2") {
 					t.Error("old reviewer user2 should not be in reviewers")
 				}
 				// Should have a new reviewer
-				reviewers := strings.Trim(pr.AssignedReviewers, "[]")
-				reviewerList := strings.Split(reviewers, ",")
-				if len(reviewerList) != 1 {
-					t.Errorf("expected 1 reviewer, got %d", len(reviewerList))
+				if len(pr.AssignedReviewers) != 1 {
+					t.Errorf("expected 1 reviewer, got %d", len(pr.AssignedReviewers))
 				}
 			},
 		},
@@ -378,7 +740,7 @@ func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 			name: "fail to reassign merged PR",
 			setup: func(storage *db.Storage) {
 				// Create merged PR
-				pr := createTestPR("pr1", "Test PR", "user1", domain.Merged, "[user2, user3]")
+				pr := createTestPR("pr1", "Test PR", "user1", domain.Merged, []string{"user2", "user3"})
 				pr.Status = domain.Merged
 				storage.PullRequestStorage.Create(pr)
 			},
@@ -391,7 +753,7 @@ func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 			name: "reassign when no available replacement (only old reviewer in team)",
 			setup: func(storage *db.Storage) {
 				// Create PR with 2 reviewers
-				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, "[user2, user3]")
+				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user3"})
 				storage.PullRequestStorage.Create(pr)
 
 				// Create team with only old reviewer (no replacement available)
@@ -409,16 +771,14 @@ func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 			oldReviewerID: "user2",
 			validate: func(t *testing.T, pr domain.PullRequest, storage *db.Storage) {
 				// Should not contain old reviewer
-				if strings.Contains(pr.AssignedReviewers, "user2") {
+				if slices.Contains(pr.AssignedReviewers, "user2") {
 					t.Error("old reviewer user2 should not be in reviewers")
 				}
 				// Should only have the other reviewer (user3)
-				reviewers := strings.Trim(pr.AssignedReviewers, "[]")
-				reviewerList := strings.Split(reviewers, ",")
-				if len(reviewerList) != 1 {
-					t.Errorf("expected 1 reviewer, got %d", len(reviewerList))
+				if len(pr.AssignedReviewers) != 1 {
+					t.Errorf("expected 1 reviewer, got %d", len(pr.AssignedReviewers))
 				}
-				if !strings.Contains(pr.AssignedReviewers, "user3") {
+				if !slices.Contains(pr.AssignedReviewers, "user3") {
 					t.Error("expected user3 to remain in reviewers")
 				}
 			},
@@ -427,7 +787,7 @@ func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 			name: "reassign excludes author and other reviewer from new assignment",
 			setup: func(storage *db.Storage) {
 				// Create PR with 2 reviewers
-				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, "[user2, user3]")
+				pr := createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user3"})
 				storage.PullRequestStorage.Create(pr)
 
 				// Create team with multiple members
@@ -450,21 +810,19 @@ func TestPullRequestManager_ReassignPullRequest(t *testing.T) {
 			oldReviewerID: "user2",
 			validate: func(t *testing.T, pr domain.PullRequest, storage *db.Storage) {
 				// Should not contain old reviewer, author, or other reviewer
-				if strings.Contains(pr.AssignedReviewers, "user2") {
+				if slices.Contains(pr.AssignedReviewers, "user2") {
 					t.Error("old reviewer user2 should not be in reviewers")
 				}
-				if strings.Contains(pr.AssignedReviewers, "user1") {
+				if slices.Contains(pr.AssignedReviewers, "user1") {
 					t.Error("author user1 should not be in reviewers")
 				}
 				// Should contain user3 (the other reviewer)
-				if !strings.Contains(pr.AssignedReviewers, "user3") {
+				if !slices.Contains(pr.AssignedReviewers, "user3") {
 					t.Error("expected user3 to remain in reviewers")
 				}
 				// New reviewer should be user4 or user5 (not user1, user2, or user3)
-				reviewers := strings.Trim(pr.AssignedReviewers, "[]")
-				reviewerList := strings.Split(reviewers, ",")
-				if len(reviewerList) != 2 {
-					t.Errorf("expected 2 reviewers, got %d", len(reviewerList))
+				if len(pr.AssignedReviewers) != 2 {
+					t.Errorf("expected 2 reviewers, got %d", len(pr.AssignedReviewers))
 				}
 			},
 		},
@@ -521,7 +879,7 @@ func TestPullRequestManager_HelperMethods(t *testing.T) {
 		storage.UserStorage.Insert(createTestUser("user4", "reviewer3", "team1", true))
 
 		manager := NewPullRequestManager(storage)
-		pr := createTestPR("pr1", "Test PR", "user1", domain.Open, "")
+		pr := createTestPR("pr1", "Test PR", "user1", domain.Open, nil)
 		result, err := manager.CreatePullRequest(pr)
 
 		if err != nil {
@@ -530,11 +888,11 @@ func TestPullRequestManager_HelperMethods(t *testing.T) {
 		}
 
 		// Verify that inactive user3 is not assigned
-		if strings.Contains(result.AssignedReviewers, "user3") {
+		if slices.Contains(result.AssignedReviewers, "user3") {
 			t.Error("inactive user3 should not be assigned")
 		}
 		// Verify that active users are assigned (but not author)
-		if strings.Contains(result.AssignedReviewers, "user1") {
+		if slices.Contains(result.AssignedReviewers, "user1") {
 			t.Error("author user1 should not be assigned")
 		}
 	})
@@ -557,7 +915,7 @@ func TestPullRequestManager_HelperMethods(t *testing.T) {
 		storage.UserStorage.Insert(createTestUser("user4", "reviewer3", "team1", true))
 
 		manager := NewPullRequestManager(storage)
-		pr := createTestPR("pr1", "Test PR", "user1", domain.Open, "")
+		pr := createTestPR("pr1", "Test PR", "user1", domain.Open, nil)
 		result, err := manager.CreatePullRequest(pr)
 
 		if err != nil {
@@ -566,14 +924,12 @@ func TestPullRequestManager_HelperMethods(t *testing.T) {
 		}
 
 		// Verify that author is filtered out
-		if strings.Contains(result.AssignedReviewers, "user1") {
+		if slices.Contains(result.AssignedReviewers, "user1") {
 			t.Error("author user1 should be filtered out")
 		}
 		// Should assign 2 reviewers from user2, user3, user4
-		reviewers := strings.Trim(result.AssignedReviewers, "[]")
-		reviewerList := strings.Split(reviewers, ",")
-		if len(reviewerList) != 2 {
-			t.Errorf("expected 2 reviewers, got %d", len(reviewerList))
+		if len(result.AssignedReviewers) != 2 {
+			t.Errorf("expected 2 reviewers, got %d", len(result.AssignedReviewers))
 		}
 	})
 }
@@ -0,0 +1,173 @@
+package manager
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/storager"
+)
+
+type SelectionStrategy string
+
+const (
+	RandomSelection      SelectionStrategy = "random"
+	RoundRobinSelection  SelectionStrategy = "round_robin"
+	LeastLoadedSelection SelectionStrategy = "least_loaded"
+	WeightedSelection    SelectionStrategy = "weighted"
+)
+
+func ParseSelectionStrategy(strategy string) (SelectionStrategy, error) {
+	switch SelectionStrategy(strategy) {
+	case RandomSelection, RoundRobinSelection, LeastLoadedSelection, WeightedSelection:
+		return SelectionStrategy(strategy), nil
+	}
+	return "", domain.ErrUnknownStrategy
+}
+
+// ReviewerSelector picks up to count reviewers out of candidates.
+// Candidates are already filtered: active, not the author, not assigned yet.
+type ReviewerSelector interface {
+	SelectReviewers(teamName string, candidates []string, count int) ([]string, error)
+}
+
+// ReviewerSelection resolves the selector used for a team, falling back to Default.
+type ReviewerSelection struct {
+	Default ReviewerSelector
+	Teams   map[string]ReviewerSelector
+}
+
+func (s ReviewerSelection) ForTeam(teamName string) ReviewerSelector {
+	if selector, ok := s.Teams[teamName]; ok {
+		return selector
+	}
+	if s.Default != nil {
+		return s.Default
+	}
+	return NewRandomSelector()
+}
+
+type RandomSelector struct{}
+
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{}
+}
+
+func (s *RandomSelector) SelectReviewers(teamName string, candidates []string, count int) ([]string, error) {
+	shuffled := slices.Clone(candidates)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled[:min(count, len(shuffled))], nil
+}
+
+// RoundRobinSelector walks the team members in user ID order, continuing
+// after the last reviewer it picked for the team.
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{last: make(map[string]string)}
+}
+
+func (s *RoundRobinSelector) SelectReviewers(teamName string, candidates []string, count int) ([]string, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return []string{}, nil
+	}
+	sorted := slices.Sorted(slices.Values(candidates))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := 0
+	if last, ok := s.last[teamName]; ok {
+		position, found := slices.BinarySearch(sorted, last)
+		if found {
+			position++
+		}
+		start = position % len(sorted)
+	}
+
+	selected := make([]string, 0, min(count, len(sorted)))
+	for i := range min(count, len(sorted)) {
+		selected = append(selected, sorted[(start+i)%len(sorted)])
+	}
+	s.last[teamName] = selected[len(selected)-1]
+	return selected, nil
+}
+
+// LeastLoadedSelector prefers candidates with the fewest open reviews.
+// Ties are broken randomly.
+type LeastLoadedSelector struct {
+	Storage storager.UserPullRequestReviewsCounter
+}
+
+func NewLeastLoadedSelector(storage storager.UserPullRequestReviewsCounter) *LeastLoadedSelector {
+	return &LeastLoadedSelector{Storage: storage}
+}
+
+func (s *LeastLoadedSelector) SelectReviewers(teamName string, candidates []string, count int) ([]string, error) {
+	if len(candidates) == 0 || count <= 0 {
+		return []string{}, nil
+	}
+	loads, err := s.Storage.CountUserOpenReviews(candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	shuffled := slices.Clone(candidates)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	slices.SortStableFunc(shuffled, func(a, b string) int {
+		return cmp.Compare(loads[a], loads[b])
+	})
+	return shuffled[:min(count, len(shuffled))], nil
+}
+
+// WeightedSelector draws reviewers at random proportionally to their weight.
+// Users without a configured weight get weight 1, users with weight <= 0 are never picked.
+type WeightedSelector struct {
+	Weights map[string]float64
+}
+
+func NewWeightedSelector(weights map[string]float64) *WeightedSelector {
+	return &WeightedSelector{Weights: weights}
+}
+
+func (s *WeightedSelector) SelectReviewers(teamName string, candidates []string, count int) ([]string, error) {
+	type keyedCandidate struct {
+		userID string
+		key    float64
+	}
+
+	keyed := make([]keyedCandidate, 0, len(candidates))
+	for _, userID := range candidates {
+		weight, ok := s.Weights[userID]
+		if !ok {
+			weight = 1
+		}
+		if weight <= 0 {
+			continue
+		}
+		// Efraimidis-Spirakis: taking the largest u^(1/w) keys samples without replacement.
+		keyed = append(keyed, keyedCandidate{
+			userID: userID,
+			key:    math.Pow(rand.Float64(), 1/weight),
+		})
+	}
+	slices.SortFunc(keyed, func(a, b keyedCandidate) int {
+		return cmp.Compare(b.key, a.key)
+	})
+
+	selected := make([]string, 0, min(count, len(keyed)))
+	for _, candidate := range keyed[:min(count, len(keyed))] {
+		selected = append(selected, candidate.userID)
+	}
+	return selected, nil
+}
@@ -0,0 +1,270 @@
+package manager
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+func TestParseSelectionStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		want     SelectionStrategy
+		wantErr  bool
+	}{
+		{name: "random", strategy: "random", want: RandomSelection},
+		{name: "round robin", strategy: "round_robin", want: RoundRobinSelection},
+		{name: "least loaded", strategy: "least_loaded", want: LeastLoadedSelection},
+		{name: "weighted", strategy: "weighted", want: WeightedSelection},
+		{name: "unknown", strategy: "fastest", wantErr: true},
+		{name: "empty", strategy: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSelectionStrategy(tt.strategy)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRandomSelector_SelectReviewers(t *testing.T) {
+	selector := NewRandomSelector()
+	candidates := []string{"user1", "user2", "user3"}
+
+	selected, err := selector.SelectReviewers("team1", candidates, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selected) != 2 {
+		t.Fatalf("expected 2 reviewers, got %d", len(selected))
+	}
+	if selected[0] == selected[1] {
+		t.Errorf("expected distinct reviewers, got %v", selected)
+	}
+	for _, reviewer := range selected {
+		if !contains(candidates, reviewer) {
+			t.Errorf("unexpected reviewer %s", reviewer)
+		}
+	}
+
+	selected, err = selector.SelectReviewers("team1", []string{"user1"}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selected) != 1 {
+		t.Errorf("expected 1 reviewer, got %d", len(selected))
+	}
+}
+
+func TestRoundRobinSelector_SelectReviewers(t *testing.T) {
+	selector := NewRoundRobinSelector()
+	candidates := []string{"user3", "user1", "user4", "user2"}
+
+	rounds := [][]string{
+		{"user1", "user2"},
+		{"user3", "user4"},
+		{"user1", "user2"},
+	}
+	for i, want := range rounds {
+		got, err := selector.SelectReviewers("team1", candidates, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("round %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	t.Run("teams rotate independently", func(t *testing.T) {
+		got, err := selector.SelectReviewers("team2", candidates, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, []string{"user1"}) {
+			t.Errorf("expected [user1], got %v", got)
+		}
+	})
+
+	t.Run("continues after last reviewer that left the candidates", func(t *testing.T) {
+		selector := NewRoundRobinSelector()
+		if _, err := selector.SelectReviewers("team1", []string{"user1", "user2", "user3"}, 2); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// user2 was the last pick and is excluded now, rotation continues from user3
+		got, err := selector.SelectReviewers("team1", []string{"user1", "user3"}, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, []string{"user3"}) {
+			t.Errorf("expected [user3], got %v", got)
+		}
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		got, err := selector.SelectReviewers("team1", nil, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("expected no reviewers, got %v", got)
+		}
+	})
+}
+
+func TestLeastLoadedSelector_SelectReviewers(t *testing.T) {
+	storage := newMockPullRequestStorage()
+	storage.Create(createTestPR("pr1", "PR 1", "author", domain.Open, []string{"user1", "user2"}))
+	storage.Create(createTestPR("pr2", "PR 2", "author", domain.Open, []string{"user1", "user3"}))
+	storage.Create(createTestPR("pr3", "PR 3", "author", domain.Open, []string{"user1"}))
+	// Merged PRs do not count towards the load
+	storage.Create(createTestPR("pr4", "PR 4", "author", domain.Merged, []string{"user4", "user2"}))
+
+	selector := NewLeastLoadedSelector(storage)
+	for range 20 {
+		got, err := selector.SelectReviewers("team1", []string{"user1", "user2", "user3", "user4"}, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 reviewers, got %v", got)
+		}
+		// user4 has no open reviews, user2 and user3 have one each and tie
+		if got[0] != "user4" {
+			t.Errorf("expected least loaded user4 first, got %v", got)
+		}
+		if got[1] != "user2" && got[1] != "user3" {
+			t.Errorf("expected user2 or user3 second, got %v", got)
+		}
+	}
+
+	t.Run("ties are broken randomly", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for range 100 {
+			got, err := selector.SelectReviewers("team1", []string{"user2", "user3"}, 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			seen[got[0]] = true
+		}
+		if !seen["user2"] || !seen["user3"] {
+			t.Errorf("expected both tied users to be picked at some point, got %v", seen)
+		}
+	})
+}
+
+func TestPullRequestManager_CreatePullRequestBalancesLoad(t *testing.T) {
+	storage := createMockStorage()
+	storage.TeamStorage.Insert(createTestTeam("team1", []domain.TeamMember{
+		{UserID: "user1", Username: "author", IsActive: true},
+		{UserID: "user2", Username: "reviewer1", IsActive: true},
+		{UserID: "user3", Username: "reviewer2", IsActive: true},
+		{UserID: "user4", Username: "reviewer3", IsActive: true},
+	}))
+	storage.UserStorage.Insert(createTestUser("user1", "author", "team1", true))
+
+	manager := NewPullRequestManager(storage)
+	manager.Selection = ReviewerSelection{Default: NewLeastLoadedSelector(storage.PullRequestStorage)}
+
+	for i := range 6 {
+		pr := createTestPR(fmt.Sprintf("pr%d", i), "PR", "user1", domain.Open, nil)
+		if _, err := manager.CreatePullRequest(pr); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	counts, err := storage.PullRequestStorage.CountUserOpenReviews([]string{"user2", "user3", "user4"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, userID := range []string{"user2", "user3", "user4"} {
+		if counts[userID] != 4 {
+			t.Errorf("expected 4 open reviews for %s, got %d", userID, counts[userID])
+		}
+	}
+}
+
+func TestWeightedSelector_SelectReviewers(t *testing.T) {
+	selector := NewWeightedSelector(map[string]float64{
+		"user1": 0,
+		"user2": 5,
+	})
+
+	for range 20 {
+		got, err := selector.SelectReviewers("team1", []string{"user1", "user2", "user3"}, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 reviewers, got %v", got)
+		}
+		if contains(got, "user1") {
+			t.Errorf("user1 has zero weight and should never be selected, got %v", got)
+		}
+	}
+}
+
+func TestReviewerSelection_ForTeam(t *testing.T) {
+	roundRobin := NewRoundRobinSelector()
+	selection := ReviewerSelection{
+		Default: NewRandomSelector(),
+		Teams:   map[string]ReviewerSelector{"team1": roundRobin},
+	}
+
+	if selection.ForTeam("team1") != roundRobin {
+		t.Error("expected team1 to use its own selector")
+	}
+	if _, ok := selection.ForTeam("team2").(*RandomSelector); !ok {
+		t.Error("expected team2 to fall back to the default selector")
+	}
+	if _, ok := (ReviewerSelection{}).ForTeam("team1").(*RandomSelector); !ok {
+		t.Error("expected empty selection to fall back to random")
+	}
+}
+
+func TestPullRequestManager_CreatePullRequestUsesTeamSelector(t *testing.T) {
+	storage := createMockStorage()
+	storage.TeamStorage.Insert(createTestTeam("team1", []domain.TeamMember{
+		{UserID: "user1", Username: "author", IsActive: true},
+		{UserID: "user2", Username: "reviewer1", IsActive: true},
+		{UserID: "user3", Username: "reviewer2", IsActive: true},
+		{UserID: "user4", Username: "reviewer3", IsActive: true},
+	}))
+	storage.UserStorage.Insert(createTestUser("user1", "author", "team1", true))
+
+	manager := NewPullRequestManager(storage)
+	manager.Selection = ReviewerSelection{
+		Teams: map[string]ReviewerSelector{"team1": NewRoundRobinSelector()},
+	}
+
+	first, err := manager.CreatePullRequest(createTestPR("pr1", "PR 1", "user1", domain.Open, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(first.AssignedReviewers, []string{"user2", "user3"}) {
+		t.Errorf("expected [user2 user3], got %v", first.AssignedReviewers)
+	}
+
+	second, err := manager.CreatePullRequest(createTestPR("pr2", "PR 2", "user1", domain.Open, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(second.AssignedReviewers, []string{"user4", "user2"}) {
+		t.Errorf("expected [user4 user2], got %v", second.AssignedReviewers)
+	}
+}
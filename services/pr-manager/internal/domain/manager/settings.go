@@ -0,0 +1,113 @@
+package manager
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/storager"
+)
+
+type SettingsManager struct {
+	TeamStorage     storager.TeamStorager
+	SettingsStorage storager.TeamSettingsStorager
+	PoolStorage     storager.ReviewerPoolStorager
+	UserStorage     storager.UserStorager
+}
+
+func NewSettingsManager(teamStorage storager.TeamStorager, settingsStorage storager.TeamSettingsStorager, poolStorage storager.ReviewerPoolStorager, userStorage storager.UserStorager) *SettingsManager {
+	return &SettingsManager{TeamStorage: teamStorage, SettingsStorage: settingsStorage, PoolStorage: poolStorage, UserStorage: userStorage}
+}
+
+// SetTeamSettings replaces the team settings. A reviewer pool takes one
+// reviewer from it unless PoolReviewersRequired says otherwise; without a pool
+// PoolReviewersRequired is ignored. A review SLA reassigns stale reviews unless
+// SLAAction says otherwise; escalation needs a team lead.
+func (m *SettingsManager) SetTeamSettings(settings domain.TeamSettings) (domain.TeamSettings, error) {
+	if settings.ReviewerPool != nil && *settings.ReviewerPool == "" {
+		settings.ReviewerPool = nil
+	}
+	if settings.ReviewerPool == nil {
+		settings.PoolReviewersRequired = 0
+	} else if settings.PoolReviewersRequired == 0 {
+		settings.PoolReviewersRequired = 1
+	}
+	if !domain.ValidReviewersCount(settings.ReviewersRequired) {
+		return domain.TeamSettings{}, domain.ErrInvalidReviewers
+	}
+	if settings.ReviewerPool != nil && !domain.ValidReviewersCount(settings.PoolReviewersRequired) {
+		return domain.TeamSettings{}, domain.ErrInvalidReviewers
+	}
+	if settings.TeamLead != nil && *settings.TeamLead == "" {
+		settings.TeamLead = nil
+	}
+	if settings.ReviewSLAHours == 0 {
+		settings.SLAAction = ""
+	} else if settings.SLAAction == "" {
+		settings.SLAAction = domain.SLAReassign
+	}
+	if err := validateSLA(settings); err != nil {
+		return domain.TeamSettings{}, err
+	}
+	if err := ensureTeamExists(m.TeamStorage, settings.TeamName); err != nil {
+		return domain.TeamSettings{}, err
+	}
+	if settings.TeamLead != nil {
+		users, err := m.UserStorage.Select(settings.TeamLead)
+		if err != nil {
+			return domain.TeamSettings{}, err
+		}
+		if len(users) == 0 {
+			return domain.TeamSettings{}, domain.ErrUserNotFound
+		}
+	}
+	if settings.ReviewerPool != nil {
+		pools, err := m.PoolStorage.Select(*settings.ReviewerPool)
+		if err != nil {
+			return domain.TeamSettings{}, err
+		}
+		if len(pools) == 0 {
+			return domain.TeamSettings{}, domain.ErrPoolNotFound
+		}
+	}
+
+	err := m.SettingsStorage.Upsert(settings)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+	return settings, nil
+}
+
+// GetTeamSettings returns the team settings, or the default ones if they were
+// never set.
+func (m *SettingsManager) GetTeamSettings(teamName string) (domain.TeamSettings, error) {
+	if err := ensureTeamExists(m.TeamStorage, teamName); err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	settings, err := m.SettingsStorage.Select(teamName)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+	if len(settings) == 0 {
+		return domain.TeamSettings{TeamName: teamName, ReviewersRequired: domain.DefaultReviewersRequired}, nil
+	}
+	return settings[0], nil
+}
+
+func validateSLA(settings domain.TeamSettings) error {
+	if settings.ReviewSLAHours < 0 {
+		return domain.ErrInvalidSLA
+	}
+	if settings.ReviewSLAHours == 0 {
+		return nil
+	}
+	switch settings.SLAAction {
+	case domain.SLAReassign:
+		return nil
+	case domain.SLAEscalate:
+		if settings.TeamLead == nil {
+			return domain.ErrInvalidSLA
+		}
+		return nil
+	default:
+		return domain.ErrInvalidSLA
+	}
+}
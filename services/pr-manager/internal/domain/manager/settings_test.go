@@ -0,0 +1,244 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+)
+
+func setupLargeTeamStorage(storage *db.Storage) {
+	members := []domain.TeamMember{
+		{UserID: "user1", Username: "author", IsActive: true},
+		{UserID: "user2", Username: "reviewer1", IsActive: true},
+		{UserID: "user3", Username: "reviewer2", IsActive: true},
+		{UserID: "user4", Username: "reviewer3", IsActive: true},
+		{UserID: "user5", Username: "reviewer4", IsActive: true},
+	}
+	storage.TeamStorage.Insert(createTestTeam("backend", members))
+	for _, member := range members {
+		storage.UserStorage.Insert(createTestUser(member.UserID, member.Username, "backend", member.IsActive))
+	}
+}
+
+func TestSettingsManager_TeamSettings(t *testing.T) {
+	storage := createMockStorage()
+	setupLargeTeamStorage(storage)
+	settingsManager := NewSettingsManager(storage.TeamStorage, storage.SettingsStorage, storage.PoolStorage, storage.UserStorage)
+
+	settings, err := settingsManager.GetTeamSettings("backend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.ReviewersRequired != domain.DefaultReviewersRequired {
+		t.Errorf("expected default %d reviewers, got %d", domain.DefaultReviewersRequired, settings.ReviewersRequired)
+	}
+
+	_, err = settingsManager.SetTeamSettings(domain.TeamSettings{TeamName: "backend", ReviewersRequired: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	settings, err = settingsManager.GetTeamSettings("backend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.ReviewersRequired != 3 {
+		t.Errorf("expected 3 reviewers, got %d", settings.ReviewersRequired)
+	}
+
+	for _, count := range []int{0, domain.MaxReviewersRequired + 1} {
+		_, err = settingsManager.SetTeamSettings(domain.TeamSettings{TeamName: "backend", ReviewersRequired: count})
+		if !errors.Is(err, domain.ErrInvalidReviewers) {
+			t.Errorf("expected ErrInvalidReviewers for %d, got %v", count, err)
+		}
+	}
+
+	_, err = settingsManager.SetTeamSettings(domain.TeamSettings{TeamName: "unknown", ReviewersRequired: 1})
+	if !errors.Is(err, domain.ErrTeamNotFound) {
+		t.Errorf("expected ErrTeamNotFound, got %v", err)
+	}
+	_, err = settingsManager.GetTeamSettings("unknown")
+	if !errors.Is(err, domain.ErrTeamNotFound) {
+		t.Errorf("expected ErrTeamNotFound, got %v", err)
+	}
+}
+
+func TestSettingsManager_ReviewSLA(t *testing.T) {
+	lead := "user5"
+	unknown := "unknown"
+
+	tests := []struct {
+		name       string
+		settings   domain.TeamSettings
+		wantAction domain.SLAAction
+		wantErr    error
+	}{
+		{
+			name:       "reassign by default",
+			settings:   domain.TeamSettings{ReviewSLAHours: 24},
+			wantAction: domain.SLAReassign,
+		},
+		{
+			name:       "escalate to team lead",
+			settings:   domain.TeamSettings{ReviewSLAHours: 24, SLAAction: domain.SLAEscalate, TeamLead: &lead},
+			wantAction: domain.SLAEscalate,
+		},
+		{
+			name:     "action is ignored without SLA",
+			settings: domain.TeamSettings{SLAAction: domain.SLAEscalate},
+		},
+		{
+			name:     "escalate without team lead",
+			settings: domain.TeamSettings{ReviewSLAHours: 24, SLAAction: domain.SLAEscalate},
+			wantErr:  domain.ErrInvalidSLA,
+		},
+		{
+			name:     "unknown action",
+			settings: domain.TeamSettings{ReviewSLAHours: 24, SLAAction: "notify"},
+			wantErr:  domain.ErrInvalidSLA,
+		},
+		{
+			name:     "negative SLA",
+			settings: domain.TeamSettings{ReviewSLAHours: -1},
+			wantErr:  domain.ErrInvalidSLA,
+		},
+		{
+			name:     "unknown team lead",
+			settings: domain.TeamSettings{ReviewSLAHours: 24, TeamLead: &unknown},
+			wantErr:  domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			setupLargeTeamStorage(storage)
+			settingsManager := NewSettingsManager(storage.TeamStorage, storage.SettingsStorage, storage.PoolStorage, storage.UserStorage)
+
+			tt.settings.TeamName = "backend"
+			tt.settings.ReviewersRequired = 2
+			settings, err := settingsManager.SetTeamSettings(tt.settings)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if settings.SLAAction != tt.wantAction {
+				t.Errorf("expected action %q, got %q", tt.wantAction, settings.SLAAction)
+			}
+		})
+	}
+}
+
+func TestPullRequestManager_CreatePullRequestReviewersCount(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name          string
+		teamRequired  int
+		prCount       *int
+		wantReviewers int
+		wantErr       error
+	}{
+		{
+			name:          "default count",
+			wantReviewers: domain.DefaultReviewersRequired,
+		},
+		{
+			name:          "team setting",
+			teamRequired:  3,
+			wantReviewers: 3,
+		},
+		{
+			name:          "pull request overrides team setting",
+			teamRequired:  3,
+			prCount:       intPtr(1),
+			wantReviewers: 1,
+		},
+		{
+			name:          "more than available candidates",
+			prCount:       intPtr(10),
+			wantReviewers: 4,
+		},
+		{
+			name:    "invalid count",
+			prCount: intPtr(0),
+			wantErr: domain.ErrInvalidReviewers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			setupLargeTeamStorage(storage)
+			if tt.teamRequired != 0 {
+				storage.SettingsStorage.Upsert(domain.TeamSettings{TeamName: "backend", ReviewersRequired: tt.teamRequired})
+			}
+			m := NewPullRequestManager(storage)
+
+			pr := createTestPR("pr1", "Test PR", "user1", domain.Open, nil)
+			pr.ReviewersCount = tt.prCount
+			result, err := m.CreatePullRequest(pr)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.AssignedReviewers) != tt.wantReviewers {
+				t.Errorf("expected %d reviewers, got %v", tt.wantReviewers, result.AssignedReviewers)
+			}
+		})
+	}
+}
+
+func TestPullRequestManager_ReassignPullRequestKeepsOtherReviewers(t *testing.T) {
+	storage := createMockStorage()
+	setupLargeTeamStorage(storage)
+	storage.PullRequestStorage.Create(createTestPR("pr1", "Test PR", "user1", domain.Open, []string{"user2", "user3", "user4"}))
+	m := NewPullRequestManager(storage)
+
+	pr, replacedBy, err := m.ReassignPullRequest("pr1", "user2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replacedBy != "user5" {
+		t.Errorf("expected user5 to replace user2, got %q", replacedBy)
+	}
+	slices.Sort(pr.AssignedReviewers)
+	if !slices.Equal(pr.AssignedReviewers, []string{"user3", "user4", "user5"}) {
+		t.Errorf("unexpected reviewers %v", pr.AssignedReviewers)
+	}
+
+	// The released reviewer can take over again.
+	_, replacedBy, err = m.ReassignPullRequest("pr1", "user3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replacedBy != "user2" {
+		t.Errorf("expected user2 to replace user3, got %q", replacedBy)
+	}
+
+	// Every other member already reviews, so nobody can take over.
+	storage.PullRequestStorage.Create(createTestPR("pr2", "Test PR", "user1", domain.Open, []string{"user2", "user3", "user4", "user5"}))
+	pr, replacedBy, err = m.ReassignPullRequest("pr2", "user2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replacedBy != "" {
+		t.Errorf("expected no replacement, got %q", replacedBy)
+	}
+	slices.Sort(pr.AssignedReviewers)
+	if !slices.Equal(pr.AssignedReviewers, []string{"user3", "user4", "user5"}) {
+		t.Errorf("unexpected reviewers %v", pr.AssignedReviewers)
+	}
+}
@@ -1,8 +1,8 @@
 package manager
 
 import (
-	"fmt"
-	"strings"
+	"slices"
+	"time"
 
 	"github.com/zemld/pr-manager/pr-manager/internal/domain"
 	"github.com/zemld/pr-manager/pr-manager/internal/domain/storager"
@@ -40,77 +40,90 @@ func (m *TeamManager) GetTeams(teamName *string) ([]domain.Team, error) {
 	return m.TeamStorage.Select(teamName)
 }
 
-func (m *TeamManager) DeleteTeam(teamName string) error {
+// DeleteTeam deletes the team and unassigns its members from all pull
+// requests. It returns the unassigned reviewers by pull request ID.
+func (m *TeamManager) DeleteTeam(teamName string) (map[string][]string, error) {
 	teamNamePtr := &teamName
 	teams, err := m.TeamStorage.Select(teamNamePtr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(teams) == 0 {
-		return domain.ErrTeamNotFound
+		return nil, domain.ErrTeamNotFound
 	}
 	team := teams[0]
 
-	processedPRs := make(map[string]bool)
+	removed := make(map[string][]string)
 	for _, member := range team.Members {
 		prs, err := m.PullRequestStorage.SelectUserPullRequestsReviews(member.UserID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for _, pr := range prs {
-			if processedPRs[pr.ID] {
+			if _, ok := removed[pr.ID]; ok {
 				continue
 			}
-			processedPRs[pr.ID] = true
-
-			reviewers := parseReviewers(pr.AssignedReviewers)
-			updatedReviewers := make([]string, 0, len(reviewers))
-			for _, reviewer := range reviewers {
-				isTeamMember := false
-				for _, teamMember := range team.Members {
-					if reviewer == teamMember.UserID {
-						isTeamMember = true
-						break
-					}
-				}
-				if !isTeamMember {
-					updatedReviewers = append(updatedReviewers, reviewer)
-				}
-			}
 
-			if len(updatedReviewers) == 0 {
-				pr.AssignedReviewers = "[]"
-			} else {
-				pr.AssignedReviewers = fmt.Sprintf("[%s]", strings.Join(updatedReviewers, ", "))
-			}
+			var unassigned []string
+			pr.AssignedReviewers = slices.DeleteFunc(slices.Clone(pr.AssignedReviewers), func(reviewer string) bool {
+				isMember := slices.ContainsFunc(team.Members, func(member domain.TeamMember) bool {
+					return member.UserID == reviewer
+				})
+				if isMember {
+					unassigned = append(unassigned, reviewer)
+				}
+				return isMember
+			})
+			removed[pr.ID] = unassigned
 
-			err = m.PullRequestStorage.Reassign(pr)
+			err = m.PullRequestStorage.Reassign(pr, domain.AssignmentTeamDeleted)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
 	err = m.TeamStorage.Delete(teamName)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return removed, nil
 }
 
-func parseReviewers(reviewersStr string) []string {
-	reviewersStr = strings.Trim(reviewersStr, "[]")
-	if reviewersStr == "" {
-		return []string{}
+func (m *TeamManager) GetDeletedTeams() ([]domain.DeletedTeam, error) {
+	return m.TeamStorage.SelectDeleted(nil)
+}
+
+// RestoreTeam brings back the members the team had when it was deleted. A
+// team of the same name must not have been created since.
+func (m *TeamManager) RestoreTeam(teamName string) (domain.Team, error) {
+	teams, err := m.TeamStorage.Select(&teamName)
+	if err != nil {
+		return domain.Team{}, err
 	}
-	parts := strings.Split(reviewersStr, ",")
-	reviewers := make([]string, 0, len(parts))
-	for _, part := range parts {
-		reviewer := strings.TrimSpace(part)
-		if reviewer != "" {
-			reviewers = append(reviewers, reviewer)
-		}
+	if len(teams) > 0 {
+		return domain.Team{}, domain.ErrTeamExists
+	}
+	deletedTeams, err := m.TeamStorage.SelectDeleted(&teamName)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	if len(deletedTeams) == 0 {
+		return domain.Team{}, domain.ErrTeamNotFound
+	}
+
+	err = m.TeamStorage.Restore(teamName)
+	if err != nil {
+		return domain.Team{}, err
+	}
+	return m.GetTeam(&teamName)
+}
+
+// PurgeDeletedTeams hard-deletes teams deleted at least retention ago.
+func (m *TeamManager) PurgeDeletedTeams(retention time.Duration) ([]string, error) {
+	if retention < 0 {
+		return nil, domain.ErrInvalidRetention
 	}
-	return reviewers
+	return m.TeamStorage.Purge(retention)
 }
@@ -1,8 +1,12 @@
 package manager
 
 import (
+	"errors"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/zemld/pr-manager/pr-manager/internal/domain"
 )
@@ -179,12 +183,13 @@ func TestTeamManager_GetTeam(t *testing.T) {
 
 func TestTeamManager_DeleteTeam(t *testing.T) {
 	tests := []struct {
-		name     string
-		setup    func(*mockTeamStorage, *mockPullRequestStorage)
-		teamName string
-		wantErr  bool
-		errMsg   string
-		validate func(*testing.T, *mockTeamStorage, *mockPullRequestStorage)
+		name        string
+		setup       func(*mockTeamStorage, *mockPullRequestStorage)
+		teamName    string
+		wantErr     bool
+		errMsg      string
+		wantRemoved map[string][]string
+		validate    func(*testing.T, *mockTeamStorage, *mockPullRequestStorage)
 	}{
 		{
 			name: "successfully delete team without PRs",
@@ -218,9 +223,9 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 				teamStorage.Insert(team)
 
 				// Create PRs with reviewers from team1
-				pr1 := createTestPR("pr1", "PR 1", "author1", domain.Open, "[user1, user2]")
-				pr2 := createTestPR("pr2", "PR 2", "author2", domain.Open, "[user1, other_reviewer]")
-				pr3 := createTestPR("pr3", "PR 3", "author3", domain.Open, "[other_reviewer, another_reviewer]")
+				pr1 := createTestPR("pr1", "PR 1", "author1", domain.Open, []string{"user1", "user2"})
+				pr2 := createTestPR("pr2", "PR 2", "author2", domain.Open, []string{"user1", "other_reviewer"})
+				pr3 := createTestPR("pr3", "PR 3", "author3", domain.Open, []string{"other_reviewer", "another_reviewer"})
 				prStorage.Create(pr1)
 				prStorage.Create(pr2)
 				prStorage.Create(pr3)
@@ -249,8 +254,8 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 					t.Error("expected pr1 to exist")
 					return
 				}
-				if prs1[0].AssignedReviewers != "[]" {
-					t.Errorf("expected pr1 to have empty reviewers, got %s", prs1[0].AssignedReviewers)
+				if len(prs1[0].AssignedReviewers) != 0 {
+					t.Errorf("expected pr1 to have empty reviewers, got %v", prs1[0].AssignedReviewers)
 				}
 
 				prID2 := "pr2"
@@ -264,10 +269,10 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 					return
 				}
 				// pr2 should have only other_reviewer, user1 should be removed
-				if strings.Contains(prs2[0].AssignedReviewers, "user1") {
+				if slices.Contains(prs2[0].AssignedReviewers, "user1") {
 					t.Error("expected user1 to be removed from pr2 reviewers")
 				}
-				if !strings.Contains(prs2[0].AssignedReviewers, "other_reviewer") {
+				if !slices.Contains(prs2[0].AssignedReviewers, "other_reviewer") {
 					t.Error("expected other_reviewer to remain in pr2 reviewers")
 				}
 
@@ -282,8 +287,8 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 					t.Error("expected pr3 to exist")
 					return
 				}
-				if prs3[0].AssignedReviewers != "[other_reviewer, another_reviewer]" {
-					t.Errorf("expected pr3 reviewers to remain unchanged, got %s", prs3[0].AssignedReviewers)
+				if !slices.Equal(prs3[0].AssignedReviewers, []string{"other_reviewer", "another_reviewer"}) {
+					t.Errorf("expected pr3 reviewers to remain unchanged, got %v", prs3[0].AssignedReviewers)
 				}
 			},
 		},
@@ -296,7 +301,7 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 				teamStorage.Insert(team)
 
 				// Create merged PR with reviewer from team1
-				pr1 := createTestPR("pr1", "PR 1", "author1", domain.Merged, "[user1, user2]")
+				pr1 := createTestPR("pr1", "PR 1", "author1", domain.Merged, []string{"user1", "user2"})
 				prStorage.Create(pr1)
 				// Merge it
 				prStorage.Merge(pr1)
@@ -326,10 +331,10 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 					return
 				}
 				// user1 should be removed, user2 should remain
-				if strings.Contains(prs1[0].AssignedReviewers, "user1") {
+				if slices.Contains(prs1[0].AssignedReviewers, "user1") {
 					t.Error("expected user1 to be removed from pr1 reviewers")
 				}
-				if !strings.Contains(prs1[0].AssignedReviewers, "user2") {
+				if !slices.Contains(prs1[0].AssignedReviewers, "user2") {
 					t.Error("expected user2 to remain in pr1 reviewers")
 				}
 			},
@@ -353,10 +358,11 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 				teamStorage.Insert(team)
 
 				// Create PR with both team members as reviewers
-				pr1 := createTestPR("pr1", "PR 1", "author1", domain.Open, "[user1, user2, other_reviewer]")
+				pr1 := createTestPR("pr1", "PR 1", "author1", domain.Open, []string{"user1", "user2", "other_reviewer"})
 				prStorage.Create(pr1)
 			},
-			teamName: "team1",
+			teamName:    "team1",
+			wantRemoved: map[string][]string{"pr1": {"user1", "user2"}},
 			validate: func(t *testing.T, teamStorage *mockTeamStorage, prStorage *mockPullRequestStorage) {
 				// Verify team is deleted
 				teamName := "team1"
@@ -380,15 +386,18 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 					t.Error("expected pr1 to exist")
 					return
 				}
-				if strings.Contains(prs1[0].AssignedReviewers, "user1") {
+				if slices.Contains(prs1[0].AssignedReviewers, "user1") {
 					t.Error("expected user1 to be removed from pr1 reviewers")
 				}
-				if strings.Contains(prs1[0].AssignedReviewers, "user2") {
+				if slices.Contains(prs1[0].AssignedReviewers, "user2") {
 					t.Error("expected user2 to be removed from pr1 reviewers")
 				}
-				if !strings.Contains(prs1[0].AssignedReviewers, "other_reviewer") {
+				if !slices.Contains(prs1[0].AssignedReviewers, "other_reviewer") {
 					t.Error("expected other_reviewer to remain in pr1 reviewers")
 				}
+				if reasons := prStorage.reasons["pr1"]; !slices.Equal(reasons, []domain.AssignmentReason{domain.AssignmentTeamDeleted}) {
+					t.Errorf("expected reviewers to be removed as team_deleted, got %v", reasons)
+				}
 			},
 		},
 	}
@@ -400,7 +409,7 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 			tt.setup(teamStorage, prStorage)
 
 			manager := NewTeamManager(teamStorage, prStorage)
-			err := manager.DeleteTeam(tt.teamName)
+			removed, err := manager.DeleteTeam(tt.teamName)
 
 			if tt.wantErr {
 				if err == nil {
@@ -416,9 +425,94 @@ func TestTeamManager_DeleteTeam(t *testing.T) {
 				return
 			}
 
+			if tt.wantRemoved != nil && !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("expected removed reviewers %v, got %v", tt.wantRemoved, removed)
+			}
+
 			if tt.validate != nil {
 				tt.validate(t, teamStorage, prStorage)
 			}
 		})
 	}
 }
+
+func TestTeamManager_RestoreTeam(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*mockTeamStorage)
+		teamName string
+		wantErr  error
+	}{
+		{
+			name: "restore deleted team",
+			setup: func(storage *mockTeamStorage) {
+				storage.Insert(createTestTeam("team1", []domain.TeamMember{{UserID: "user1", Username: "member1", IsActive: true}}))
+				storage.Delete("team1")
+			},
+			teamName: "team1",
+		},
+		{
+			name: "team was re-created",
+			setup: func(storage *mockTeamStorage) {
+				storage.Insert(createTestTeam("team1", []domain.TeamMember{{UserID: "user1", Username: "member1", IsActive: true}}))
+				storage.Delete("team1")
+				storage.Insert(createTestTeam("team1", []domain.TeamMember{{UserID: "user2", Username: "member2", IsActive: true}}))
+			},
+			teamName: "team1",
+			wantErr:  domain.ErrTeamExists,
+		},
+		{
+			name:     "team was never deleted",
+			setup:    func(storage *mockTeamStorage) {},
+			teamName: "team1",
+			wantErr:  domain.ErrTeamNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := newMockTeamStorage()
+			tt.setup(storage)
+
+			manager := NewTeamManager(storage, nil)
+			team, err := manager.RestoreTeam(tt.teamName)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if team.TeamName != tt.teamName || len(team.Members) != 1 {
+				t.Errorf("expected restored team, got %+v", team)
+			}
+			if deleted, _ := storage.SelectDeleted(nil); len(deleted) != 0 {
+				t.Errorf("expected no deleted teams, got %+v", deleted)
+			}
+		})
+	}
+}
+
+func TestTeamManager_PurgeDeletedTeams(t *testing.T) {
+	storage := newMockTeamStorage()
+	storage.Insert(createTestTeam("team1", []domain.TeamMember{{UserID: "user1", Username: "member1", IsActive: true}}))
+	storage.Delete("team1")
+	manager := NewTeamManager(storage, nil)
+
+	if _, err := manager.PurgeDeletedTeams(-time.Hour); !errors.Is(err, domain.ErrInvalidRetention) {
+		t.Errorf("expected ErrInvalidRetention, got %v", err)
+	}
+
+	purged, err := manager.PurgeDeletedTeams(time.Hour)
+	if err != nil || len(purged) != 0 {
+		t.Errorf("expected recently deleted team to be kept, got %v, %v", purged, err)
+	}
+
+	purged, err = manager.PurgeDeletedTeams(0)
+	if err != nil || !slices.Equal(purged, []string{"team1"}) {
+		t.Errorf("expected team1 to be purged, got %v, %v", purged, err)
+	}
+}
@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/storager"
+)
+
+type WebhookManager struct {
+	WebhookStorage storager.WebhookStorager
+	// AllowPrivateTargets lets webhooks be posted to loopback, link-local and
+	// private addresses.
+	AllowPrivateTargets bool
+}
+
+func NewWebhookManager(webhookStorage storager.WebhookStorager) *WebhookManager {
+	return &WebhookManager{WebhookStorage: webhookStorage}
+}
+
+// Subscribe stores the subscription. It needs an absolute http(s) URL, at
+// least one event type, all of them known, and a secret to sign payloads with.
+// Unless private targets are allowed, the URL cannot name localhost or a
+// non-public address; host names resolving to one are refused on delivery.
+func (m *WebhookManager) Subscribe(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	target, err := url.Parse(subscription.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return domain.WebhookSubscription{}, domain.ErrInvalidWebhook
+	}
+	if !m.AllowPrivateTargets && privateHost(target.Hostname()) {
+		return domain.WebhookSubscription{}, domain.ErrInvalidWebhook
+	}
+	if len(subscription.Events) == 0 || subscription.Secret == "" {
+		return domain.WebhookSubscription{}, domain.ErrInvalidWebhook
+	}
+	events := make([]domain.WebhookEventType, 0, len(subscription.Events))
+	for _, event := range subscription.Events {
+		if !domain.ValidWebhookEventType(event) {
+			return domain.WebhookSubscription{}, domain.ErrInvalidWebhook
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	subscription.Events = events
+
+	return m.WebhookStorage.Insert(subscription)
+}
+
+func (m *WebhookManager) Unsubscribe(id int64) error {
+	if _, err := m.subscription(id); err != nil {
+		return err
+	}
+	return m.WebhookStorage.Delete(id)
+}
+
+// GetDeliveries returns the latest delivery attempts of the subscription,
+// newest first.
+func (m *WebhookManager) GetDeliveries(id int64, limit int) ([]domain.WebhookDelivery, error) {
+	if _, err := m.subscription(id); err != nil {
+		return nil, err
+	}
+	return m.WebhookStorage.SelectDeliveries(id, limit)
+}
+
+func (m *WebhookManager) subscription(id int64) (domain.WebhookSubscription, error) {
+	subscriptions, err := m.WebhookStorage.Select(id)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	if len(subscriptions) == 0 {
+		return domain.WebhookSubscription{}, domain.ErrWebhookNotFound
+	}
+	return subscriptions[0], nil
+}
+
+// privateHost reports whether the host is localhost or a non-public address.
+func privateHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && !domain.PublicAddress(addr)
+}
@@ -0,0 +1,138 @@
+package manager
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+func TestWebhookManager_Subscribe(t *testing.T) {
+	merged := []domain.WebhookEventType{domain.EventPullRequestMerged}
+
+	tests := []struct {
+		name         string
+		subscription domain.WebhookSubscription
+		allowPrivate bool
+		wantEvents   []domain.WebhookEventType
+		wantErr      error
+	}{
+		{
+			name: "duplicate events are dropped",
+			subscription: domain.WebhookSubscription{
+				URL:    "https://chat.example.com/hooks/pr",
+				Events: []domain.WebhookEventType{domain.EventPullRequestMerged, domain.EventTeamDeleted, domain.EventPullRequestMerged},
+				Secret: "secret",
+			},
+			wantEvents: []domain.WebhookEventType{domain.EventPullRequestMerged, domain.EventTeamDeleted},
+		},
+		{
+			name:         "relative URL",
+			subscription: domain.WebhookSubscription{URL: "/hooks/pr", Events: merged, Secret: "secret"},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "unsupported scheme",
+			subscription: domain.WebhookSubscription{URL: "ftp://example.com/hooks", Events: merged, Secret: "secret"},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "no events",
+			subscription: domain.WebhookSubscription{URL: "https://chat.example.com/hooks/pr", Secret: "secret"},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "unknown event",
+			subscription: domain.WebhookSubscription{URL: "https://chat.example.com/hooks/pr", Events: []domain.WebhookEventType{"pr.opened"}, Secret: "secret"},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "no secret",
+			subscription: domain.WebhookSubscription{URL: "https://chat.example.com/hooks/pr", Events: merged},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "localhost",
+			subscription: domain.WebhookSubscription{URL: "http://localhost:9000/hooks", Events: merged, Secret: "secret"},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "loopback address",
+			subscription: domain.WebhookSubscription{URL: "http://127.0.0.1:9000/hooks", Events: merged, Secret: "secret"},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "link-local address",
+			subscription: domain.WebhookSubscription{URL: "http://169.254.169.254/latest/meta-data", Events: merged, Secret: "secret"},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "private address",
+			subscription: domain.WebhookSubscription{URL: "https://10.0.0.5/hooks", Events: merged, Secret: "secret"},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "IPv4-mapped loopback address",
+			subscription: domain.WebhookSubscription{URL: "http://[::ffff:127.0.0.1]/hooks", Events: merged, Secret: "secret"},
+			wantErr:      domain.ErrInvalidWebhook,
+		},
+		{
+			name:         "private address allowed",
+			subscription: domain.WebhookSubscription{URL: "http://127.0.0.1:9000/hooks", Events: merged, Secret: "secret"},
+			allowPrivate: true,
+			wantEvents:   merged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := createMockStorage()
+			webhookManager := NewWebhookManager(storage.WebhookStorage)
+			webhookManager.AllowPrivateTargets = tt.allowPrivate
+
+			subscription, err := webhookManager.Subscribe(tt.subscription)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if subscription.ID == 0 || !slices.Equal(subscription.Events, tt.wantEvents) {
+				t.Errorf("unexpected subscription %+v", subscription)
+			}
+		})
+	}
+}
+
+func TestWebhookManager_Unsubscribe(t *testing.T) {
+	storage := createMockStorage()
+	webhookManager := NewWebhookManager(storage.WebhookStorage)
+	subscription, err := webhookManager.Subscribe(domain.WebhookSubscription{
+		URL:    "https://chat.example.com/hooks/pr",
+		Events: []domain.WebhookEventType{domain.EventUserDeactivated},
+		Secret: "secret",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	storage.WebhookStorage.InsertDelivery(domain.WebhookDelivery{SubscriptionID: subscription.ID, EventID: "event-1", Attempt: 1})
+
+	deliveries, err := webhookManager.GetDeliveries(subscription.ID, 10)
+	if err != nil || len(deliveries) != 1 {
+		t.Errorf("expected one delivery, got %+v, %v", deliveries, err)
+	}
+
+	if err := webhookManager.Unsubscribe(subscription.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := webhookManager.Unsubscribe(subscription.ID); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+	if _, err := webhookManager.GetDeliveries(subscription.ID, 10); !errors.Is(err, domain.ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type AbsenceStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+func (s *AbsenceStorage) Insert(absence domain.Absence) (domain.Absence, error) {
+	s.state.lastAbsenceID++
+	absence.ID = s.state.lastAbsenceID
+	s.state.absences = append(s.state.absences, absence)
+	return absence, nil
+}
+
+func (s *AbsenceStorage) Select(userID string) ([]domain.Absence, error) {
+	var absences []domain.Absence
+	for _, absence := range s.state.absences {
+		if absence.UserID == userID {
+			absences = append(absences, absence)
+		}
+	}
+	slices.SortStableFunc(absences, func(a, b domain.Absence) int {
+		return cmp.Or(a.StartsAt.Compare(b.StartsAt), cmp.Compare(a.ID, b.ID))
+	})
+	return absences, nil
+}
+
+func (s *AbsenceStorage) SelectAbsentUserIDs() ([]string, error) {
+	return s.state.absentUserIDs(s.now()), nil
+}
+
+// absentUserIDs returns the users absent at the given time, sorted.
+func (s *state) absentUserIDs(at time.Time) []string {
+	var userIDs []string
+	for _, absence := range s.absences {
+		if absence.Covers(at) && !slices.Contains(userIDs, absence.UserID) {
+			userIDs = append(userIDs, absence.UserID)
+		}
+	}
+	slices.Sort(userIDs)
+	return userIDs
+}
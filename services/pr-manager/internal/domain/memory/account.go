@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type AccountStorage struct {
+	state *state
+}
+
+func (s *AccountStorage) Upsert(account domain.ProviderAccount) error {
+	i := slices.IndexFunc(s.state.accounts, func(existing domain.ProviderAccount) bool {
+		return existing.Provider == account.Provider && existing.Login == account.Login
+	})
+	if i >= 0 {
+		s.state.accounts[i] = account
+		return nil
+	}
+	s.state.accounts = append(s.state.accounts, account)
+	return nil
+}
+
+func (s *AccountStorage) Select(provider domain.Provider, login *string) ([]domain.ProviderAccount, error) {
+	var accounts []domain.ProviderAccount
+	for _, account := range s.state.accounts {
+		if account.Provider == provider && (login == nil || account.Login == *login) {
+			accounts = append(accounts, account)
+		}
+	}
+	slices.SortFunc(accounts, func(a, b domain.ProviderAccount) int {
+		return cmp.Compare(a.Login, b.Login)
+	})
+	return accounts, nil
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"maps"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type AuditStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+func (s *AuditStorage) Insert(event domain.AuditEvent) error {
+	now := s.now()
+	event.ID = int64(len(s.state.auditEvents) + 1)
+	event.Details = maps.Clone(event.Details)
+	if event.Details == nil {
+		event.Details = map[string]any{}
+	}
+	event.CreatedAt = &now
+	s.state.auditEvents = append(s.state.auditEvents, event)
+	return nil
+}
+
+func (s *AuditStorage) Select(filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	var events []domain.AuditEvent
+	for i := len(s.state.auditEvents) - 1; i >= 0 && len(events) < filter.Limit; i-- {
+		if filter.Matches(s.state.auditEvents[i]) {
+			events = append(events, s.state.auditEvents[i])
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type CodeownersStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+func (s *CodeownersStorage) Select(teamName string) ([]domain.Codeowners, error) {
+	codeowners, ok := s.state.codeowners[teamName]
+	if !ok {
+		return nil, nil
+	}
+	return []domain.Codeowners{codeowners}, nil
+}
+
+func (s *CodeownersStorage) Upsert(codeowners domain.Codeowners) error {
+	updatedAt := s.now()
+	codeowners.UpdatedAt = &updatedAt
+	s.state.codeowners[codeowners.TeamName] = codeowners
+	return nil
+}
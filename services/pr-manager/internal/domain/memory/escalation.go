@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type EscalationStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+func (s *EscalationStorage) SelectStaleReviews() ([]domain.StaleReview, error) {
+	now := s.now()
+	var reviews []domain.StaleReview
+	for _, pr := range s.state.pullRequests {
+		if pr.Status != domain.Open {
+			continue
+		}
+		author, ok := s.state.users[pr.AuthorID]
+		if !ok || author.teamDeleted {
+			continue
+		}
+		settings, ok := s.state.settings[author.TeamName]
+		if !ok || settings.ReviewSLAHours <= 0 {
+			continue
+		}
+		deadline := now.Add(-time.Duration(settings.ReviewSLAHours) * time.Hour)
+		for _, r := range pr.reviewers {
+			if r.state != domain.ReviewPending || r.assignedAt.After(deadline) || s.escalatedSince(pr.ID, r.userID, r.assignedAt) {
+				continue
+			}
+			reviews = append(reviews, domain.StaleReview{
+				PullRequestID: pr.ID,
+				ReviewerID:    r.userID,
+				TeamName:      author.TeamName,
+				AssignedAt:    r.assignedAt,
+			})
+		}
+	}
+	slices.SortFunc(reviews, func(a, b domain.StaleReview) int {
+		return cmp.Or(
+			a.AssignedAt.Compare(b.AssignedAt),
+			cmp.Compare(a.PullRequestID, b.PullRequestID),
+			cmp.Compare(a.ReviewerID, b.ReviewerID),
+		)
+	})
+	return reviews, nil
+}
+
+func (s *EscalationStorage) Insert(escalation domain.Escalation) error {
+	now := s.now()
+	escalation.ID = int64(len(s.state.escalations) + 1)
+	escalation.CreatedAt = &now
+	s.state.escalations = append(s.state.escalations, escalation)
+	return nil
+}
+
+// escalatedSince reports whether the review was escalated at or after the
+// given time.
+func (s *EscalationStorage) escalatedSince(pullRequestID string, reviewerID string, since time.Time) bool {
+	return slices.ContainsFunc(s.state.escalations, func(e domain.Escalation) bool {
+		return e.PullRequestID == pullRequestID && e.ReviewerID == reviewerID && !e.CreatedAt.Before(since)
+	})
+}
+
+// LockStorage always gets the lock: the store lives in a single process and
+// serializes write transactions itself.
+type LockStorage struct{}
+
+func (LockStorage) TryLock(key int64) (bool, error) {
+	return true, nil
+}
+
+// SavepointStorage restores a copy of the state taken before fn if fn fails.
+type SavepointStorage struct {
+	state *state
+}
+
+func (s *SavepointStorage) Savepoint(fn func() error) error {
+	saved := s.state.clone()
+	if err := fn(); err != nil {
+		*s.state = *saved
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type outboxRecord struct {
+	domain.OutboxMessage
+	availableAt time.Time
+	lastError   string
+}
+
+type OutboxStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+func (s *OutboxStorage) Insert(event domain.WebhookEvent) error {
+	now := s.now()
+	s.state.lastOutboxID++
+	event.CreatedAt = now
+	event.Data = maps.Clone(event.Data)
+	if event.Data == nil {
+		event.Data = map[string]any{}
+	}
+	s.state.outbox = append(s.state.outbox, outboxRecord{
+		OutboxMessage: domain.OutboxMessage{ID: s.state.lastOutboxID, Event: event},
+		availableAt:   now,
+	})
+	return nil
+}
+
+func (s *OutboxStorage) Claim(limit int, maxAttempts int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	now := s.now()
+	var messages []domain.OutboxMessage
+	for i := range s.state.outbox {
+		if len(messages) >= limit {
+			break
+		}
+		record := &s.state.outbox[i]
+		if record.Attempts >= maxAttempts || record.availableAt.After(now) {
+			continue
+		}
+		record.availableAt = now.Add(lease)
+		messages = append(messages, record.OutboxMessage)
+	}
+	return messages, nil
+}
+
+func (s *OutboxStorage) MarkPublished(id int64) error {
+	s.state.outbox = slices.DeleteFunc(s.state.outbox, func(record outboxRecord) bool {
+		return record.ID == id
+	})
+	return nil
+}
+
+func (s *OutboxStorage) MarkFailed(id int64, reason string, retryAfter time.Duration) error {
+	if record := s.record(id); record != nil {
+		record.Attempts++
+		record.lastError = reason
+		record.availableAt = s.now().Add(retryAfter)
+	}
+	return nil
+}
+
+func (s *OutboxStorage) record(id int64) *outboxRecord {
+	for i := range s.state.outbox {
+		if s.state.outbox[i].ID == id {
+			return &s.state.outbox[i]
+		}
+	}
+	return nil
+}
+
+// DeletePublished deletes nothing: published messages are dropped right away.
+func (s *OutboxStorage) DeletePublished(retention time.Duration) (int64, error) {
+	return 0, nil
+}
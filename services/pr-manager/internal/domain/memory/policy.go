@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"slices"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type PolicyStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+func (s *PolicyStorage) Select(teamName string) ([]domain.MergePolicy, error) {
+	policy, ok := s.state.policies[teamName]
+	if !ok {
+		return nil, nil
+	}
+	return []domain.MergePolicy{policy}, nil
+}
+
+func (s *PolicyStorage) Upsert(policy domain.MergePolicy) error {
+	s.state.policies[policy.TeamName] = policy
+	return nil
+}
+
+func (s *PolicyStorage) InsertOverride(override domain.MergePolicyOverride) error {
+	if _, ok := s.state.pullRequests[override.PullRequestID]; !ok {
+		return domain.ErrNotFound
+	}
+	now := s.now()
+	override.UnmetRules = slices.Clone(override.UnmetRules)
+	override.CreatedAt = &now
+	s.state.overrides = append(s.state.overrides, override)
+	return nil
+}
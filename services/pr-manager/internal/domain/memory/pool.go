@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"slices"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type PoolStorage struct {
+	state *state
+}
+
+func (s *PoolStorage) Select(name string) ([]domain.ReviewerPool, error) {
+	members, ok := s.state.pools[name]
+	if !ok {
+		return nil, nil
+	}
+	members = slices.Clone(members)
+	slices.Sort(members)
+	return []domain.ReviewerPool{{Name: name, Members: members}}, nil
+}
+
+func (s *PoolStorage) Upsert(pool domain.ReviewerPool) error {
+	members := make([]string, 0, len(pool.Members))
+	for _, userID := range pool.Members {
+		if !slices.Contains(members, userID) {
+			members = append(members, userID)
+		}
+	}
+	s.state.pools[pool.Name] = members
+	return nil
+}
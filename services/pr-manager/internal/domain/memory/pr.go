@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type PullRequestStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+func (s *PullRequestStorage) Select(pullRequestID *string) ([]domain.PullRequest, error) {
+	if pullRequestID != nil {
+		pr, ok := s.state.pullRequests[*pullRequestID]
+		if !ok {
+			return nil, nil
+		}
+		return []domain.PullRequest{s.state.toDomain(pr)}, nil
+	}
+	return s.filter(func(pullRequestRecord) bool { return true }), nil
+}
+
+func (s *PullRequestStorage) Create(pullRequest domain.PullRequest) error {
+	if _, ok := s.state.pullRequests[pullRequest.ID]; ok {
+		return domain.ErrPRExists
+	}
+	if _, ok := s.state.users[pullRequest.AuthorID]; !ok {
+		return domain.ErrUserNotFound
+	}
+	now := s.now()
+	s.state.pullRequests[pullRequest.ID] = pullRequestRecord{
+		PullRequest: domain.PullRequest{
+			PullRequestShort: domain.PullRequestShort{
+				ID:       pullRequest.ID,
+				Name:     pullRequest.Name,
+				AuthorID: pullRequest.AuthorID,
+				Status:   pullRequest.Status,
+			},
+			ReviewersCount: pullRequest.ReviewersCount,
+			ChangedPaths:   slices.Clone(pullRequest.ChangedPaths),
+			CreatedAt:      &now,
+		},
+	}
+	return s.assignReviewers(pullRequest.ID, pullRequest.AssignedReviewers, domain.AssignmentInitial)
+}
+
+func (s *PullRequestStorage) Merge(pullRequest domain.PullRequest) error {
+	pr, ok := s.state.pullRequests[pullRequest.ID]
+	if !ok || pr.Status == domain.Merged {
+		return nil
+	}
+	now := s.now()
+	pr.Status = domain.Merged
+	pr.MergedAt = &now
+	s.state.pullRequests[pullRequest.ID] = pr
+	return nil
+}
+
+func (s *PullRequestStorage) UpdateStatus(pullRequestID string, status domain.PullRequestStatus) error {
+	pr, ok := s.state.pullRequests[pullRequestID]
+	if !ok {
+		return nil
+	}
+	pr.Status = status
+	s.state.pullRequests[pullRequestID] = pr
+	return nil
+}
+
+func (s *PullRequestStorage) Reassign(pullRequest domain.PullRequest, reason domain.AssignmentReason) error {
+	pr, ok := s.state.pullRequests[pullRequest.ID]
+	if !ok {
+		return nil
+	}
+	now := s.now()
+	pr.reviewers = slices.DeleteFunc(slices.Clone(pr.reviewers), func(r reviewerRecord) bool {
+		if slices.Contains(pullRequest.AssignedReviewers, r.userID) {
+			return false
+		}
+		pr.assignments = append(pr.assignments, domain.AssignmentEvent{ReviewerID: r.userID, Action: domain.Unassigned, Reason: reason, CreatedAt: &now})
+		return true
+	})
+	s.state.pullRequests[pullRequest.ID] = pr
+	return s.assignReviewers(pullRequest.ID, pullRequest.AssignedReviewers, reason)
+}
+
+func (s *PullRequestStorage) SubmitReview(pullRequestID string, review domain.Review) error {
+	pr, ok := s.state.pullRequests[pullRequestID]
+	if !ok {
+		return domain.ErrNotAssigned
+	}
+	i := slices.IndexFunc(pr.reviewers, func(r reviewerRecord) bool {
+		return r.userID == review.ReviewerID
+	})
+	if i < 0 {
+		return domain.ErrNotAssigned
+	}
+	now := s.now()
+	pr.reviewers = slices.Clone(pr.reviewers)
+	pr.reviewers[i].state = review.State
+	pr.reviewers[i].submittedAt = &now
+	pr.reviews = append(pr.reviews, reviewRecord{
+		reviewerID:  review.ReviewerID,
+		assignedAt:  pr.reviewers[i].assignedAt,
+		state:       review.State,
+		submittedAt: now,
+	})
+	s.state.pullRequests[pullRequestID] = pr
+	return nil
+}
+
+func (s *PullRequestStorage) SelectUserPullRequestsReviews(userID string) ([]domain.PullRequest, error) {
+	return s.filter(func(pr pullRequestRecord) bool {
+		return hasReviewer(pr, userID)
+	}), nil
+}
+
+func (s *PullRequestStorage) CountUserOpenReviews(userIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(userIDs))
+	for _, userID := range userIDs {
+		counts[userID] = 0
+	}
+	for _, pr := range s.state.pullRequests {
+		if pr.Status != domain.Open {
+			continue
+		}
+		for _, r := range pr.reviewers {
+			if _, ok := counts[r.userID]; ok {
+				counts[r.userID]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (s *PullRequestStorage) SelectHistory(pullRequestID string) ([]domain.AssignmentEvent, error) {
+	return slices.Clone(s.state.pullRequests[pullRequestID].assignments), nil
+}
+
+func (s *PullRequestStorage) assignReviewers(pullRequestID string, reviewers []string, reason domain.AssignmentReason) error {
+	pr := s.state.pullRequests[pullRequestID]
+	now := s.now()
+	for _, userID := range reviewers {
+		if hasReviewer(pr, userID) {
+			continue
+		}
+		if _, ok := s.state.users[userID]; !ok {
+			return domain.ErrUserNotFound
+		}
+		pr.reviewers = append(pr.reviewers, reviewerRecord{userID: userID, assignedAt: now, state: domain.ReviewPending})
+		pr.assignments = append(pr.assignments, domain.AssignmentEvent{ReviewerID: userID, Action: domain.Assigned, Reason: reason, CreatedAt: &now})
+	}
+	s.state.pullRequests[pullRequestID] = pr
+	return nil
+}
+
+func (s *PullRequestStorage) filter(keep func(pullRequestRecord) bool) []domain.PullRequest {
+	var prs []domain.PullRequest
+	for _, pr := range s.state.pullRequests {
+		if keep(pr) {
+			prs = append(prs, s.state.toDomain(pr))
+		}
+	}
+	slices.SortFunc(prs, func(a, b domain.PullRequest) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return prs
+}
+
+func hasReviewer(pr pullRequestRecord, userID string) bool {
+	return slices.ContainsFunc(pr.reviewers, func(r reviewerRecord) bool {
+		return r.userID == userID
+	})
+}
@@ -0,0 +1,22 @@
+package memory
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type SettingsStorage struct {
+	state *state
+}
+
+func (s *SettingsStorage) Select(teamName string) ([]domain.TeamSettings, error) {
+	settings, ok := s.state.settings[teamName]
+	if !ok {
+		return nil, nil
+	}
+	return []domain.TeamSettings{settings}, nil
+}
+
+func (s *SettingsStorage) Upsert(settings domain.TeamSettings) error {
+	s.state.settings[settings.TeamName] = settings
+	return nil
+}
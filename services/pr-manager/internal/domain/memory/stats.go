@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type StatsStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+// mergeTimes accumulates merge times to average them.
+type mergeTimes struct {
+	totalHours float64
+	count      int
+}
+
+func (m *mergeTimes) add(pr pullRequestRecord, filter domain.StatsFilter) {
+	if pr.Status != domain.Merged || pr.CreatedAt == nil || pr.MergedAt == nil || !filter.InWindow(pr.MergedAt) {
+		return
+	}
+	m.totalHours += pr.MergedAt.Sub(*pr.CreatedAt).Hours()
+	m.count++
+}
+
+func (m mergeTimes) average() float64 {
+	if m.count == 0 {
+		return 0
+	}
+	return m.totalHours / float64(m.count)
+}
+
+// reviewLatency collects review latencies in hours to aggregate them.
+type reviewLatency struct {
+	firstReviews     []float64
+	changesRequested []float64
+}
+
+// add collects the hours from assignment to the first review of a reviewer
+// for the reviewer and the waits after changes requested by the reviewer for
+// the author, each only if the review ending it falls into the window. The
+// reviews are those of one reviewer, oldest first.
+func (l reviewLatency) add(reviews []reviewRecord, filter domain.StatsFilter, forReviewer, forAuthor bool) reviewLatency {
+	for i, review := range reviews {
+		if i == 0 && forReviewer && filter.InWindow(&review.submittedAt) {
+			l.firstReviews = append(l.firstReviews, review.submittedAt.Sub(review.assignedAt).Hours())
+		}
+		if i == 0 || reviews[i-1].state != domain.ReviewChangesRequested {
+			continue
+		}
+		if forAuthor && filter.InWindow(&review.submittedAt) {
+			l.changesRequested = append(l.changesRequested, review.submittedAt.Sub(reviews[i-1].submittedAt).Hours())
+		}
+	}
+	return l
+}
+
+// percentile interpolates linearly between the closest values, as
+// percentile_cont does in Postgres.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(values))
+	position := p * float64(len(sorted)-1)
+	lower := int(position)
+	if lower == len(sorted)-1 {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[lower+1]-sorted[lower])*(position-float64(lower))
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+func (l reviewLatency) apply(p50, p90, changesRequested *float64) {
+	*p50 = percentile(l.firstReviews, 0.5)
+	*p90 = percentile(l.firstReviews, 0.9)
+	*changesRequested = average(l.changesRequested)
+}
+
+// selectedUsers returns the users of not deleted teams matching the filter.
+func (s *StatsStorage) selectedUsers(filter domain.StatsFilter) []userRecord {
+	var users []userRecord
+	for _, u := range s.state.activeUsers() {
+		if filter.SelectsUser(u.User) {
+			users = append(users, u)
+		}
+	}
+	return users
+}
+
+// SelectUserActivity takes every review metric from the review history, as
+// the Postgres storage does: a review is waiting while the reviewer has not
+// submitted one since the assignment.
+func (s *StatsStorage) SelectUserActivity(filter domain.StatsFilter) ([]domain.UserActivity, error) {
+	users := s.selectedUsers(filter)
+	byID := make(map[string]*domain.UserActivity, len(users))
+	merges := make(map[string]*mergeTimes, len(users))
+	latencies := make(map[string]reviewLatency, len(users))
+	absent := s.state.absentUserIDs(s.now())
+	for _, u := range users {
+		byID[u.UserID] = &domain.UserActivity{
+			UserID:   u.UserID,
+			Username: u.Username,
+			TeamName: u.TeamName,
+			IsActive: u.IsActive,
+			IsAbsent: slices.Contains(absent, u.UserID),
+		}
+		merges[u.UserID] = &mergeTimes{}
+	}
+
+	for _, pr := range s.state.pullRequests {
+		author, isAuthor := byID[pr.AuthorID]
+		if isAuthor {
+			createdInWindow := filter.InWindow(pr.CreatedAt)
+			if createdInWindow {
+				author.PRsCreated++
+			}
+			switch {
+			case pr.Status == domain.Merged && filter.InWindow(pr.MergedAt):
+				author.PRsMerged++
+			case pr.Status == domain.Open && createdInWindow:
+				author.PRsOpen++
+			}
+			merges[pr.AuthorID].add(pr, filter)
+		}
+		reviewsByReviewer := pr.reviewsByReviewer()
+		for reviewerID, reviews := range reviewsByReviewer {
+			if isAuthor {
+				latencies[pr.AuthorID] = latencies[pr.AuthorID].add(reviews, filter, false, true)
+			}
+			reviewer, ok := byID[reviewerID]
+			if !ok {
+				continue
+			}
+			latencies[reviewerID] = latencies[reviewerID].add(reviews, filter, true, false)
+			if slices.ContainsFunc(reviews, func(review reviewRecord) bool { return filter.InWindow(&review.submittedAt) }) {
+				reviewer.PRsReviewed++
+			}
+		}
+		for _, r := range pr.reviewers {
+			reviewer, ok := byID[r.userID]
+			if !ok || pr.Status != domain.Open || !filter.InWindow(&r.assignedAt) {
+				continue
+			}
+			if !slices.ContainsFunc(reviewsByReviewer[r.userID], func(review reviewRecord) bool { return !review.submittedAt.Before(r.assignedAt) }) {
+				reviewer.PRsWaitingForReview++
+			}
+		}
+	}
+
+	activity := make([]domain.UserActivity, 0, len(byID))
+	for id, user := range byID {
+		user.AverageMergeTimeHours = merges[id].average()
+		latencies[id].apply(&user.TimeToFirstReviewP50Hours, &user.TimeToFirstReviewP90Hours, &user.AverageChangesRequestedWaitHours)
+		activity = append(activity, *user)
+	}
+	slices.SortFunc(activity, func(a, b domain.UserActivity) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+	return activity, nil
+}
+
+func (s *StatsStorage) SelectTeamActivity(filter domain.StatsFilter) ([]domain.TeamActivity, error) {
+	byName := make(map[string]*domain.TeamActivity)
+	teamOf := make(map[string]string)
+	absent := s.state.absentUserIDs(s.now())
+	for _, u := range s.selectedUsers(filter) {
+		if u.TeamName == "" {
+			continue
+		}
+		team, ok := byName[u.TeamName]
+		if !ok {
+			team = &domain.TeamActivity{TeamName: u.TeamName}
+			byName[u.TeamName] = team
+		}
+		team.TotalMembers++
+		if u.IsActive {
+			team.ActiveMembers++
+			if slices.Contains(absent, u.UserID) {
+				team.AbsentMembers++
+			}
+		}
+		teamOf[u.UserID] = u.TeamName
+	}
+
+	merges := make(map[string]*mergeTimes, len(byName))
+	latencies := make(map[string]reviewLatency, len(byName))
+	for _, pr := range s.state.pullRequests {
+		authorTeam, isAuthor := teamOf[pr.AuthorID]
+		for reviewerID, reviews := range pr.reviewsByReviewer() {
+			if isAuthor {
+				latencies[authorTeam] = latencies[authorTeam].add(reviews, filter, false, true)
+			}
+			if reviewerTeam, ok := teamOf[reviewerID]; ok {
+				latencies[reviewerTeam] = latencies[reviewerTeam].add(reviews, filter, true, false)
+			}
+		}
+		if !isAuthor {
+			continue
+		}
+		if merges[authorTeam] == nil {
+			merges[authorTeam] = &mergeTimes{}
+		}
+		merges[authorTeam].add(pr, filter)
+	}
+
+	activity := make([]domain.TeamActivity, 0, len(byName))
+	for name, team := range byName {
+		if merges[name] != nil {
+			team.AverageMergeTimeHours = merges[name].average()
+		}
+		latencies[name].apply(&team.TimeToFirstReviewP50Hours, &team.TimeToFirstReviewP90Hours, &team.AverageChangesRequestedWaitHours)
+		activity = append(activity, *team)
+	}
+	slices.SortFunc(activity, func(a, b domain.TeamActivity) int {
+		return strings.Compare(a.TeamName, b.TeamName)
+	})
+	return activity, nil
+}
+
+// SelectPullRequestTotals counts pull requests of deleted teams too, unless the
+// filter narrows the statistics down to some users.
+func (s *StatsStorage) SelectPullRequestTotals(filter domain.StatsFilter) (domain.PullRequestTotals, error) {
+	authors := make(map[string]bool)
+	for _, u := range s.selectedUsers(filter) {
+		authors[u.UserID] = true
+	}
+
+	totals := domain.PullRequestTotals{}
+	merges := mergeTimes{}
+	for _, pr := range s.state.pullRequests {
+		if filter.SelectsUsers() && !authors[pr.AuthorID] {
+			continue
+		}
+		if filter.InWindow(pr.CreatedAt) {
+			totals.Total++
+		}
+		merges.add(pr, filter)
+	}
+	totals.AverageMergeTimeHours = merges.average()
+	return totals, nil
+}
@@ -0,0 +1,318 @@
+package memory
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+)
+
+func TestStatsStorage_Activity(t *testing.T) {
+	store := NewStore()
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	seedTeam(t, store, "backend", "u1", "u10")
+	seedTeam(t, store, "frontend", "u2")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		prs := storage.PullRequestStorage
+		prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1", AuthorID: "u2", Status: domain.Open}, AssignedReviewers: []string{"u10"}})
+		prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr2", AuthorID: "u2", Status: domain.Open}, AssignedReviewers: []string{"u10"}})
+		prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr3", AuthorID: "u1", Status: domain.Open}})
+		prs.SubmitReview("pr2", domain.Review{ReviewerID: "u10", State: domain.ReviewApproved})
+		now = now.Add(2 * time.Hour)
+		prs.Merge(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr2"}})
+		return prs.UpdateStatus("pr3", domain.Closed)
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed pull requests: %v", err)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		users, err := storage.StatsStorage.SelectUserActivity(domain.StatsFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		byID := make(map[string]domain.UserActivity, len(users))
+		for _, user := range users {
+			byID[user.UserID] = user
+		}
+		if got := byID["u1"]; got.PRsCreated != 1 || got.PRsOpen != 0 || got.PRsReviewed != 0 || got.PRsWaitingForReview != 0 {
+			t.Errorf("unexpected u1 activity %+v", got)
+		}
+		if got := byID["u10"]; got.PRsReviewed != 1 || got.PRsWaitingForReview != 1 {
+			t.Errorf("expected u10 reviewed=1 waiting=1, got %+v", got)
+		}
+		if got := byID["u2"]; got.PRsCreated != 2 || got.PRsMerged != 1 || got.PRsOpen != 1 || got.AverageMergeTimeHours != 2 {
+			t.Errorf("unexpected u2 activity %+v", got)
+		}
+
+		teams, err := storage.StatsStorage.SelectTeamActivity(domain.StatsFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(teams) != 2 || teams[0].TeamName != "backend" || teams[0].TotalMembers != 2 || teams[0].AverageMergeTimeHours != 0 {
+			t.Errorf("expected closed PR to be excluded from backend merge time, got %+v", teams)
+		}
+		if teams[1].AverageMergeTimeHours != 2 {
+			t.Errorf("expected frontend merge time of 2h, got %+v", teams[1])
+		}
+
+		totals, err := storage.StatsStorage.SelectPullRequestTotals(domain.StatsFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if totals.Total != 3 || totals.AverageMergeTimeHours != 2 {
+			t.Errorf("expected 3 PRs with 2h merge time, got %+v", totals)
+		}
+		return nil
+	}, true)
+}
+
+func TestStatsStorage_Filtered(t *testing.T) {
+	store := NewStore()
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	seedTeam(t, store, "backend", "u1", "u10")
+	seedTeam(t, store, "frontend", "u2")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		prs := storage.PullRequestStorage
+		prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1", AuthorID: "u1", Status: domain.Open}, AssignedReviewers: []string{"u10"}})
+		prs.SubmitReview("pr1", domain.Review{ReviewerID: "u10", State: domain.ReviewApproved})
+		now = now.Add(24 * time.Hour)
+		prs.Merge(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1"}})
+		prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr2", AuthorID: "u1", Status: domain.Open}, AssignedReviewers: []string{"u10"}})
+		return prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr3", AuthorID: "u2", Status: domain.Open}})
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed pull requests: %v", err)
+	}
+
+	from := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	team := "backend"
+	filter := domain.StatsFilter{From: &from, TeamName: &team}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		users, err := storage.StatsStorage.SelectUserActivity(filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(users) != 2 {
+			t.Fatalf("expected only backend members, got %+v", users)
+		}
+		// pr1 was created and reviewed before the window, but merged within it.
+		if got := users[0]; got.UserID != "u1" || got.PRsCreated != 1 || got.PRsMerged != 1 || got.PRsOpen != 1 || got.AverageMergeTimeHours != 24 {
+			t.Errorf("unexpected u1 activity %+v", got)
+		}
+		if got := users[1]; got.PRsReviewed != 0 || got.PRsWaitingForReview != 1 {
+			t.Errorf("expected u10 reviewed=0 waiting=1, got %+v", got)
+		}
+
+		teams, err := storage.StatsStorage.SelectTeamActivity(filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(teams) != 1 || teams[0].TeamName != "backend" || teams[0].AverageMergeTimeHours != 24 {
+			t.Errorf("expected only backend with 24h merge time, got %+v", teams)
+		}
+
+		totals, err := storage.StatsStorage.SelectPullRequestTotals(filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if totals.Total != 1 || totals.AverageMergeTimeHours != 24 {
+			t.Errorf("expected 1 backend PR within the window, got %+v", totals)
+		}
+
+		totals, _ = storage.StatsStorage.SelectPullRequestTotals(domain.StatsFilter{From: &from})
+		if totals.Total != 2 {
+			t.Errorf("expected 2 PRs within the window, got %+v", totals)
+		}
+		return nil
+	}, true)
+}
+
+func TestStatsStorage_ReviewLatency(t *testing.T) {
+	store := NewStore()
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	store.now = func() time.Time { return now }
+	seedTeam(t, store, "backend", "u1", "u10", "u11")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		prs := storage.PullRequestStorage
+		prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1", AuthorID: "u1", Status: domain.Open}, AssignedReviewers: []string{"u10", "u11"}})
+		now = start.Add(time.Hour)
+		prs.SubmitReview("pr1", domain.Review{ReviewerID: "u10", State: domain.ReviewChangesRequested})
+		now = start.Add(3 * time.Hour)
+		prs.SubmitReview("pr1", domain.Review{ReviewerID: "u11", State: domain.ReviewApproved})
+		now = start.Add(4 * time.Hour)
+		return prs.SubmitReview("pr1", domain.Review{ReviewerID: "u10", State: domain.ReviewApproved})
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed pull requests: %v", err)
+	}
+
+	near := func(got, want float64) bool {
+		return math.Abs(got-want) < 1e-9
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		users, err := storage.StatsStorage.SelectUserActivity(domain.StatsFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		byID := make(map[string]domain.UserActivity, len(users))
+		for _, user := range users {
+			byID[user.UserID] = user
+		}
+		if got := byID["u10"]; got.TimeToFirstReviewP50Hours != 1 || got.TimeToFirstReviewP90Hours != 1 {
+			t.Errorf("expected u10 to review after 1h, got %+v", got)
+		}
+		if got := byID["u11"]; got.TimeToFirstReviewP50Hours != 3 {
+			t.Errorf("expected u11 to review after 3h, got %+v", got)
+		}
+		if got := byID["u1"]; got.AverageChangesRequestedWaitHours != 3 || got.TimeToFirstReviewP50Hours != 0 {
+			t.Errorf("expected u1 to wait 3h after requested changes, got %+v", got)
+		}
+
+		teams, err := storage.StatsStorage.SelectTeamActivity(domain.StatsFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(teams) != 1 || !near(teams[0].TimeToFirstReviewP50Hours, 2) || !near(teams[0].TimeToFirstReviewP90Hours, 2.8) || teams[0].AverageChangesRequestedWaitHours != 3 {
+			t.Errorf("expected backend p50=2h p90=2.8h wait=3h, got %+v", teams)
+		}
+
+		// Only the second review of u10 falls into the window: it ends the
+		// wait, but is not the first review.
+		from := start.Add(3*time.Hour + 30*time.Minute)
+		users, _ = storage.StatsStorage.SelectUserActivity(domain.StatsFilter{From: &from})
+		for _, user := range users {
+			if user.TimeToFirstReviewP50Hours != 0 {
+				t.Errorf("expected no first reviews within the window, got %+v", user)
+			}
+			if user.UserID == "u1" && user.AverageChangesRequestedWaitHours != 3 {
+				t.Errorf("expected the wait to end within the window, got %+v", user)
+			}
+		}
+		return nil
+	}, true)
+}
+
+func TestStatsStorage_ReviewLatencyOfUnassignedReviewer(t *testing.T) {
+	store := NewStore()
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	store.now = func() time.Time { return now }
+	seedTeam(t, store, "backend", "u1", "u10", "u11")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		prs := storage.PullRequestStorage
+		prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1", AuthorID: "u1", Status: domain.Open}, AssignedReviewers: []string{"u10"}})
+		now = start.Add(2 * time.Hour)
+		prs.SubmitReview("pr1", domain.Review{ReviewerID: "u10", State: domain.ReviewChangesRequested})
+		now = start.Add(3 * time.Hour)
+		return prs.Reassign(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1"}, AssignedReviewers: []string{"u11"}}, domain.AssignmentReassign)
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed pull requests: %v", err)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		users, _ := storage.StatsStorage.SelectUserActivity(domain.StatsFilter{})
+		for _, user := range users {
+			if user.UserID == "u10" && user.TimeToFirstReviewP50Hours != 2 {
+				t.Errorf("expected the review of the unassigned u10 to count, got %+v", user)
+			}
+		}
+		return nil
+	}, true)
+}
+
+func TestStatsStorage_ReviewedCountSurvivesReassignment(t *testing.T) {
+	store := NewStore()
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	store.now = func() time.Time { return now }
+	seedTeam(t, store, "backend", "u1", "u10", "u11")
+
+	reviewed := func() int64 {
+		t.Helper()
+		var count int64
+		store.WithTransaction(func(storage *db.Storage) error {
+			users, _ := storage.StatsStorage.SelectUserActivity(domain.StatsFilter{})
+			for _, user := range users {
+				if user.UserID == "u10" {
+					count = user.PRsReviewed
+				}
+			}
+			return nil
+		}, true)
+		return count
+	}
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		prs := storage.PullRequestStorage
+		prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1", AuthorID: "u1", Status: domain.Open}, AssignedReviewers: []string{"u10"}})
+		now = start.Add(time.Hour)
+		prs.SubmitReview("pr1", domain.Review{ReviewerID: "u10", State: domain.ReviewChangesRequested})
+		now = start.Add(2 * time.Hour)
+		return prs.SubmitReview("pr1", domain.Review{ReviewerID: "u10", State: domain.ReviewApproved})
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed pull requests: %v", err)
+	}
+	if got := reviewed(); got != 1 {
+		t.Fatalf("expected one reviewed pull request, got %d", got)
+	}
+
+	err = store.WithTransaction(func(storage *db.Storage) error {
+		now = start.Add(3 * time.Hour)
+		return storage.PullRequestStorage.Reassign(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1"}, AssignedReviewers: []string{"u11"}}, domain.AssignmentReassign)
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to reassign: %v", err)
+	}
+	if got := reviewed(); got != 1 {
+		t.Errorf("expected the review of the unassigned u10 to still count, got %d", got)
+	}
+}
+
+func TestStatsStorage_ReviewMetricsAgree(t *testing.T) {
+	store := NewStore()
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	store.now = func() time.Time { return now }
+	seedTeam(t, store, "backend", "u1", "u10", "u11")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		prs := storage.PullRequestStorage
+		prs.Create(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1", AuthorID: "u1", Status: domain.Open}, AssignedReviewers: []string{"u10"}})
+		now = start.Add(time.Hour)
+		prs.SubmitReview("pr1", domain.Review{ReviewerID: "u10", State: domain.ReviewApproved})
+		now = start.Add(2 * time.Hour)
+		prs.Reassign(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1"}, AssignedReviewers: []string{"u11"}}, domain.AssignmentReassign)
+		now = start.Add(3 * time.Hour)
+		return prs.Reassign(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1"}, AssignedReviewers: []string{"u11", "u10"}}, domain.AssignmentReassign)
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed pull requests: %v", err)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		users, _ := storage.StatsStorage.SelectUserActivity(domain.StatsFilter{})
+		for _, user := range users {
+			if user.UserID != "u10" {
+				continue
+			}
+			if user.PRsReviewed != 1 || user.TimeToFirstReviewP50Hours != 1 || user.PRsWaitingForReview != 1 {
+				t.Errorf("expected u10 to have reviewed pr1 once and to wait for the new assignment, got %+v", user)
+			}
+		}
+		return nil
+	}, true)
+}
@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+)
+
+// Store keeps all data in process memory. Write transactions are serialized
+// and run against a copy of the state that replaces it only on success, so a
+// failed transaction leaves no trace, as with Postgres.
+type Store struct {
+	mu    sync.RWMutex
+	state *state
+	now   func() time.Time
+}
+
+func NewStore() *Store {
+	return NewStoreWithClock(time.Now)
+}
+
+// NewStoreWithClock returns a store that takes the current time from now.
+func NewStoreWithClock(now func() time.Time) *Store {
+	return &Store{state: newState(), now: now}
+}
+
+func (s *Store) WithTransaction(fn func(*db.Storage) error, isReadOnly bool) error {
+	if isReadOnly {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return fn(s.storage(s.state))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	draft := s.state.clone()
+	if err := fn(s.storage(draft)); err != nil {
+		return err
+	}
+	s.state = draft
+	return nil
+}
+
+func (s *Store) storage(st *state) *db.Storage {
+	return &db.Storage{
+		UserStorage:        &UserStorage{state: st},
+		TeamStorage:        &TeamStorage{state: st, now: s.now},
+		PullRequestStorage: &PullRequestStorage{state: st, now: s.now},
+		PolicyStorage:      &PolicyStorage{state: st, now: s.now},
+		StatsStorage:       &StatsStorage{state: st, now: s.now},
+		AuditStorage:       &AuditStorage{state: st, now: s.now},
+		AbsenceStorage:     &AbsenceStorage{state: st, now: s.now},
+		SettingsStorage:    &SettingsStorage{state: st},
+		PoolStorage:        &PoolStorage{state: st},
+		CodeownersStorage:  &CodeownersStorage{state: st, now: s.now},
+		EscalationStorage:  &EscalationStorage{state: st, now: s.now},
+		LockStorage:        LockStorage{},
+		SavepointStorage:   &SavepointStorage{state: st},
+		WebhookStorage:     &WebhookStorage{state: st, now: s.now},
+		OutboxStorage:      &OutboxStorage{state: st, now: s.now},
+		AccountStorage:     &AccountStorage{state: st},
+	}
+}
+
+type userRecord struct {
+	domain.User
+	teamDeleted   bool
+	teamDeletedAt time.Time
+	seq           int
+}
+
+type reviewerRecord struct {
+	userID      string
+	assignedAt  time.Time
+	state       domain.ReviewState
+	submittedAt *time.Time
+}
+
+type reviewRecord struct {
+	reviewerID string
+	// assignedAt is when the reviewer was assigned before submitting.
+	assignedAt  time.Time
+	state       domain.ReviewState
+	submittedAt time.Time
+}
+
+type pullRequestRecord struct {
+	domain.PullRequest
+	reviewers   []reviewerRecord
+	assignments []domain.AssignmentEvent
+	// reviews holds every submitted review, oldest first, including those of
+	// reviewers unassigned since.
+	reviews []reviewRecord
+}
+
+// reviewsByReviewer groups the submitted reviews by reviewer, oldest first.
+func (pr pullRequestRecord) reviewsByReviewer() map[string][]reviewRecord {
+	byReviewer := make(map[string][]reviewRecord)
+	for _, review := range pr.reviews {
+		byReviewer[review.reviewerID] = append(byReviewer[review.reviewerID], review)
+	}
+	return byReviewer
+}
+
+type state struct {
+	users             map[string]userRecord
+	pullRequests      map[string]pullRequestRecord
+	policies          map[string]domain.MergePolicy
+	settings          map[string]domain.TeamSettings
+	pools             map[string][]string
+	codeowners        map[string]domain.Codeowners
+	overrides         []domain.MergePolicyOverride
+	auditEvents       []domain.AuditEvent
+	absences          []domain.Absence
+	escalations       []domain.Escalation
+	webhooks          []domain.WebhookSubscription
+	webhookDeliveries []domain.WebhookDelivery
+	// outbox holds only unpublished messages; published ones are dropped.
+	outbox                []outboxRecord
+	accounts              []domain.ProviderAccount
+	nextSeq               int
+	lastAbsenceID         int64
+	lastWebhookID         int64
+	lastWebhookDeliveryID int64
+	lastOutboxID          int64
+}
+
+func newState() *state {
+	return &state{
+		users:        make(map[string]userRecord),
+		pullRequests: make(map[string]pullRequestRecord),
+		policies:     make(map[string]domain.MergePolicy),
+		settings:     make(map[string]domain.TeamSettings),
+		pools:        make(map[string][]string),
+		codeowners:   make(map[string]domain.Codeowners),
+	}
+}
+
+// clone copies the state for a write transaction. Maps and slices that are
+// changed in place are copied; the append-only logs (audit events, webhook
+// deliveries, escalations, assignment and review history) are shared with the
+// committed state instead. Write transactions are serialized, so a draft only
+// ever appends past the committed length, which the committed state does not
+// see; the rare in-place change of a shared slice copies it first. Pull request
+// records are copied shallowly and own their reviewers only once changed.
+func (s *state) clone() *state {
+	return &state{
+		users:                 maps.Clone(s.users),
+		pullRequests:          maps.Clone(s.pullRequests),
+		policies:              maps.Clone(s.policies),
+		settings:              maps.Clone(s.settings),
+		pools:                 maps.Clone(s.pools),
+		codeowners:            maps.Clone(s.codeowners),
+		overrides:             slices.Clone(s.overrides),
+		auditEvents:           s.auditEvents,
+		absences:              slices.Clone(s.absences),
+		escalations:           s.escalations,
+		webhooks:              slices.Clone(s.webhooks),
+		webhookDeliveries:     s.webhookDeliveries,
+		outbox:                slices.Clone(s.outbox),
+		accounts:              slices.Clone(s.accounts),
+		nextSeq:               s.nextSeq,
+		lastAbsenceID:         s.lastAbsenceID,
+		lastWebhookID:         s.lastWebhookID,
+		lastWebhookDeliveryID: s.lastWebhookDeliveryID,
+		lastOutboxID:          s.lastOutboxID,
+	}
+}
+
+func (s *state) activeUsers() []userRecord {
+	return slices.DeleteFunc(s.sortedUsers(), func(u userRecord) bool {
+		return u.teamDeleted
+	})
+}
+
+// sortedUsers returns all users, including those of deleted teams, in
+// insertion order.
+func (s *state) sortedUsers() []userRecord {
+	users := slices.Collect(maps.Values(s.users))
+	slices.SortFunc(users, func(a, b userRecord) int {
+		return a.seq - b.seq
+	})
+	return users
+}
+
+func (s *state) toDomain(pr pullRequestRecord) domain.PullRequest {
+	result := pr.PullRequest
+	result.AssignedReviewers = make([]string, 0, len(pr.reviewers))
+	result.Reviews = make([]domain.Review, 0, len(pr.reviewers))
+	for _, r := range pr.reviewers {
+		result.AssignedReviewers = append(result.AssignedReviewers, r.userID)
+		review := domain.Review{ReviewerID: r.userID, State: r.state}
+		if r.submittedAt != nil {
+			submittedAt := *r.submittedAt
+			review.SubmittedAt = &submittedAt
+		}
+		result.Reviews = append(result.Reviews, review)
+	}
+	if pr.CreatedAt != nil {
+		createdAt := *pr.CreatedAt
+		result.CreatedAt = &createdAt
+	}
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		result.MergedAt = &mergedAt
+	}
+	if pr.ReviewersCount != nil {
+		reviewersCount := *pr.ReviewersCount
+		result.ReviewersCount = &reviewersCount
+	}
+	result.ChangedPaths = slices.Clone(pr.ChangedPaths)
+	return result
+}
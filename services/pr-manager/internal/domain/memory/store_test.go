@@ -0,0 +1,597 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/domain/db"
+)
+
+func seedTeam(t *testing.T, store *Store, teamName string, userIDs ...string) {
+	t.Helper()
+	team := domain.Team{TeamName: teamName}
+	for _, userID := range userIDs {
+		team.Members = append(team.Members, domain.TeamMember{UserID: userID, Username: userID, IsActive: true})
+	}
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		return storage.TeamStorage.Insert(team)
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed team: %v", err)
+	}
+}
+
+func TestStore_Teams(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*testing.T, *Store)
+		run      func(*db.Storage) error
+		wantErr  error
+		validate func(*testing.T, *db.Storage)
+	}{
+		{
+			name: "members keep insertion order",
+			setup: func(t *testing.T, store *Store) {
+				seedTeam(t, store, "backend", "u3", "u1", "u2")
+			},
+			validate: func(t *testing.T, storage *db.Storage) {
+				teamName := "backend"
+				teams, _ := storage.TeamStorage.Select(&teamName)
+				if len(teams) != 1 || len(teams[0].Members) != 3 {
+					t.Fatalf("expected one team with 3 members, got %+v", teams)
+				}
+				if teams[0].Members[0].UserID != "u3" {
+					t.Errorf("expected u3 first, got %s", teams[0].Members[0].UserID)
+				}
+			},
+		},
+		{
+			name: "user already in another team",
+			setup: func(t *testing.T, store *Store) {
+				seedTeam(t, store, "backend", "u1")
+			},
+			run: func(storage *db.Storage) error {
+				return storage.TeamStorage.Insert(domain.Team{
+					TeamName: "frontend",
+					Members:  []domain.TeamMember{{UserID: "u1", Username: "u1", IsActive: true}},
+				})
+			},
+			wantErr: domain.ErrUserInAnotherTeam,
+		},
+		{
+			name: "deleted team is hidden from teams and users",
+			setup: func(t *testing.T, store *Store) {
+				seedTeam(t, store, "backend", "u1", "u2")
+				seedTeam(t, store, "frontend", "u3")
+			},
+			run: func(storage *db.Storage) error {
+				return storage.TeamStorage.Delete("backend")
+			},
+			validate: func(t *testing.T, storage *db.Storage) {
+				teams, _ := storage.TeamStorage.Select(nil)
+				if len(teams) != 1 || teams[0].TeamName != "frontend" {
+					t.Errorf("expected only frontend, got %+v", teams)
+				}
+				userID := "u1"
+				users, _ := storage.UserStorage.Select(&userID)
+				if len(users) != 0 {
+					t.Errorf("expected deleted user to be hidden, got %+v", users)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore()
+			if tt.setup != nil {
+				tt.setup(t, store)
+			}
+
+			if tt.run != nil {
+				err := store.WithTransaction(tt.run, false)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("expected error %v, got %v", tt.wantErr, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if tt.validate != nil {
+				store.WithTransaction(func(storage *db.Storage) error {
+					tt.validate(t, storage)
+					return nil
+				}, true)
+			}
+		})
+	}
+}
+
+func TestStore_PullRequests(t *testing.T) {
+	newPR := func(id string, reviewers ...string) domain.PullRequest {
+		return domain.PullRequest{
+			PullRequestShort:  domain.PullRequestShort{ID: id, Name: id, AuthorID: "u1", Status: domain.Open},
+			AssignedReviewers: reviewers,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		run      func(*db.Storage) error
+		wantErr  error
+		validate func(*testing.T, *db.Storage)
+	}{
+		{
+			name: "duplicate PR id",
+			run: func(storage *db.Storage) error {
+				if err := storage.PullRequestStorage.Create(newPR("pr1", "u2")); err != nil {
+					return err
+				}
+				return storage.PullRequestStorage.Create(newPR("pr1", "u3"))
+			},
+			wantErr: domain.ErrPRExists,
+		},
+		{
+			name: "unknown reviewer",
+			run: func(storage *db.Storage) error {
+				return storage.PullRequestStorage.Create(newPR("pr1", "ghost"))
+			},
+			wantErr: domain.ErrUserNotFound,
+		},
+		{
+			name: "merge is idempotent",
+			run: func(storage *db.Storage) error {
+				if err := storage.PullRequestStorage.Create(newPR("pr1", "u2")); err != nil {
+					return err
+				}
+				if err := storage.PullRequestStorage.Merge(newPR("pr1")); err != nil {
+					return err
+				}
+				return storage.PullRequestStorage.Merge(newPR("pr1"))
+			},
+			validate: func(t *testing.T, storage *db.Storage) {
+				prID := "pr1"
+				prs, _ := storage.PullRequestStorage.Select(&prID)
+				if len(prs) != 1 || prs[0].Status != domain.Merged || prs[0].MergedAt == nil {
+					t.Fatalf("expected merged PR, got %+v", prs)
+				}
+				counts, _ := storage.PullRequestStorage.CountUserOpenReviews([]string{"u2"})
+				if counts["u2"] != 0 {
+					t.Errorf("expected no open reviews for u2, got %d", counts["u2"])
+				}
+			},
+		},
+		{
+			name: "reassign keeps remaining reviewers in assignment order",
+			run: func(storage *db.Storage) error {
+				if err := storage.PullRequestStorage.Create(newPR("pr1", "u2", "u3")); err != nil {
+					return err
+				}
+				return storage.PullRequestStorage.Reassign(newPR("pr1", "u3", "u4"), domain.AssignmentReassign)
+			},
+			validate: func(t *testing.T, storage *db.Storage) {
+				prID := "pr1"
+				prs, _ := storage.PullRequestStorage.Select(&prID)
+				reviewers := prs[0].AssignedReviewers
+				if len(reviewers) != 2 || reviewers[0] != "u3" || reviewers[1] != "u4" {
+					t.Errorf("expected [u3 u4], got %v", reviewers)
+				}
+				reviews, _ := storage.PullRequestStorage.SelectUserPullRequestsReviews("u2")
+				if len(reviews) != 0 {
+					t.Errorf("expected u2 to have no reviews, got %d", len(reviews))
+				}
+			},
+		},
+		{
+			name: "reviewer changes are kept as history with their reason",
+			run: func(storage *db.Storage) error {
+				if err := storage.PullRequestStorage.Create(newPR("pr1", "u2", "u3")); err != nil {
+					return err
+				}
+				return storage.PullRequestStorage.Reassign(newPR("pr1", "u3", "u4"), domain.AssignmentReassign)
+			},
+			validate: func(t *testing.T, storage *db.Storage) {
+				history, _ := storage.PullRequestStorage.SelectHistory("pr1")
+				var got []string
+				for _, event := range history {
+					got = append(got, fmt.Sprintf("%s %s %s", event.Action, event.ReviewerID, event.Reason))
+				}
+				want := []string{
+					"assigned u2 initial",
+					"assigned u3 initial",
+					"unassigned u2 reassign",
+					"assigned u4 reassign",
+				}
+				if !slices.Equal(got, want) {
+					t.Errorf("expected history %v, got %v", want, got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewStore()
+			seedTeam(t, store, "backend", "u1", "u2", "u3", "u4")
+
+			err := store.WithTransaction(tt.run, false)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.validate != nil {
+				store.WithTransaction(func(storage *db.Storage) error {
+					tt.validate(t, storage)
+					return nil
+				}, true)
+			}
+		})
+	}
+}
+
+func TestStore_FailedTransactionIsRolledBack(t *testing.T) {
+	store := NewStore()
+	seedTeam(t, store, "backend", "u1", "u2")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		return storage.PullRequestStorage.Create(domain.PullRequest{
+			PullRequestShort:  domain.PullRequestShort{ID: "pr1", Name: "pr1", AuthorID: "u1", Status: domain.Open},
+			AssignedReviewers: []string{"u2"},
+		})
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	errRollback := errors.New("rollback")
+	err = store.WithTransaction(func(storage *db.Storage) error {
+		storage.PullRequestStorage.Merge(domain.PullRequest{PullRequestShort: domain.PullRequestShort{ID: "pr1"}})
+		return errRollback
+	}, false)
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		prs, _ := storage.PullRequestStorage.Select(nil)
+		if len(prs) != 1 || prs[0].Status != domain.Open {
+			t.Errorf("expected open PR after rollback, got %+v", prs)
+		}
+		return nil
+	}, true)
+}
+
+func TestStore_RollbackLeavesSharedLogsIntact(t *testing.T) {
+	store := NewStore()
+	seedTeam(t, store, "backend", "u1", "u2")
+	store.WithTransaction(func(storage *db.Storage) error {
+		storage.AuditStorage.Insert(domain.AuditEvent{Action: domain.AuditTeamCreated, EntityID: "committed"})
+		return storage.PullRequestStorage.Create(domain.PullRequest{
+			PullRequestShort:  domain.PullRequestShort{ID: "pr1", Name: "pr1", AuthorID: "u1", Status: domain.Open},
+			AssignedReviewers: []string{"u2"},
+		})
+	}, false)
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		storage.AuditStorage.Insert(domain.AuditEvent{Action: domain.AuditTeamCreated, EntityID: "rolled-back"})
+		storage.PullRequestStorage.SubmitReview("pr1", domain.Review{ReviewerID: "u2", State: domain.ReviewApproved})
+		return errors.New("rollback")
+	}, false)
+	store.WithTransaction(func(storage *db.Storage) error {
+		return storage.AuditStorage.Insert(domain.AuditEvent{Action: domain.AuditTeamCreated, EntityID: "next"})
+	}, false)
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		events, _ := storage.AuditStorage.Select(domain.AuditFilter{Limit: 10})
+		var actions []string
+		for _, event := range events {
+			actions = append(actions, event.EntityID)
+		}
+		if !slices.Equal(actions, []string{"next", "committed"}) {
+			t.Errorf("expected only committed audit events, got %v", actions)
+		}
+		prs, _ := storage.PullRequestStorage.Select(nil)
+		if len(prs) != 1 || prs[0].Reviews[0].State != domain.ReviewPending {
+			t.Errorf("expected the rolled back review to be discarded, got %+v", prs)
+		}
+		return nil
+	}, true)
+}
+
+func TestStore_SavepointUndoesOnlyItsChanges(t *testing.T) {
+	store := NewStore()
+	seedTeam(t, store, "backend", "u1", "u2")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		storage.AuditStorage.Insert(domain.AuditEvent{Action: domain.AuditTeamCreated, EntityID: "before"})
+		err := storage.SavepointStorage.Savepoint(func() error {
+			storage.AuditStorage.Insert(domain.AuditEvent{Action: domain.AuditTeamCreated, EntityID: "failed"})
+			storage.PullRequestStorage.Create(domain.PullRequest{
+				PullRequestShort: domain.PullRequestShort{ID: "pr1", Name: "pr1", AuthorID: "u1", Status: domain.Open},
+			})
+			return errors.New("rollback")
+		})
+		if err == nil {
+			t.Error("expected the savepoint to return the error")
+		}
+		return storage.AuditStorage.Insert(domain.AuditEvent{Action: domain.AuditTeamCreated, EntityID: "after"})
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		events, _ := storage.AuditStorage.Select(domain.AuditFilter{Limit: 10})
+		var ids []string
+		for _, event := range events {
+			ids = append(ids, event.EntityID)
+		}
+		if !slices.Equal(ids, []string{"after", "before"}) {
+			t.Errorf("expected the changes around the savepoint kept, got %v", ids)
+		}
+		if prs, _ := storage.PullRequestStorage.Select(nil); len(prs) != 0 {
+			t.Errorf("expected the pull request of the failed savepoint discarded, got %+v", prs)
+		}
+		return nil
+	}, true)
+}
+
+func TestStore_ConcurrentWrites(t *testing.T) {
+	store := NewStore()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.WithTransaction(func(storage *db.Storage) error {
+				return storage.UserStorage.Insert(domain.User{UserID: fmt.Sprintf("u%d", i), TeamName: "team"})
+			}, false)
+		}()
+	}
+	wg.Wait()
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		users, _ := storage.UserStorage.Select(nil)
+		if len(users) != 50 {
+			t.Errorf("expected 50 users, got %d", len(users))
+		}
+		return nil
+	}, true)
+}
+
+func TestStore_Absences(t *testing.T) {
+	store := NewStore()
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	seedTeam(t, store, "backend", "u1", "u2", "u3")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		absences := storage.AbsenceStorage
+		absences.Insert(domain.Absence{UserID: "u2", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)})
+		absences.Insert(domain.Absence{UserID: "u3", StartsAt: now.Add(-2 * time.Hour), EndsAt: now})
+		_, err := absences.Insert(domain.Absence{UserID: "u2", StartsAt: now.Add(-3 * time.Hour), EndsAt: now.Add(-2 * time.Hour)})
+		return err
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed absences: %v", err)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		absent, err := storage.AbsenceStorage.SelectAbsentUserIDs()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(absent, []string{"u2"}) {
+			t.Errorf("expected only u2 to be absent, as the end is excluded, got %v", absent)
+		}
+
+		absences, _ := storage.AbsenceStorage.Select("u2")
+		if len(absences) != 2 || absences[0].ID != 3 || absences[1].ID != 1 {
+			t.Errorf("expected absences of u2 ordered by start, got %+v", absences)
+		}
+
+		teams, _ := storage.StatsStorage.SelectTeamActivity(domain.StatsFilter{})
+		if len(teams) != 1 || teams[0].ActiveMembers != 3 || teams[0].AbsentMembers != 1 {
+			t.Errorf("expected 1 of 3 active members to be absent, got %+v", teams)
+		}
+		return nil
+	}, true)
+}
+
+func TestStore_ReviewerPools(t *testing.T) {
+	store := NewStore()
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	seedTeam(t, store, "backend", "u1", "u2")
+	seedTeam(t, store, "security", "s1")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		if err := storage.PoolStorage.Upsert(domain.ReviewerPool{Name: "security", Members: []string{"s1", "u2", "s1"}}); err != nil {
+			return err
+		}
+		return storage.TeamStorage.Delete("security")
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed pool: %v", err)
+	}
+
+	errRollback := errors.New("rollback")
+	store.WithTransaction(func(storage *db.Storage) error {
+		storage.PoolStorage.Upsert(domain.ReviewerPool{Name: "security"})
+		return errRollback
+	}, false)
+
+	now = now.Add(time.Hour)
+	store.WithTransaction(func(storage *db.Storage) error {
+		_, err := storage.TeamStorage.Purge(0)
+		return err
+	}, false)
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		pools, _ := storage.PoolStorage.Select("security")
+		if len(pools) != 1 || !slices.Equal(pools[0].Members, []string{"u2"}) {
+			t.Errorf("expected purged s1 to leave the pool, got %+v", pools)
+		}
+		return nil
+	}, true)
+}
+
+func TestStore_PurgeRecordsUnassignments(t *testing.T) {
+	store := NewStore()
+	seedTeam(t, store, "backend", "u1", "u2")
+	seedTeam(t, store, "security", "s1")
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		err := storage.PullRequestStorage.Create(domain.PullRequest{
+			PullRequestShort:  domain.PullRequestShort{ID: "pr1", Name: "pr1", AuthorID: "u1", Status: domain.Open},
+			AssignedReviewers: []string{"u2", "s1"},
+		})
+		if err != nil {
+			return err
+		}
+		if err = storage.TeamStorage.Delete("security"); err != nil {
+			return err
+		}
+		_, err = storage.TeamStorage.Purge(0)
+		return err
+	}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		history, _ := storage.PullRequestStorage.SelectHistory("pr1")
+		last := history[len(history)-1]
+		if last.ReviewerID != "s1" || last.Action != domain.Unassigned || last.Reason != domain.AssignmentTeamPurged {
+			t.Errorf("expected the purged reviewer to be unassigned in history, got %+v", history)
+		}
+		return nil
+	}, true)
+}
+
+func TestStore_StaleReviews(t *testing.T) {
+	store := NewStore()
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	seedTeam(t, store, "backend", "u1", "u2", "u3")
+
+	err := store.WithTransaction(func(storage *db.Storage) error {
+		if err := storage.SettingsStorage.Upsert(domain.TeamSettings{TeamName: "backend", ReviewersRequired: 2, ReviewSLAHours: 24, SLAAction: domain.SLAReassign}); err != nil {
+			return err
+		}
+		return storage.PullRequestStorage.Create(domain.PullRequest{
+			PullRequestShort:  domain.PullRequestShort{ID: "pr1", Name: "Test", AuthorID: "u1", Status: domain.Open},
+			AssignedReviewers: []string{"u2", "u3"},
+		})
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to seed pull request: %v", err)
+	}
+
+	staleReviewers := func() []string {
+		var reviewers []string
+		store.WithTransaction(func(storage *db.Storage) error {
+			reviews, err := storage.EscalationStorage.SelectStaleReviews()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, review := range reviews {
+				reviewers = append(reviewers, review.ReviewerID)
+			}
+			return nil
+		}, true)
+		return reviewers
+	}
+
+	now = now.Add(23 * time.Hour)
+	if reviewers := staleReviewers(); len(reviewers) != 0 {
+		t.Errorf("expected no stale reviews within the SLA, got %v", reviewers)
+	}
+
+	now = now.Add(time.Hour)
+	store.WithTransaction(func(storage *db.Storage) error {
+		return storage.PullRequestStorage.SubmitReview("pr1", domain.Review{ReviewerID: "u3", State: domain.ReviewCommented})
+	}, false)
+	if reviewers := staleReviewers(); !slices.Equal(reviewers, []string{"u2"}) {
+		t.Errorf("expected only the pending review of u2 to be stale, got %v", reviewers)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		return storage.EscalationStorage.Insert(domain.Escalation{PullRequestID: "pr1", ReviewerID: "u2", Action: domain.SLAEscalate})
+	}, false)
+	if reviewers := staleReviewers(); len(reviewers) != 0 {
+		t.Errorf("expected the escalated review not to be stale, got %v", reviewers)
+	}
+}
+
+func TestStore_Outbox(t *testing.T) {
+	store := NewStore()
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		storage.OutboxStorage.Insert(domain.WebhookEvent{ID: "rolled-back", Type: domain.EventTeamDeleted})
+		return errors.New("rollback")
+	}, false)
+	store.WithTransaction(func(storage *db.Storage) error {
+		for _, id := range []string{"e1", "e2"} {
+			if err := storage.OutboxStorage.Insert(domain.WebhookEvent{ID: id, Type: domain.EventTeamDeleted}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, false)
+
+	claim := func() []string {
+		var ids []string
+		store.WithTransaction(func(storage *db.Storage) error {
+			messages, err := storage.OutboxStorage.Claim(10, 2, time.Minute)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, message := range messages {
+				ids = append(ids, message.Event.ID)
+			}
+			return nil
+		}, false)
+		return ids
+	}
+
+	if ids := claim(); !slices.Equal(ids, []string{"e1", "e2"}) {
+		t.Fatalf("expected e1 and e2 to be claimed, got %v", ids)
+	}
+	if ids := claim(); len(ids) != 0 {
+		t.Errorf("expected claimed messages to be leased, got %v", ids)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		storage.OutboxStorage.MarkPublished(1)
+		return storage.OutboxStorage.MarkFailed(2, "unreachable", time.Second)
+	}, false)
+	now = now.Add(time.Second)
+	if ids := claim(); !slices.Equal(ids, []string{"e2"}) {
+		t.Errorf("expected the failed message to be due again, got %v", ids)
+	}
+
+	store.WithTransaction(func(storage *db.Storage) error {
+		return storage.OutboxStorage.MarkFailed(2, "unreachable", 0)
+	}, false)
+	if ids := claim(); len(ids) != 0 {
+		t.Errorf("expected no message to be claimed after max attempts, got %v", ids)
+	}
+}
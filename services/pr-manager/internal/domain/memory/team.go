@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type TeamStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+func (s *TeamStorage) Select(teamName *string) ([]domain.Team, error) {
+	byName := make(map[string]*domain.Team)
+	for _, u := range s.state.activeUsers() {
+		if u.TeamName == "" || teamName != nil && u.TeamName != *teamName {
+			continue
+		}
+		team, ok := byName[u.TeamName]
+		if !ok {
+			team = &domain.Team{TeamName: u.TeamName}
+			byName[u.TeamName] = team
+		}
+		team.Members = append(team.Members, domain.TeamMember{
+			UserID:   u.UserID,
+			Username: u.Username,
+			IsActive: u.IsActive,
+		})
+	}
+
+	var teams []domain.Team
+	for _, team := range byName {
+		teams = append(teams, *team)
+	}
+	slices.SortFunc(teams, func(a, b domain.Team) int {
+		return strings.Compare(a.TeamName, b.TeamName)
+	})
+	return teams, nil
+}
+
+func (s *TeamStorage) Insert(team domain.Team) error {
+	users := &UserStorage{state: s.state}
+	for _, member := range team.Members {
+		userID := member.UserID
+		existingUsers, err := users.Select(&userID)
+		if err == nil && len(existingUsers) > 0 && existingUsers[0].TeamName != "" {
+			return domain.ErrUserInAnotherTeam
+		}
+	}
+
+	for _, member := range team.Members {
+		err := s.AddMember(team.TeamName, member)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TeamStorage) AddMember(teamName string, member domain.TeamMember) error {
+	record, ok := s.state.users[member.UserID]
+	if !ok {
+		s.state.nextSeq++
+		record.seq = s.state.nextSeq
+	}
+	record.User = domain.User{
+		UserID:   member.UserID,
+		Username: member.Username,
+		TeamName: teamName,
+		IsActive: member.IsActive,
+	}
+	record.teamDeleted = false
+	record.teamDeletedAt = time.Time{}
+	s.state.users[member.UserID] = record
+	return nil
+}
+
+func (s *TeamStorage) SetMemberTeam(userID string, teamName *string) error {
+	record, ok := s.state.users[userID]
+	if !ok {
+		return nil
+	}
+	record.TeamName = ""
+	if teamName != nil {
+		record.TeamName = *teamName
+	}
+	record.teamDeleted = false
+	record.teamDeletedAt = time.Time{}
+	s.state.users[userID] = record
+	return nil
+}
+
+func (s *TeamStorage) Delete(teamName string) error {
+	now := s.now()
+	for id, u := range s.state.users {
+		if u.TeamName != "" && u.TeamName == teamName && !u.teamDeleted {
+			u.teamDeleted = true
+			u.teamDeletedAt = now
+			s.state.users[id] = u
+		}
+	}
+	return nil
+}
+
+func (s *TeamStorage) SelectDeleted(teamName *string) ([]domain.DeletedTeam, error) {
+	byName := make(map[string]*domain.DeletedTeam)
+	for _, u := range s.state.sortedUsers() {
+		if !u.teamDeleted || u.TeamName == "" || teamName != nil && u.TeamName != *teamName {
+			continue
+		}
+		team, ok := byName[u.TeamName]
+		if !ok {
+			deletedAt := u.teamDeletedAt
+			team = &domain.DeletedTeam{Team: domain.Team{TeamName: u.TeamName}, DeletedAt: &deletedAt}
+			byName[u.TeamName] = team
+		}
+		if u.teamDeletedAt.After(*team.DeletedAt) {
+			deletedAt := u.teamDeletedAt
+			team.DeletedAt = &deletedAt
+		}
+		team.Members = append(team.Members, domain.TeamMember{
+			UserID:   u.UserID,
+			Username: u.Username,
+			IsActive: u.IsActive,
+		})
+	}
+
+	var teams []domain.DeletedTeam
+	for _, team := range byName {
+		teams = append(teams, *team)
+	}
+	slices.SortFunc(teams, func(a, b domain.DeletedTeam) int {
+		return strings.Compare(a.TeamName, b.TeamName)
+	})
+	return teams, nil
+}
+
+func (s *TeamStorage) Restore(teamName string) error {
+	for id, u := range s.state.users {
+		if u.TeamName == teamName && u.teamDeleted {
+			u.teamDeleted = false
+			u.teamDeletedAt = time.Time{}
+			s.state.users[id] = u
+		}
+	}
+	return nil
+}
+
+func (s *TeamStorage) Purge(retention time.Duration) ([]string, error) {
+	now := s.now()
+	cutoff := now.Add(-retention)
+	purged := make(map[string]bool)
+	var teamNames []string
+	for id, u := range s.state.users {
+		if !u.teamDeleted || u.teamDeletedAt.After(cutoff) {
+			continue
+		}
+		purged[id] = true
+		if !slices.Contains(teamNames, u.TeamName) {
+			teamNames = append(teamNames, u.TeamName)
+		}
+	}
+	if len(purged) == 0 {
+		return nil, nil
+	}
+
+	for id, pr := range s.state.pullRequests {
+		if purged[pr.AuthorID] {
+			delete(s.state.pullRequests, id)
+			continue
+		}
+		pr.reviewers = slices.DeleteFunc(slices.Clone(pr.reviewers), func(r reviewerRecord) bool {
+			if !purged[r.userID] {
+				return false
+			}
+			pr.assignments = append(pr.assignments, domain.AssignmentEvent{ReviewerID: r.userID, Action: domain.Unassigned, Reason: domain.AssignmentTeamPurged, CreatedAt: &now})
+			return true
+		})
+		pr.reviews = slices.DeleteFunc(slices.Clone(pr.reviews), func(review reviewRecord) bool {
+			return purged[review.reviewerID]
+		})
+		s.state.pullRequests[id] = pr
+	}
+	s.state.overrides = slices.DeleteFunc(s.state.overrides, func(override domain.MergePolicyOverride) bool {
+		_, ok := s.state.pullRequests[override.PullRequestID]
+		return !ok
+	})
+	s.state.absences = slices.DeleteFunc(s.state.absences, func(absence domain.Absence) bool {
+		return purged[absence.UserID]
+	})
+	s.state.accounts = slices.DeleteFunc(s.state.accounts, func(account domain.ProviderAccount) bool {
+		return purged[account.UserID]
+	})
+	s.state.escalations = slices.DeleteFunc(slices.Clone(s.state.escalations), func(escalation domain.Escalation) bool {
+		_, ok := s.state.pullRequests[escalation.PullRequestID]
+		return !ok
+	})
+	for teamName, settings := range s.state.settings {
+		if settings.TeamLead != nil && purged[*settings.TeamLead] {
+			settings.TeamLead = nil
+			s.state.settings[teamName] = settings
+		}
+	}
+	for name, members := range s.state.pools {
+		s.state.pools[name] = slices.DeleteFunc(slices.Clone(members), func(userID string) bool {
+			return purged[userID]
+		})
+	}
+	for id := range purged {
+		delete(s.state.users, id)
+	}
+	for _, teamName := range teamNames {
+		if !slices.ContainsFunc(s.state.sortedUsers(), func(u userRecord) bool { return u.TeamName == teamName }) {
+			delete(s.state.policies, teamName)
+			delete(s.state.settings, teamName)
+			delete(s.state.codeowners, teamName)
+		}
+	}
+
+	slices.Sort(teamNames)
+	return teamNames, nil
+}
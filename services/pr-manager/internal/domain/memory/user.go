@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type UserStorage struct {
+	state *state
+}
+
+func (s *UserStorage) Select(userID *string) ([]domain.User, error) {
+	var users []domain.User
+	for _, u := range s.state.activeUsers() {
+		if userID == nil || u.UserID == *userID {
+			users = append(users, u.User)
+		}
+	}
+	return users, nil
+}
+
+func (s *UserStorage) Update(user domain.User) error {
+	existing, ok := s.state.users[user.UserID]
+	if !ok {
+		return nil
+	}
+	existing.IsActive = user.IsActive
+	s.state.users[user.UserID] = existing
+	return nil
+}
+
+func (s *UserStorage) Insert(user domain.User) error {
+	if _, ok := s.state.users[user.UserID]; ok {
+		return nil
+	}
+	s.state.nextSeq++
+	s.state.users[user.UserID] = userRecord{User: user, seq: s.state.nextSeq}
+	return nil
+}
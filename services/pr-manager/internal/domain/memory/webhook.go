@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"slices"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type WebhookStorage struct {
+	state *state
+	now   func() time.Time
+}
+
+func (s *WebhookStorage) Insert(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	now := s.now()
+	s.state.lastWebhookID++
+	subscription.ID = s.state.lastWebhookID
+	subscription.Events = slices.Clone(subscription.Events)
+	subscription.CreatedAt = &now
+	s.state.webhooks = append(s.state.webhooks, subscription)
+	return subscription, nil
+}
+
+func (s *WebhookStorage) Select(id int64) ([]domain.WebhookSubscription, error) {
+	return s.filter(func(subscription domain.WebhookSubscription) bool {
+		return subscription.ID == id
+	}), nil
+}
+
+func (s *WebhookStorage) SelectSubscribed(eventType domain.WebhookEventType) ([]domain.WebhookSubscription, error) {
+	return s.filter(func(subscription domain.WebhookSubscription) bool {
+		return subscription.Subscribed(eventType)
+	}), nil
+}
+
+func (s *WebhookStorage) Delete(id int64) error {
+	s.state.webhooks = slices.DeleteFunc(s.state.webhooks, func(subscription domain.WebhookSubscription) bool {
+		return subscription.ID == id
+	})
+	s.state.webhookDeliveries = slices.DeleteFunc(slices.Clone(s.state.webhookDeliveries), func(delivery domain.WebhookDelivery) bool {
+		return delivery.SubscriptionID == id
+	})
+	return nil
+}
+
+func (s *WebhookStorage) InsertDelivery(delivery domain.WebhookDelivery) error {
+	now := s.now()
+	s.state.lastWebhookDeliveryID++
+	delivery.ID = s.state.lastWebhookDeliveryID
+	delivery.CreatedAt = &now
+	s.state.webhookDeliveries = append(s.state.webhookDeliveries, delivery)
+	return nil
+}
+
+func (s *WebhookStorage) SelectDeliveries(subscriptionID int64, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	for i := len(s.state.webhookDeliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if s.state.webhookDeliveries[i].SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, s.state.webhookDeliveries[i])
+		}
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookStorage) SelectEventDeliveries(eventID string) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	for _, delivery := range s.state.webhookDeliveries {
+		if delivery.EventID == eventID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookStorage) filter(keep func(domain.WebhookSubscription) bool) []domain.WebhookSubscription {
+	var subscriptions []domain.WebhookSubscription
+	for _, subscription := range s.state.webhooks {
+		if keep(subscription) {
+			subscription.Events = slices.Clone(subscription.Events)
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions
+}
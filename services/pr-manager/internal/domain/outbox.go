@@ -0,0 +1,10 @@
+package domain
+
+// OutboxMessage is an event written to the outbox in the transaction of the
+// change it reports, waiting to be published. Attempts counts the failed
+// attempts to publish it.
+type OutboxMessage struct {
+	ID       int64
+	Event    WebhookEvent
+	Attempts int
+}
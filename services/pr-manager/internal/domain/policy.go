@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MergePolicy lists the rules a pull request of the team must satisfy before
+// it can be merged. The zero value allows merging any open pull request.
+type MergePolicy struct {
+	TeamName                string `json:"team_name"`
+	MinApprovals            int    `json:"min_approvals"`
+	BlockOnChangesRequested bool   `json:"block_on_changes_requested"`
+	ForbidSelfApproval      bool   `json:"forbid_self_approval"`
+}
+
+type MergePolicyOverride struct {
+	PullRequestID string
+	Reason        string
+	UnmetRules    []string
+	CreatedAt     *time.Time
+}
+
+// UnmetRules returns a description of every rule the pull request violates
+// when merged by mergedBy. A nil mergedBy means the merging user is unknown,
+// which forbid_self_approval does not allow: the author could be merging.
+func (p MergePolicy) UnmetRules(pr PullRequest, mergedBy *string) []string {
+	var unmet []string
+
+	approvals := 0
+	selfApproved := false
+	var changesRequestedBy []string
+	for _, review := range pr.Reviews {
+		switch review.State {
+		case ReviewApproved:
+			if p.ForbidSelfApproval && review.ReviewerID == pr.AuthorID {
+				selfApproved = true
+				continue
+			}
+			approvals++
+		case ReviewChangesRequested:
+			changesRequestedBy = append(changesRequestedBy, review.ReviewerID)
+		}
+	}
+
+	if approvals < p.MinApprovals {
+		unmet = append(unmet, fmt.Sprintf("requires %d approvals, got %d", p.MinApprovals, approvals))
+	}
+	if p.BlockOnChangesRequested && len(changesRequestedBy) > 0 {
+		unmet = append(unmet, fmt.Sprintf("changes requested by %s", strings.Join(changesRequestedBy, ", ")))
+	}
+	if selfApproved {
+		unmet = append(unmet, "author cannot approve own pull request")
+	}
+	if p.ForbidSelfApproval && mergedBy == nil {
+		unmet = append(unmet, "merging user must be known to rule out self-approval")
+	}
+	if p.ForbidSelfApproval && mergedBy != nil && *mergedBy == pr.AuthorID {
+		unmet = append(unmet, "author cannot merge own pull request")
+	}
+	return unmet
+}
+
+// PolicyError is returned when a merge is rejected by the team merge policy.
+type PolicyError struct {
+	UnmetRules []string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPolicyNotSatisfied, strings.Join(e.UnmetRules, "; "))
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrPolicyNotSatisfied
+}
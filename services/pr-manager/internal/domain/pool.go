@@ -0,0 +1,8 @@
+package domain
+
+// ReviewerPool is a named set of users, possibly from different teams, that
+// teams can draw reviewers from in addition to their own members.
+type ReviewerPool struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
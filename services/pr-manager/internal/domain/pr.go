@@ -2,9 +2,15 @@ package domain
 
 import "time"
 
+// PullRequest is reviewed by ReviewersCount reviewers if it is set, and by
+// the number required by the author's team otherwise. ChangedPaths are used to
+// pick owners of the changed code as reviewers.
 type PullRequest struct {
 	PullRequestShort
-	AssignedReviewers string     `json:"assigned_reviewers"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	Reviews           []Review   `json:"reviews"`
+	ReviewersCount    *int       `json:"reviewers_count,omitempty"`
+	ChangedPaths      []string   `json:"changed_paths,omitempty"`
 	CreatedAt         *time.Time `json:"created_at"`
 	MergedAt          *time.Time `json:"merged_at"`
 }
@@ -19,6 +25,8 @@ type PullRequestShort struct {
 type PullRequestStatus string
 
 const (
+	Draft  PullRequestStatus = "draft"
 	Open   PullRequestStatus = "open"
 	Merged PullRequestStatus = "merged"
+	Closed PullRequestStatus = "closed"
 )
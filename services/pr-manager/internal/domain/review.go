@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type Review struct {
+	ReviewerID  string      `json:"reviewer_id"`
+	State       ReviewState `json:"state"`
+	SubmittedAt *time.Time  `json:"submitted_at"`
+}
+
+type ReviewState string
+
+const (
+	ReviewPending          ReviewState = "pending"
+	ReviewApproved         ReviewState = "approved"
+	ReviewChangesRequested ReviewState = "changes_requested"
+	ReviewCommented        ReviewState = "commented"
+)
+
+// ParseReviewState accepts the states a reviewer can submit, in any case.
+func ParseReviewState(state string) (ReviewState, error) {
+	switch s := ReviewState(strings.ToLower(state)); s {
+	case ReviewApproved, ReviewChangesRequested, ReviewCommented:
+		return s, nil
+	default:
+		return "", ErrInvalidReviewState
+	}
+}
+
+// ReviewOf returns the review of an assigned reviewer. Reviewers without a
+// stored review are reported as pending.
+func (pr PullRequest) ReviewOf(reviewerID string) (Review, bool) {
+	for _, review := range pr.Reviews {
+		if review.ReviewerID == reviewerID {
+			return review, true
+		}
+	}
+	for _, reviewer := range pr.AssignedReviewers {
+		if reviewer == reviewerID {
+			return Review{ReviewerID: reviewerID, State: ReviewPending}, true
+		}
+	}
+	return Review{}, false
+}
@@ -1,6 +1,10 @@
 package domain
 
+import "time"
+
 type Stats struct {
+	Filters StatsFilter `json:"filters"`
+
 	UserStats           UserStats                      `json:"user_stats"`
 	IndividualUserStats map[string]IndividualUserStats `json:"individual_user_stats"`
 
@@ -10,9 +14,12 @@ type Stats struct {
 	PullRequestStats PullRequestStats `json:"pull_request_stats"`
 }
 
+// UserStats splits the users into active ones, active ones absent at the
+// moment and inactive ones.
 type UserStats struct {
 	Total    int64 `json:"total"`
 	Active   int64 `json:"active"`
+	Absent   int64 `json:"absent"`
 	Inactive int64 `json:"inactive"`
 }
 
@@ -24,6 +31,10 @@ type IndividualUserStats struct {
 	PRsOpen               int64   `json:"prs_open"`
 	PRsWaitingForReview   int64   `json:"prs_waiting_for_review"`
 	AverageMergeTimeHours float64 `json:"average_merge_time_hours"`
+
+	TimeToFirstReviewP50Hours        float64 `json:"time_to_first_review_p50_hours"`
+	TimeToFirstReviewP90Hours        float64 `json:"time_to_first_review_p90_hours"`
+	AverageChangesRequestedWaitHours float64 `json:"average_changes_requested_wait_hours"`
 }
 
 type TeamStats struct {
@@ -39,9 +50,12 @@ type TeamStats struct {
 	LeastInactiveMembersInTeam    int64   `json:"least_inactive_members_in_team"`
 }
 
+// IndividualTeamStats counts absent members apart from the active ones, as
+// UserStats does.
 type IndividualTeamStats struct {
 	TotalMembers          int64   `json:"total_members"`
 	ActiveMembers         int64   `json:"active_members"`
+	AbsentMembers         int64   `json:"absent_members"`
 	InactiveMembers       int64   `json:"inactive_members"`
 	PRsCreated            int64   `json:"prs_created"`
 	PRsReviewed           int64   `json:"prs_reviewed"`
@@ -49,6 +63,10 @@ type IndividualTeamStats struct {
 	PRsOpen               int64   `json:"prs_open"`
 	PRsWaitingForReview   int64   `json:"prs_waiting_for_review"`
 	AverageMergeTimeHours float64 `json:"average_merge_time_hours"`
+
+	TimeToFirstReviewP50Hours        float64 `json:"time_to_first_review_p50_hours"`
+	TimeToFirstReviewP90Hours        float64 `json:"time_to_first_review_p90_hours"`
+	AverageChangesRequestedWaitHours float64 `json:"average_changes_requested_wait_hours"`
 }
 
 type PullRequestStats struct {
@@ -61,3 +79,85 @@ type PullRequestStats struct {
 	MostPRsPerReviewer    int64   `json:"most_prs_per_reviewer"`
 	LeastPRsPerReviewer   int64   `json:"least_prs_per_reviewer"`
 }
+
+// UserActivity is what the statistics know about a user, aggregated over all
+// pull requests.
+type UserActivity struct {
+	UserID                string
+	Username              string
+	TeamName              string
+	IsActive              bool
+	IsAbsent              bool
+	PRsCreated            int64
+	PRsReviewed           int64
+	PRsMerged             int64
+	PRsOpen               int64
+	PRsWaitingForReview   int64
+	AverageMergeTimeHours float64
+
+	// TimeToFirstReview* are percentiles of the hours between assignment and
+	// the first review of the user as a reviewer.
+	TimeToFirstReviewP50Hours float64
+	TimeToFirstReviewP90Hours float64
+	// AverageChangesRequestedWaitHours is the average number of hours the
+	// user's pull requests waited for the next review after changes were
+	// requested.
+	AverageChangesRequestedWaitHours float64
+}
+
+// TeamActivity is what the statistics know about a team, aggregated over its
+// members and the pull requests they authored. AbsentMembers are the active
+// members absent at the moment.
+type TeamActivity struct {
+	TeamName              string
+	TotalMembers          int64
+	ActiveMembers         int64
+	AbsentMembers         int64
+	AverageMergeTimeHours float64
+
+	TimeToFirstReviewP50Hours        float64
+	TimeToFirstReviewP90Hours        float64
+	AverageChangesRequestedWaitHours float64
+}
+
+type PullRequestTotals struct {
+	Total                 int64
+	AverageMergeTimeHours float64
+}
+
+// StatsFilter narrows the statistics down to a time window and to the members
+// of a team or a single user. Unset fields do not filter.
+//
+// Pull requests count towards the window by creation time, merges and merge
+// time by merge time, reviews and time to first review by submission time,
+// pending reviews by assignment time and waits after requested changes by the
+// time of the next review. The window includes From and excludes To.
+type StatsFilter struct {
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+	TeamName *string    `json:"team,omitempty"`
+	UserID   *string    `json:"user_id,omitempty"`
+}
+
+// InWindow reports whether t falls into the time window. Unknown times are
+// only in an unbounded window.
+func (f StatsFilter) InWindow(t *time.Time) bool {
+	if f.From == nil && f.To == nil {
+		return true
+	}
+	if t == nil {
+		return false
+	}
+	return (f.From == nil || !t.Before(*f.From)) && (f.To == nil || t.Before(*f.To))
+}
+
+// SelectsUser reports whether the user is one of the users the statistics are
+// narrowed down to.
+func (f StatsFilter) SelectsUser(user User) bool {
+	return (f.TeamName == nil || user.TeamName == *f.TeamName) && (f.UserID == nil || user.UserID == *f.UserID)
+}
+
+// SelectsUsers reports whether the filter narrows the statistics down to some users.
+func (f StatsFilter) SelectsUsers() bool {
+	return f.TeamName != nil || f.UserID != nil
+}
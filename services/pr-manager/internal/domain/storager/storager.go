@@ -1,13 +1,11 @@
 package storager
 
 import (
+	"time"
+
 	"github.com/zemld/pr-manager/pr-manager/internal/domain"
 )
 
-type Initializer interface {
-	Initialize() error
-}
-
 type Transactor interface {
 	Begin() error
 	Commit() error
@@ -36,6 +34,11 @@ type TeamStorager interface {
 	TeamSelector
 	TeamInserter
 	TeamDeleter
+	TeamMemberAdder
+	TeamMemberMover
+	DeletedTeamSelector
+	TeamRestorer
+	TeamPurger
 }
 
 type TeamSelector interface {
@@ -50,18 +53,46 @@ type TeamDeleter interface {
 	Delete(teamName string) error
 }
 
+type TeamMemberAdder interface {
+	AddMember(teamName string, member domain.TeamMember) error
+}
+
+type TeamMemberMover interface {
+	SetMemberTeam(userID string, teamName *string) error
+}
+
+type DeletedTeamSelector interface {
+	SelectDeleted(teamName *string) ([]domain.DeletedTeam, error)
+}
+
+type TeamRestorer interface {
+	Restore(teamName string) error
+}
+
+// TeamPurger hard-deletes teams deleted at least retention ago, together with
+// the pull requests their members authored. It returns the purged team names.
+type TeamPurger interface {
+	Purge(retention time.Duration) ([]string, error)
+}
+
 type PullRequestStorager interface {
 	PullRequestSelector
 	PullRequestCreator
 	PullRequestMerger
 	PullRequestReassigner
 	UserPullRequestReviewer
+	UserPullRequestReviewsCounter
+	PullRequestReviewSubmitter
+	PullRequestStatusUpdater
+	PullRequestHistorySelector
 }
 
 type PullRequestSelector interface {
 	Select(pullRequestID *string) ([]domain.PullRequest, error)
 }
 
+// PullRequestCreator creates the pull request. Its reviewers are recorded in
+// the assignment history as the initial assignment.
 type PullRequestCreator interface {
 	Create(pullRequest domain.PullRequest) error
 }
@@ -70,10 +101,250 @@ type PullRequestMerger interface {
 	Merge(pullRequest domain.PullRequest) error
 }
 
+// PullRequestReassigner makes the assigned reviewers of the pull request
+// exactly its AssignedReviewers and records the changes in the assignment
+// history with the reason.
 type PullRequestReassigner interface {
-	Reassign(pullRequest domain.PullRequest) error
+	Reassign(pullRequest domain.PullRequest, reason domain.AssignmentReason) error
+}
+
+// PullRequestHistorySelector returns the assignment history of the pull
+// request, oldest first.
+type PullRequestHistorySelector interface {
+	SelectHistory(pullRequestID string) ([]domain.AssignmentEvent, error)
 }
 
 type UserPullRequestReviewer interface {
 	SelectUserPullRequestsReviews(userID string) ([]domain.PullRequest, error)
 }
+
+type UserPullRequestReviewsCounter interface {
+	CountUserOpenReviews(userIDs []string) (map[string]int64, error)
+}
+
+type PullRequestReviewSubmitter interface {
+	SubmitReview(pullRequestID string, review domain.Review) error
+}
+
+type PullRequestStatusUpdater interface {
+	UpdateStatus(pullRequestID string, status domain.PullRequestStatus) error
+}
+
+type MergePolicyStorager interface {
+	MergePolicySelector
+	MergePolicyUpserter
+	MergePolicyOverrideInserter
+}
+
+type MergePolicySelector interface {
+	Select(teamName string) ([]domain.MergePolicy, error)
+}
+
+type MergePolicyUpserter interface {
+	Upsert(policy domain.MergePolicy) error
+}
+
+type MergePolicyOverrideInserter interface {
+	InsertOverride(override domain.MergePolicyOverride) error
+}
+
+type ReviewerPoolStorager interface {
+	ReviewerPoolSelector
+	ReviewerPoolUpserter
+}
+
+type ReviewerPoolSelector interface {
+	Select(name string) ([]domain.ReviewerPool, error)
+}
+
+// ReviewerPoolUpserter creates the pool or replaces its members.
+type ReviewerPoolUpserter interface {
+	Upsert(pool domain.ReviewerPool) error
+}
+
+type CodeownersStorager interface {
+	CodeownersSelector
+	CodeownersUpserter
+}
+
+type CodeownersSelector interface {
+	Select(teamName string) ([]domain.Codeowners, error)
+}
+
+type CodeownersUpserter interface {
+	Upsert(codeowners domain.Codeowners) error
+}
+
+type TeamSettingsStorager interface {
+	TeamSettingsSelector
+	TeamSettingsUpserter
+}
+
+type TeamSettingsSelector interface {
+	Select(teamName string) ([]domain.TeamSettings, error)
+}
+
+type TeamSettingsUpserter interface {
+	Upsert(settings domain.TeamSettings) error
+}
+
+// StatsStorager returns aggregates for the statistics. Users and teams of
+// deleted teams are not included, their pull requests are.
+type StatsStorager interface {
+	SelectUserActivity(filter domain.StatsFilter) ([]domain.UserActivity, error)
+	SelectTeamActivity(filter domain.StatsFilter) ([]domain.TeamActivity, error)
+	SelectPullRequestTotals(filter domain.StatsFilter) (domain.PullRequestTotals, error)
+}
+
+type AuditStorager interface {
+	AuditInserter
+	AuditSelector
+}
+
+type AuditInserter interface {
+	Insert(event domain.AuditEvent) error
+}
+
+type AuditSelector interface {
+	Select(filter domain.AuditFilter) ([]domain.AuditEvent, error)
+}
+
+// AbsenceStorager keeps scheduled absences. Currently absent users are
+// decided by the clock of the storage.
+type AbsenceStorager interface {
+	AbsenceInserter
+	AbsenceSelector
+	AbsentUserSelector
+}
+
+type AbsenceInserter interface {
+	Insert(absence domain.Absence) (domain.Absence, error)
+}
+
+type AbsenceSelector interface {
+	Select(userID string) ([]domain.Absence, error)
+}
+
+type AbsentUserSelector interface {
+	SelectAbsentUserIDs() ([]string, error)
+}
+
+// EscalationStorager finds stale reviews and records what was done about
+// them. A review stays stale until it is escalated, so it is acted on once per
+// assignment. Stale reviews are decided by the clock of the storage.
+type EscalationStorager interface {
+	StaleReviewSelector
+	EscalationInserter
+}
+
+type StaleReviewSelector interface {
+	SelectStaleReviews() ([]domain.StaleReview, error)
+}
+
+type EscalationInserter interface {
+	Insert(escalation domain.Escalation) error
+}
+
+// Locker takes locks held until the end of the unit of work. A lock taken by
+// another unit of work is not waited for.
+type Locker interface {
+	TryLock(key int64) (bool, error)
+}
+
+// Savepointer runs a part of the unit of work whose changes are undone if it
+// fails, while the rest of the unit of work goes on.
+type Savepointer interface {
+	Savepoint(fn func() error) error
+}
+
+type WebhookStorager interface {
+	WebhookInserter
+	WebhookSelector
+	WebhookDeleter
+	WebhookDeliveryInserter
+	WebhookDeliverySelector
+	WebhookEventDeliverySelector
+}
+
+type WebhookInserter interface {
+	Insert(subscription domain.WebhookSubscription) (domain.WebhookSubscription, error)
+}
+
+// WebhookSelector returns subscriptions by ID, oldest first. SelectSubscribed
+// returns those receiving events of the type.
+type WebhookSelector interface {
+	Select(id int64) ([]domain.WebhookSubscription, error)
+	SelectSubscribed(eventType domain.WebhookEventType) ([]domain.WebhookSubscription, error)
+}
+
+// WebhookDeleter deletes the subscription together with its delivery log.
+type WebhookDeleter interface {
+	Delete(id int64) error
+}
+
+type WebhookDeliveryInserter interface {
+	InsertDelivery(delivery domain.WebhookDelivery) error
+}
+
+// WebhookDeliverySelector returns at most limit deliveries of the
+// subscription, newest first.
+type WebhookDeliverySelector interface {
+	SelectDeliveries(subscriptionID int64, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// WebhookEventDeliverySelector returns every delivery of the event, oldest
+// first.
+type WebhookEventDeliverySelector interface {
+	SelectEventDeliveries(eventID string) ([]domain.WebhookDelivery, error)
+}
+
+// OutboxStorager keeps events until they are published. Insert is part of
+// the unit of work of the change the event reports. A message claimed by
+// Claim is not claimed again for lease, so it is retried if the claimant
+// neither marks it published nor failed in time.
+type OutboxStorager interface {
+	OutboxInserter
+	OutboxClaimer
+	OutboxMarker
+	OutboxCleaner
+}
+
+type OutboxInserter interface {
+	Insert(event domain.WebhookEvent) error
+}
+
+// OutboxClaimer claims at most limit unpublished messages, oldest first,
+// that are due and have failed fewer than maxAttempts times.
+type OutboxClaimer interface {
+	Claim(limit int, maxAttempts int, lease time.Duration) ([]domain.OutboxMessage, error)
+}
+
+// OutboxMarker records the outcome of publishing a claimed message. A failed
+// message is due again after retryAfter.
+type OutboxMarker interface {
+	MarkPublished(id int64) error
+	MarkFailed(id int64, reason string, retryAfter time.Duration) error
+}
+
+// OutboxCleaner deletes messages published at least retention ago and
+// returns how many were deleted.
+type OutboxCleaner interface {
+	DeletePublished(retention time.Duration) (int64, error)
+}
+
+type AccountStorager interface {
+	AccountUpserter
+	AccountSelector
+}
+
+// AccountUpserter links the login to the user, replacing an earlier link of
+// the login.
+type AccountUpserter interface {
+	Upsert(account domain.ProviderAccount) error
+}
+
+// AccountSelector returns the accounts of the provider ordered by login. A
+// nil login does not filter.
+type AccountSelector interface {
+	Select(provider domain.Provider, login *string) ([]domain.ProviderAccount, error)
+}
@@ -1,12 +1,53 @@
 package domain
 
+import "time"
+
 type Team struct {
 	TeamName string       `json:"team_name"`
 	Members  []TeamMember `json:"members"`
 }
 
+// DeletedTeam is a soft-deleted team that can still be restored.
+type DeletedTeam struct {
+	Team
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
 type TeamMember struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
 }
+
+const (
+	// DefaultReviewersRequired is the number of reviewers assigned to a pull
+	// request if neither its team nor the pull request asks for another.
+	DefaultReviewersRequired = 2
+	MaxReviewersRequired     = 10
+)
+
+// TeamSettings configure how pull requests of the team are reviewed.
+// ReviewersRequired reviewers come from the team itself and, if ReviewerPool
+// is set, PoolReviewersRequired more from that pool. Reviews pending for
+// ReviewSLAHours are handled with SLAAction; zero hours disable the SLA.
+type TeamSettings struct {
+	TeamName              string    `json:"team_name"`
+	ReviewersRequired     int       `json:"reviewers_required"`
+	ReviewerPool          *string   `json:"reviewer_pool,omitempty"`
+	PoolReviewersRequired int       `json:"pool_reviewers_required"`
+	ReviewSLAHours        int       `json:"review_sla_hours"`
+	SLAAction             SLAAction `json:"sla_action,omitempty"`
+	TeamLead              *string   `json:"team_lead,omitempty"`
+}
+
+// ValidReviewersCount reports whether count reviewers can be required.
+func ValidReviewersCount(count int) bool {
+	return count >= 1 && count <= MaxReviewersRequired
+}
+
+// Reassignment records a review handed over when a reviewer left the team.
+// ReplacedBy is empty if nobody in the team could take it over.
+type Reassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReplacedBy    string `json:"replaced_by"`
+}
@@ -6,3 +6,11 @@ type User struct {
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
 }
+
+// UserStatusResult is the outcome of one user in a bulk status update. Err is
+// set if the user could not be updated; the other users are updated anyway.
+type UserStatusResult struct {
+	User          User
+	Reassignments []Reassignment
+	Err           error
+}
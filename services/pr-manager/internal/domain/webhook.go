@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"net/netip"
+	"slices"
+	"time"
+)
+
+type WebhookEventType string
+
+const (
+	EventPullRequestCreated    WebhookEventType = "pr.created"
+	EventPullRequestMerged     WebhookEventType = "pr.merged"
+	EventPullRequestReassigned WebhookEventType = "pr.reassigned"
+	EventTeamDeleted           WebhookEventType = "team.deleted"
+	EventUserDeactivated       WebhookEventType = "user.deactivated"
+)
+
+// ValidWebhookEventType reports whether webhooks can subscribe to the event
+// type.
+func ValidWebhookEventType(eventType WebhookEventType) bool {
+	switch eventType {
+	case EventPullRequestCreated, EventPullRequestMerged, EventPullRequestReassigned, EventTeamDeleted, EventUserDeactivated:
+		return true
+	default:
+		return false
+	}
+}
+
+// WebhookEvent is the JSON payload posted to the webhooks subscribed to its
+// type. ID is the same for every attempt of every delivery of the event, so
+// receivers can drop duplicates.
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      map[string]any   `json:"data"`
+}
+
+// WebhookSubscription asks for the events of the given types to be posted to
+// URL. Payloads are signed with Secret, which is never returned.
+type WebhookSubscription struct {
+	ID        int64              `json:"id"`
+	URL       string             `json:"url"`
+	Events    []WebhookEventType `json:"events"`
+	Secret    string             `json:"-"`
+	CreatedAt *time.Time         `json:"created_at,omitempty"`
+}
+
+// Subscribed reports whether the subscription receives events of the type.
+func (s WebhookSubscription) Subscribed(eventType WebhookEventType) bool {
+	return slices.Contains(s.Events, eventType)
+}
+
+// WebhookDelivery records one attempt to post an event to a webhook.
+// StatusCode is nil if no response was received, Error is nil if the webhook
+// accepted the event.
+type WebhookDelivery struct {
+	ID             int64            `json:"id"`
+	SubscriptionID int64            `json:"subscription_id"`
+	EventID        string           `json:"event_id"`
+	EventType      WebhookEventType `json:"event_type"`
+	Attempt        int              `json:"attempt"`
+	StatusCode     *int             `json:"status_code"`
+	Error          *string          `json:"error"`
+	CreatedAt      *time.Time       `json:"created_at,omitempty"`
+}
+
+// PublicAddress reports whether webhooks may be posted to the address without
+// opting in: loopback, link-local, private and unspecified addresses belong to
+// the network the service runs in.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsUnspecified()
+}
@@ -0,0 +1,171 @@
+// Package integration reads pull request events from the webhooks of code
+// hosting providers.
+//
+// GitHub signs the body as X-Hub-Signature-256: sha256=<hex HMAC-SHA256 of
+// the body keyed by the secret>. GitLab sends the secret token as is in
+// X-Gitlab-Token. Events other than pull (merge) request changes the service
+// tracks are reported as not relevant, so they can be acknowledged and
+// dropped.
+package integration
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/webhook"
+)
+
+const (
+	GitHubEventHeader     = "X-GitHub-Event"
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+	GitLabEventHeader     = "X-Gitlab-Event"
+	GitLabTokenHeader     = "X-Gitlab-Token"
+
+	gitHubPullRequestEvent  = "pull_request"
+	gitLabMergeRequestEvent = "Merge Request Hook"
+)
+
+// VerifyGitHub reports whether signature is the GitHub signature of the body.
+// Nothing verifies without a secret.
+func VerifyGitHub(secret string, body []byte, signature string) bool {
+	return secret != "" && webhook.Verify(secret, body, signature)
+}
+
+// VerifyGitLab reports whether token is the GitLab secret token. Nothing
+// verifies without a secret.
+func VerifyGitLab(secret string, token string) bool {
+	return secret != "" && subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
+
+type gitHubUser struct {
+	Login string `json:"login"`
+}
+
+type gitHubPayload struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int        `json:"number"`
+		Title  string     `json:"title"`
+		Draft  bool       `json:"draft"`
+		Merged bool       `json:"merged"`
+		User   gitHubUser `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender gitHubUser `json:"sender"`
+}
+
+// ParseGitHub reads a GitHub webhook payload of the event named by
+// X-GitHub-Event. ok is false if the event is not relevant.
+func ParseGitHub(eventName string, body []byte) (event domain.ProviderPullRequestEvent, ok bool, err error) {
+	if eventName != gitHubPullRequestEvent {
+		return domain.ProviderPullRequestEvent{}, false, nil
+	}
+
+	var payload gitHubPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return domain.ProviderPullRequestEvent{}, false, err
+	}
+	if payload.Repository.FullName == "" || payload.PullRequest.Number == 0 {
+		return domain.ProviderPullRequestEvent{}, false, errors.New("pull_request payload without repository or number")
+	}
+
+	event = domain.ProviderPullRequestEvent{
+		Provider:      domain.ProviderGitHub,
+		PullRequestID: fmt.Sprintf("github:%s#%d", payload.Repository.FullName, payload.PullRequest.Number),
+		Title:         payload.PullRequest.Title,
+		Draft:         payload.PullRequest.Draft,
+		AuthorLogin:   payload.PullRequest.User.Login,
+		SenderLogin:   payload.Sender.Login,
+	}
+	switch payload.Action {
+	case "opened":
+		event.Action = domain.ProviderOpened
+	case "ready_for_review":
+		event.Action = domain.ProviderReadyForReview
+	case "reopened":
+		event.Action = domain.ProviderReopened
+	case "closed":
+		event.Action = domain.ProviderClosed
+		if payload.PullRequest.Merged {
+			event.Action = domain.ProviderMerged
+		}
+	default:
+		return domain.ProviderPullRequestEvent{}, false, nil
+	}
+	return event, true, nil
+}
+
+type gitLabPayload struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Action string `json:"action"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+// ParseGitLab reads a GitLab webhook payload of the event named by
+// X-Gitlab-Event. ok is false if the event is not relevant.
+//
+// Merge request payloads name the user who caused the event but not the
+// author, so the author of an opened merge request is taken to be the user
+// who opened it.
+func ParseGitLab(eventName string, body []byte) (event domain.ProviderPullRequestEvent, ok bool, err error) {
+	if eventName != gitLabMergeRequestEvent {
+		return domain.ProviderPullRequestEvent{}, false, nil
+	}
+
+	var payload gitLabPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return domain.ProviderPullRequestEvent{}, false, err
+	}
+	if payload.ObjectKind != "merge_request" || payload.Project.PathWithNamespace == "" || payload.ObjectAttributes.IID == 0 {
+		return domain.ProviderPullRequestEvent{}, false, errors.New("merge_request payload without project or iid")
+	}
+
+	event = domain.ProviderPullRequestEvent{
+		Provider:      domain.ProviderGitLab,
+		PullRequestID: fmt.Sprintf("gitlab:%s!%d", payload.Project.PathWithNamespace, payload.ObjectAttributes.IID),
+		Title:         payload.ObjectAttributes.Title,
+		Draft:         payload.ObjectAttributes.Draft,
+		SenderLogin:   payload.User.Username,
+	}
+	switch payload.ObjectAttributes.Action {
+	case "open":
+		event.Action = domain.ProviderOpened
+		event.AuthorLogin = payload.User.Username
+	case "reopen":
+		event.Action = domain.ProviderReopened
+	case "close":
+		event.Action = domain.ProviderClosed
+	case "merge":
+		event.Action = domain.ProviderMerged
+	case "update":
+		draft := payload.Changes.Draft
+		if draft == nil || !draft.Previous || draft.Current {
+			return domain.ProviderPullRequestEvent{}, false, nil
+		}
+		event.Action = domain.ProviderReadyForReview
+	default:
+		return domain.ProviderPullRequestEvent{}, false, nil
+	}
+	return event, true, nil
+}
@@ -0,0 +1,178 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+	"github.com/zemld/pr-manager/pr-manager/internal/webhook"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return body
+}
+
+func TestVerify(t *testing.T) {
+	body := readFixture(t, "github_pull_request_opened.json")
+	signature := webhook.Sign("secret", body)
+
+	if !VerifyGitHub("secret", body, signature) {
+		t.Error("expected the GitHub signature to verify")
+	}
+	if VerifyGitHub("other", body, signature) {
+		t.Error("expected the GitHub signature not to verify with another secret")
+	}
+	if VerifyGitHub("", body, webhook.Sign("", body)) {
+		t.Error("expected nothing to verify without a GitHub secret")
+	}
+	if !VerifyGitLab("token", "token") || VerifyGitLab("token", "other") || VerifyGitLab("", "") {
+		t.Error("expected only the GitLab secret token to verify")
+	}
+}
+
+func TestParseGitHub(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   string
+		fixture string
+		want    domain.ProviderPullRequestEvent
+		wantOK  bool
+	}{
+		{
+			name:    "opened",
+			event:   "pull_request",
+			fixture: "github_pull_request_opened.json",
+			want: domain.ProviderPullRequestEvent{
+				Provider:      domain.ProviderGitHub,
+				Action:        domain.ProviderOpened,
+				PullRequestID: "github:octo-org/search#42",
+				Title:         "Add fuzzy search",
+				AuthorLogin:   "OctoCat",
+				SenderLogin:   "OctoCat",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "closed and merged",
+			event:   "pull_request",
+			fixture: "github_pull_request_merged.json",
+			want: domain.ProviderPullRequestEvent{
+				Provider:      domain.ProviderGitHub,
+				Action:        domain.ProviderMerged,
+				PullRequestID: "github:octo-org/search#42",
+				Title:         "Add fuzzy search",
+				AuthorLogin:   "OctoCat",
+				SenderLogin:   "hubot",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "closed without merge",
+			event:   "pull_request",
+			fixture: "github_pull_request_closed.json",
+			want: domain.ProviderPullRequestEvent{
+				Provider:      domain.ProviderGitHub,
+				Action:        domain.ProviderClosed,
+				PullRequestID: "github:octo-org/search#42",
+				Title:         "Add fuzzy search",
+				AuthorLogin:   "OctoCat",
+				SenderLogin:   "OctoCat",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "ping is not relevant",
+			event:   "ping",
+			fixture: "github_ping.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := ParseGitHub(tt.event, readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("expected %+v (%v), got %+v (%v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestParseGitLab(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    domain.ProviderPullRequestEvent
+		wantOK  bool
+	}{
+		{
+			name:    "open",
+			fixture: "gitlab_merge_request_open.json",
+			want: domain.ProviderPullRequestEvent{
+				Provider:      domain.ProviderGitLab,
+				Action:        domain.ProviderOpened,
+				PullRequestID: "gitlab:platform/billing!7",
+				Title:         "Fix invoice rounding",
+				Draft:         true,
+				AuthorLogin:   "jdoe",
+				SenderLogin:   "jdoe",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "draft marked ready",
+			fixture: "gitlab_merge_request_ready.json",
+			want: domain.ProviderPullRequestEvent{
+				Provider:      domain.ProviderGitLab,
+				Action:        domain.ProviderReadyForReview,
+				PullRequestID: "gitlab:platform/billing!7",
+				Title:         "Fix invoice rounding",
+				SenderLogin:   "jdoe",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "merge",
+			fixture: "gitlab_merge_request_merge.json",
+			want: domain.ProviderPullRequestEvent{
+				Provider:      domain.ProviderGitLab,
+				Action:        domain.ProviderMerged,
+				PullRequestID: "gitlab:platform/billing!7",
+				Title:         "Fix invoice rounding",
+				SenderLogin:   "mmustermann",
+			},
+			wantOK: true,
+		},
+		{
+			name:    "other update is not relevant",
+			fixture: "gitlab_merge_request_update.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := ParseGitLab("Merge Request Hook", readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("expected %+v (%v), got %+v (%v)", tt.want, tt.wantOK, got, ok)
+			}
+		})
+	}
+
+	if _, ok, err := ParseGitLab("Push Hook", []byte(`{"object_kind":"push"}`)); ok || err != nil {
+		t.Errorf("expected push events not to be relevant, got %v, %v", ok, err)
+	}
+	if _, _, err := ParseGitLab("Merge Request Hook", []byte(`{"object_kind":"merge_request"}`)); err == nil {
+		t.Error("expected an error for a payload without project")
+	}
+}
@@ -0,0 +1,211 @@
+// Package outbox publishes the events written to the outbox.
+//
+// Events are written in the transaction of the change they report, so an
+// event exists if and only if the change does. A Relay claims them in batches
+// and hands each to every sink. An event is published once all sinks accepted
+// it and is retried as a whole otherwise, or if the relay stopped before it
+// knew, so sinks receive events at least once. The event ID is kept across
+// retries for receivers to drop duplicates.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+// Sink is where events are published to. Publish may be called again for an
+// event it accepted before.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event domain.WebhookEvent) error
+}
+
+// Store keeps the outbox. A claimed message is not claimed again for lease,
+// so it is retried if it is neither marked published nor failed in time.
+// DeletePublished deletes messages published at least retention ago.
+type Store interface {
+	Claim(ctx context.Context, limit int, maxAttempts int, lease time.Duration) ([]domain.OutboxMessage, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string, retryAfter time.Duration) error
+	DeletePublished(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type Relay struct {
+	Store Store
+	Sinks []Sink
+	// BatchSize is how many messages are claimed at once.
+	BatchSize int
+	// MaxAttempts is how many times a message is published before giving
+	// up. Messages given up on stay in the outbox.
+	MaxAttempts int
+	// Backoff is the wait before the second attempt, doubled before each
+	// following one.
+	Backoff time.Duration
+	// Lease is how long a claimed batch is reserved for the relay. It has to
+	// outlast publishing the whole batch.
+	Lease time.Duration
+	// Retention is how long published messages are kept. Zero keeps them
+	// forever.
+	Retention time.Duration
+	// CleanupInterval is how often Run deletes messages kept past Retention.
+	CleanupInterval time.Duration
+}
+
+func NewRelay(store Store, sinks ...Sink) *Relay {
+	return &Relay{
+		Store:           store,
+		Sinks:           sinks,
+		BatchSize:       20,
+		MaxAttempts:     5,
+		Backoff:         time.Second,
+		Lease:           5 * time.Minute,
+		Retention:       7 * 24 * time.Hour,
+		CleanupInterval: time.Hour,
+	}
+}
+
+// Run drains the outbox every interval until ctx is done, deleting messages
+// kept past Retention every CleanupInterval.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var lastCleanup time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Drain(ctx); err != nil {
+				log.Printf("Failed to relay outbox: %v\n", err)
+			}
+			if time.Since(lastCleanup) < r.CleanupInterval {
+				continue
+			}
+			lastCleanup = time.Now()
+			if _, err := r.Cleanup(ctx); err != nil {
+				log.Printf("Failed to clean up outbox: %v\n", err)
+			}
+		}
+	}
+}
+
+// Cleanup deletes messages published at least Retention ago and returns how
+// many were deleted.
+func (r *Relay) Cleanup(ctx context.Context) (int64, error) {
+	if r.Retention <= 0 {
+		return 0, nil
+	}
+	return r.Store.DeletePublished(ctx, r.Retention)
+}
+
+// Drain relays batches until no due messages are left and returns how many
+// messages were handled, published or not.
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := r.RelayBatch(ctx)
+		total += n
+		if err != nil || n == 0 || n < r.BatchSize {
+			return total, err
+		}
+	}
+}
+
+// RelayBatch publishes one batch of due messages in the order they were
+// written and returns its size.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	messages, err := r.Store.Claim(ctx, r.BatchSize, r.MaxAttempts, r.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, message := range messages {
+		if err := r.publish(ctx, message.Event); err != nil {
+			if message.Attempts+1 >= r.MaxAttempts {
+				log.Printf("Giving up on event %s after %d attempts: %v\n", message.Event.ID, message.Attempts+1, err)
+			}
+			err = r.Store.MarkFailed(ctx, message.ID, err.Error(), r.Backoff<<message.Attempts)
+			if err != nil {
+				return len(messages), err
+			}
+			continue
+		}
+		if err := r.Store.MarkPublished(ctx, message.ID); err != nil {
+			return len(messages), err
+		}
+	}
+	return len(messages), nil
+}
+
+func (r *Relay) publish(ctx context.Context, event domain.WebhookEvent) error {
+	var errs []error
+	for _, sink := range r.Sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogSink writes events to the standard logger.
+type LogSink struct{}
+
+func (LogSink) Name() string {
+	return "log"
+}
+
+func (LogSink) Publish(ctx context.Context, event domain.WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	log.Printf("Event %s: %s\n", event.Type, body)
+	return nil
+}
+
+// FileSink appends events to the file at Path as JSON lines. An event is
+// accepted once it is synced to disk.
+type FileSink struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{Path: path}
+}
+
+func (s *FileSink) Name() string {
+	return "file"
+}
+
+func (s *FileSink) Publish(ctx context.Context, event domain.WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(body, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,195 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+type fakeStore struct {
+	pending   []domain.OutboxMessage
+	published []int64
+	retries   map[int64]time.Duration
+	cleanups  []time.Duration
+}
+
+func newFakeStore(messages ...domain.OutboxMessage) *fakeStore {
+	return &fakeStore{pending: messages, retries: make(map[int64]time.Duration)}
+}
+
+func (s *fakeStore) Claim(ctx context.Context, limit int, maxAttempts int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	var claimed []domain.OutboxMessage
+	s.pending = slices.DeleteFunc(s.pending, func(message domain.OutboxMessage) bool {
+		if len(claimed) >= limit || message.Attempts >= maxAttempts {
+			return false
+		}
+		claimed = append(claimed, message)
+		return true
+	})
+	return claimed, nil
+}
+
+func (s *fakeStore) MarkPublished(ctx context.Context, id int64) error {
+	s.published = append(s.published, id)
+	return nil
+}
+
+func (s *fakeStore) MarkFailed(ctx context.Context, id int64, reason string, retryAfter time.Duration) error {
+	s.retries[id] = retryAfter
+	return nil
+}
+
+// DeletePublished deletes every published message regardless of retention.
+func (s *fakeStore) DeletePublished(ctx context.Context, retention time.Duration) (int64, error) {
+	s.cleanups = append(s.cleanups, retention)
+	deleted := int64(len(s.published))
+	s.published = nil
+	return deleted, nil
+}
+
+type fakeSink struct {
+	events []string
+	reject map[string]bool
+}
+
+func (s *fakeSink) Name() string {
+	return "fake"
+}
+
+func (s *fakeSink) Publish(ctx context.Context, event domain.WebhookEvent) error {
+	s.events = append(s.events, event.ID)
+	if s.reject[event.ID] {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+func message(id int64, attempts int) domain.OutboxMessage {
+	return domain.OutboxMessage{
+		ID:       id,
+		Event:    domain.WebhookEvent{ID: fmt.Sprintf("event-%d", id), Type: domain.EventPullRequestCreated},
+		Attempts: attempts,
+	}
+}
+
+func TestRelay_RelayBatch(t *testing.T) {
+	store := newFakeStore(message(1, 0), message(2, 0), message(3, 2))
+	accepting := &fakeSink{}
+	rejecting := &fakeSink{reject: map[string]bool{"event-2": true, "event-3": true}}
+	relay := NewRelay(store, accepting, rejecting)
+	relay.Backoff = time.Second
+
+	n, err := relay.RelayBatch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 messages, got %d", n)
+	}
+	if !slices.Equal(accepting.events, []string{"event-1", "event-2", "event-3"}) {
+		t.Errorf("expected every event to reach every sink in order, got %v", accepting.events)
+	}
+	if !slices.Equal(store.published, []int64{1}) {
+		t.Errorf("expected only message 1 to be published, got %v", store.published)
+	}
+	if store.retries[2] != time.Second || store.retries[3] != 4*time.Second {
+		t.Errorf("expected retries after 1s and 4s, got %v", store.retries)
+	}
+}
+
+func TestRelay_Drain(t *testing.T) {
+	store := newFakeStore(message(1, 0), message(2, 0), message(3, 0), message(4, 0), message(5, 5))
+	sink := &fakeSink{}
+	relay := NewRelay(store, sink)
+	relay.BatchSize = 2
+
+	n, err := relay.Drain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 4 {
+		t.Errorf("expected 4 messages, got %d", n)
+	}
+	if !slices.Equal(store.published, []int64{1, 2, 3, 4}) {
+		t.Errorf("expected messages 1-4 to be published, got %v", store.published)
+	}
+	if len(store.pending) != 1 {
+		t.Errorf("expected the message given up on to stay, got %v", store.pending)
+	}
+}
+
+func TestRelay_Run(t *testing.T) {
+	store := newFakeStore(message(1, 0), message(2, 0))
+	relay := NewRelay(store, &fakeSink{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	relay.Run(ctx, time.Millisecond)
+
+	if len(store.pending) != 0 {
+		t.Errorf("expected the outbox to be drained, got %v", store.pending)
+	}
+	if !slices.Equal(store.cleanups, []time.Duration{relay.Retention}) {
+		t.Errorf("expected one cleanup within the cleanup interval, got %v", store.cleanups)
+	}
+	if len(store.published) != 0 {
+		t.Errorf("expected published messages to be deleted, got %v", store.published)
+	}
+}
+
+func TestRelay_CleanupWithoutRetention(t *testing.T) {
+	store := newFakeStore()
+	store.published = []int64{1}
+	relay := NewRelay(store)
+	relay.Retention = 0
+
+	n, err := relay.Cleanup(context.Background())
+	if err != nil || n != 0 {
+		t.Fatalf("expected nothing to be deleted, got %d, %v", n, err)
+	}
+	if len(store.cleanups) != 0 || len(store.published) != 1 {
+		t.Errorf("expected published messages to be kept, got %v", store.published)
+	}
+}
+
+func TestFileSink_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := NewFileSink(path)
+	for _, id := range []string{"event-1", "event-2"} {
+		event := domain.WebhookEvent{ID: id, Type: domain.EventTeamDeleted, Data: map[string]any{"team_name": "backend"}}
+		if err := sink.Publish(context.Background(), event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer file.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event domain.WebhookEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("unexpected line %q: %v", scanner.Text(), err)
+		}
+		if event.Type != domain.EventTeamDeleted || event.Data["team_name"] != "backend" {
+			t.Errorf("unexpected event %+v", event)
+		}
+		ids = append(ids, event.ID)
+	}
+	if !slices.Equal(ids, []string{"event-1", "event-2"}) {
+		t.Errorf("expected both events in order, got %v", ids)
+	}
+}
@@ -0,0 +1,132 @@
+// Package webhook posts events to subscribed webhooks.
+//
+// Every request carries the event as JSON with these headers:
+//
+//	X-Webhook-Event      event type, e.g. pr.merged
+//	X-Webhook-Delivery   event ID, the same for every attempt
+//	X-Webhook-Signature  sha256=<hex HMAC-SHA256 of the body keyed by the secret>
+//
+// A delivery succeeds on a 2xx response. Retrying failed deliveries is up to
+// the caller.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	SignatureHeader = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the value of SignatureHeader for the body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the value of SignatureHeader for the
+// body.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// ErrPrivateTarget is returned when a webhook would be posted to a
+// non-public address that is not allowed.
+var ErrPrivateTarget = errors.New("webhook target address is not public")
+
+type Dispatcher struct {
+	Client *http.Client
+}
+
+// NewDispatcher returns a dispatcher that connects to non-public addresses
+// only while allowPrivate returns true. The address is checked after the host
+// name is resolved, so names resolving to internal hosts are refused too.
+func NewDispatcher(allowPrivate func() bool) *Dispatcher {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if allowPrivate() {
+				return nil
+			}
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !domain.PublicAddress(addrPort.Addr()) {
+				return ErrPrivateTarget
+			}
+			return nil
+		},
+	}
+	return &Dispatcher{
+		Client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+		},
+	}
+}
+
+// Deliver posts the event to the subscription once and returns the record of
+// the attempt. Its Error is set unless the webhook accepted the event.
+func (d *Dispatcher) Deliver(ctx context.Context, subscription domain.WebhookSubscription, event domain.WebhookEvent, attempt int) domain.WebhookDelivery {
+	delivery := domain.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Attempt:        attempt,
+	}
+
+	statusCode, err := d.post(ctx, subscription, event)
+	if statusCode != 0 {
+		delivery.StatusCode = &statusCode
+	}
+	if err != nil {
+		message := err.Error()
+		delivery.Error = &message
+	}
+	return delivery
+}
+
+// post returns the response status, zero if there was no response.
+func (d *Dispatcher) post(ctx context.Context, subscription domain.WebhookSubscription, event domain.WebhookEvent) (int, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(event.Type))
+	req.Header.Set(DeliveryHeader, event.ID)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
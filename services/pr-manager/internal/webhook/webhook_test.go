@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zemld/pr-manager/pr-manager/internal/domain"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := Sign("secret", body)
+	if !Verify("secret", body, signature) {
+		t.Errorf("expected signature %q to verify", signature)
+	}
+	if Verify("other", body, signature) {
+		t.Error("expected signature not to verify with another secret")
+	}
+	if Verify("secret", []byte(`{"id":"2"}`), signature) {
+		t.Error("expected signature not to verify for another body")
+	}
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantFailed bool
+	}{
+		{name: "accepted", status: http.StatusNoContent},
+		{name: "rejected", status: http.StatusInternalServerError, wantFailed: true},
+		{name: "redirect is not accepted", status: http.StatusNotModified, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if !Verify("secret", body, r.Header.Get(SignatureHeader)) {
+					t.Errorf("unexpected signature %q", r.Header.Get(SignatureHeader))
+				}
+				if r.Header.Get(EventHeader) != string(domain.EventPullRequestMerged) || r.Header.Get(DeliveryHeader) != "event-1" {
+					t.Errorf("unexpected headers %v", r.Header)
+				}
+				var event domain.WebhookEvent
+				if err := json.Unmarshal(body, &event); err != nil || event.ID != "event-1" {
+					t.Errorf("unexpected payload %s: %v", body, err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			dispatcher := &Dispatcher{Client: server.Client()}
+			subscription := domain.WebhookSubscription{ID: 1, URL: server.URL, Secret: "secret"}
+			event := domain.WebhookEvent{ID: "event-1", Type: domain.EventPullRequestMerged, Data: map[string]any{"pull_request_id": "pr1"}}
+
+			delivery := dispatcher.Deliver(context.Background(), subscription, event, 3)
+			if delivery.Attempt != 3 || delivery.EventID != "event-1" || delivery.EventType != domain.EventPullRequestMerged || delivery.SubscriptionID != 1 {
+				t.Errorf("unexpected delivery %+v", delivery)
+			}
+			if delivery.StatusCode == nil || *delivery.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %v", tt.status, delivery.StatusCode)
+			}
+			if (delivery.Error != nil) != tt.wantFailed {
+				t.Errorf("expected failure %v, got error %v", tt.wantFailed, delivery.Error)
+			}
+		})
+	}
+}
+
+func TestDispatcher_DeliverUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	dispatcher := &Dispatcher{Client: http.DefaultClient}
+	delivery := dispatcher.Deliver(context.Background(), domain.WebhookSubscription{ID: 1, URL: url, Secret: "secret"}, domain.WebhookEvent{ID: "event-1"}, 1)
+	if delivery.StatusCode != nil || delivery.Error == nil {
+		t.Errorf("expected an attempt without response, got %+v", delivery)
+	}
+}
+
+func TestNewDispatcher_PrivateTarget(t *testing.T) {
+	received := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer server.Close()
+	subscription := domain.WebhookSubscription{ID: 1, URL: server.URL, Secret: "secret"}
+
+	allowPrivate := false
+	dispatcher := NewDispatcher(func() bool { return allowPrivate })
+	delivery := dispatcher.Deliver(context.Background(), subscription, domain.WebhookEvent{ID: "event-1"}, 1)
+	if delivery.StatusCode != nil || delivery.Error == nil || received != 0 {
+		t.Errorf("expected the loopback target to be refused, got %+v", delivery)
+	}
+
+	allowPrivate = true
+	delivery = dispatcher.Deliver(context.Background(), subscription, domain.WebhookEvent{ID: "event-1"}, 2)
+	if delivery.StatusCode == nil || *delivery.StatusCode != http.StatusOK || received != 1 {
+		t.Errorf("expected the delivery to succeed once allowed, got %+v", delivery)
+	}
+}